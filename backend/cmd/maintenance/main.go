@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"restaurant-menu-api/internal/config"
+	"restaurant-menu-api/internal/database"
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/services"
+	databaseRepo "restaurant-menu-api/internal/infrastructure/database"
+	"restaurant-menu-api/pkg/logger"
+)
+
+func main() {
+	command := flag.String("command", "", "Maintenance command: compact-order, regenerate-slugs, create-admin-key")
+	name := flag.String("name", "Admin", "Name of the key create-admin-key issues")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	switch *command {
+	case "compact-order":
+		compactors := []struct {
+			name    string
+			compact func(context.Context) (int64, error)
+		}{
+			{"categories", databaseRepo.NewCategoryRepository(db.DB).CompactDisplayOrder},
+			{"subcategories", databaseRepo.NewSubCategoryRepository(db.DB).CompactDisplayOrder},
+			{"items", databaseRepo.NewItemRepository(db.DB).CompactDisplayOrder},
+		}
+
+		for _, c := range compactors {
+			updated, err := c.compact(ctx)
+			if err != nil {
+				log.Fatalf("Failed to compact %s display order: %v", c.name, err)
+			}
+			fmt.Printf("Compacted %s display order: %d rows updated\n", c.name, updated)
+		}
+
+	case "regenerate-slugs":
+		regenerators := []struct {
+			name       string
+			regenerate func(context.Context) (int64, error)
+		}{
+			{"categories", databaseRepo.NewCategoryRepository(db.DB).RegenerateFallbackSlugs},
+			{"subcategories", databaseRepo.NewSubCategoryRepository(db.DB).RegenerateFallbackSlugs},
+			{"items", databaseRepo.NewItemRepository(db.DB).RegenerateFallbackSlugs},
+		}
+
+		for _, r := range regenerators {
+			updated, err := r.regenerate(ctx)
+			if err != nil {
+				log.Fatalf("Failed to regenerate %s slugs: %v", r.name, err)
+			}
+			fmt.Printf("Regenerated %s slugs: %d rows updated\n", r.name, updated)
+		}
+
+	case "create-admin-key":
+		appLogger := logger.New(cfg.Logger.Level, cfg.Logger.Format)
+		apiKeyService := services.NewAPIKeyService(databaseRepo.NewAPIKeyRepository(db.DB), nil, appLogger)
+		defer apiKeyService.Close()
+
+		created, err := apiKeyService.Create(ctx, &entities.APIKey{
+			Name:  *name,
+			Scope: entities.APIKeyScopeAdmin,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create admin API key: %v", err)
+		}
+		fmt.Printf("Created admin API key %d (%s)\n", created.ID, created.Name)
+		fmt.Println("Send it in the X-API-Key header; it is not shown again:")
+		fmt.Println(created.Key)
+
+	default:
+		fmt.Printf("Unknown command: %s\n", *command)
+		fmt.Println("Available commands: compact-order, regenerate-slugs, create-admin-key")
+		os.Exit(1)
+	}
+}
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Maintenance utility for restaurant-menu-api\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  compact-order - Renumber display_order as 1..n within each parent, removing gaps and duplicates\n")
+		fmt.Fprintf(os.Stderr, "  regenerate-slugs - Derive slugs for rows migration 000004 gave numbered slugs, such as item-12, keeping the old ones as redirects\n")
+		fmt.Fprintf(os.Stderr, "  create-admin-key - Issue an admin-scoped API key, needed to manage keys, read analytics and edit the search dictionary, and print it once\n\n")
+		fmt.Fprintf(os.Stderr, "Examples:\n")
+		fmt.Fprintf(os.Stderr, "  %s -command=compact-order\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -command=regenerate-slugs\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -command=create-admin-key -name=\"Back office\"\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flag.PrintDefaults()
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"restaurant-menu-api/internal/database"
 	"restaurant-menu-api/internal/database/migrations"
 	"restaurant-menu-api/internal/infrastructure/aws"
+	"restaurant-menu-api/internal/infrastructure/cache"
 	"restaurant-menu-api/internal/infrastructure/redis"
 	"restaurant-menu-api/internal/infrastructure/web"
 	"restaurant-menu-api/pkg/logger"
@@ -57,11 +58,11 @@ func main() {
 	// Run database migrations (only in development mode)
 	// In production, use proper migrations: make db-migrate
 	if cfg.IsDevelopment() {
-		if err := db.AutoMigrate(); err != nil {
-			appLogger.WithError(err).Fatal("Failed to run database auto-migration")
+		if err := database.RunMigrations(cfg); err != nil {
+			appLogger.WithError(err).Fatal("Failed to run database migrations")
 		}
-		appLogger.Info("Database auto-migration completed successfully")
-		appLogger.Warn("Auto-migration is enabled in development mode. Use 'make db-migrate' for production")
+		appLogger.Info("Database migrations completed successfully")
+		appLogger.Warn("Migrations run on startup in development mode. Use 'make db-migrate' for production")
 	} else {
 		appLogger.Info("Production mode: Use 'make db-migrate' to run database migrations")
 	}
@@ -86,18 +87,22 @@ func main() {
 	var redisClient *redis.Client
 	redisClient, err = redis.NewClient(&cfg.Redis, appLogger)
 	if err != nil {
-		appLogger.WithError(err).Warn("Failed to initialize Redis client, continuing without caching")
+		appLogger.WithError(err).Warn("Failed to initialize Redis client, continuing without Redis")
 		redisClient = nil
 	} else {
 		appLogger.Info("Redis client initialized successfully")
 	}
 
+	// Initialize cache, in Redis or in memory as configured
+	appCache := cache.New(&cfg.Cache, redisClient, appLogger)
+
 	// Initialize web server
 	server := web.NewServer(&web.ServerConfig{
 		Config:      cfg,
 		DB:          db,
 		S3Client:    s3Client,
 		RedisClient: redisClient,
+		Cache:       appCache,
 		Logger:      appLogger,
 	})
 
@@ -134,4 +139,7 @@ func main() {
 	} else {
 		appLogger.Info("Server shutdown complete")
 	}
+
+	// Stop background jobs once no more requests come in
+	server.Shutdown()
 }
@@ -23,6 +23,11 @@ package docs
 // @in header
 // @name Authorization
 
+// @securityDefinitions.apikey AdminAPIKey
+// @in header
+// @name X-API-Key
+// @description An admin-scoped API key, needed to manage keys, read analytics and edit the search dictionary
+
 // @tag.name Health
 // @tag.description Health check endpoints
 
@@ -3,18 +3,25 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	AWS      AWSConfig
-	Redis    RedisConfig
-	Logger   LoggerConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	AWS         AWSConfig
+	Redis       RedisConfig
+	Logger      LoggerConfig
+	Search      SearchConfig
+	Cache       CacheConfig
+	Compression CompressionConfig
+	Snapshot    SnapshotConfig
+	RateLimit   RateLimitConfig
 }
 
 type ServerConfig struct {
@@ -23,6 +30,9 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	Host         string
+	// TrustedProxies are the addresses or CIDR ranges whose X-Forwarded-For
+	// and X-Real-IP headers are believed when telling clients apart
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -55,6 +65,47 @@ type LoggerConfig struct {
 	Format string
 }
 
+type SearchConfig struct {
+	// SimilarityThreshold is the minimum trigram word similarity (0-1) for a
+	// fuzzy match or "did you mean" suggestion
+	SimilarityThreshold float64
+}
+
+// CacheConfig holds where cached results are kept and how long built menus
+// stay cached.
+type CacheConfig struct {
+	// Driver is "redis", falling back to memory when Redis is unreachable at
+	// startup, or "memory"
+	Driver string
+	// MemoryMaxEntries and MemoryMaxBytes bound the in-memory cache, which
+	// drops the least recently used entries beyond them
+	MemoryMaxEntries int
+	MemoryMaxBytes   int
+	MenuTTL          time.Duration
+	CategoryMenuTTL  time.Duration
+	FeaturedTTL      time.Duration
+	// LockTimeout bounds how long other requests wait for one request to
+	// rebuild an expired menu
+	LockTimeout time.Duration
+	// TagPruneInterval is how often keys that expired are dropped from the
+	// Redis tag sets
+	TagPruneInterval time.Duration
+}
+
+// CompressionConfig holds when responses are gzip or brotli encoded.
+type CompressionConfig struct {
+	// MinSize is the smallest body, in bytes, worth compressing
+	MinSize int
+}
+
+// SnapshotConfig holds where the last good copy of the public menu is kept
+// for serving while the database is down.
+type SnapshotConfig struct {
+	// Dir holds snapshot files next to the copy in the cache; empty keeps none
+	Dir          string
+	SaveInterval time.Duration
+}
+
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		// .env file is optional in production
@@ -68,6 +119,8 @@ func Load() (*Config, error) {
 			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			// Clients' addresses are taken as they connect unless proxies are listed
+			TrustedProxies: getListEnv("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -95,8 +148,36 @@ func Load() (*Config, error) {
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Search: SearchConfig{
+			SimilarityThreshold: getFloatEnv("SEARCH_SIMILARITY_THRESHOLD", 0.3),
+		},
+		Cache: CacheConfig{
+			Driver:           getEnv("CACHE_DRIVER", "redis"),
+			MemoryMaxEntries: getIntEnv("CACHE_MEMORY_MAX_ENTRIES", 10000),
+			MemoryMaxBytes:   getIntEnv("CACHE_MEMORY_MAX_BYTES", 64<<20),
+			MenuTTL:          getDurationEnv("CACHE_MENU_TTL", 10*time.Minute),
+			CategoryMenuTTL:  getDurationEnv("CACHE_CATEGORY_MENU_TTL", 10*time.Minute),
+			FeaturedTTL:      getDurationEnv("CACHE_FEATURED_TTL", 5*time.Minute),
+			LockTimeout:      getDurationEnv("CACHE_LOCK_TIMEOUT", 5*time.Second),
+			TagPruneInterval: getDurationEnv("CACHE_TAG_PRUNE_INTERVAL", time.Hour),
+		},
+		Compression: CompressionConfig{
+			MinSize: getIntEnv("COMPRESSION_MIN_SIZE", 1024),
+		},
+		Snapshot: SnapshotConfig{
+			Dir:          getEnv("SNAPSHOT_DIR", filepath.Join(os.TempDir(), "restaurant-menu-snapshots")),
+			SaveInterval: getDurationEnv("SNAPSHOT_SAVE_INTERVAL", time.Minute),
+		},
 	}
 
+	rateLimit, err := loadRateLimitConfig(getEnv("RATE_LIMIT_RULES_FILE", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.RateLimit = rateLimit
+	cfg.RateLimit.MemoryMaxClients = getIntEnv("RATE_LIMIT_MEMORY_MAX_CLIENTS", 100000)
+	cfg.RateLimit.MemorySweepInterval = getDurationEnv("RATE_LIMIT_MEMORY_SWEEP_INTERVAL", time.Minute)
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -124,6 +205,38 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid environment: %s", c.Server.Environment)
 	}
 
+	if c.Search.SimilarityThreshold <= 0 || c.Search.SimilarityThreshold > 1 {
+		return fmt.Errorf("search similarity threshold must be between 0 and 1")
+	}
+
+	if c.Cache.MenuTTL <= 0 || c.Cache.CategoryMenuTTL <= 0 || c.Cache.FeaturedTTL <= 0 || c.Cache.LockTimeout <= 0 {
+		return fmt.Errorf("cache TTLs and lock timeout must be positive")
+	}
+
+	if c.Cache.TagPruneInterval <= 0 {
+		return fmt.Errorf("cache tag prune interval must be positive")
+	}
+
+	if c.Cache.Driver != "redis" && c.Cache.Driver != "memory" {
+		return fmt.Errorf("invalid cache driver: %s", c.Cache.Driver)
+	}
+
+	if c.Cache.MemoryMaxEntries <= 0 || c.Cache.MemoryMaxBytes <= 0 {
+		return fmt.Errorf("in-memory cache limits must be positive")
+	}
+
+	if c.Compression.MinSize < 0 {
+		return fmt.Errorf("compression minimum size must not be negative")
+	}
+
+	if c.Snapshot.SaveInterval <= 0 {
+		return fmt.Errorf("snapshot save interval must be positive")
+	}
+
+	if err := c.RateLimit.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -162,6 +275,26 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
+// getListEnv splits a comma-separated value, dropping empty entries.
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
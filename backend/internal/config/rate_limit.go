@@ -0,0 +1,145 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RateLimitConfig holds the token buckets requests draw from. Each request
+// is counted against the first rule matching it, or the default rule.
+type RateLimitConfig struct {
+	// RulesFile is a JSON file replacing the built-in rules, holding a
+	// "default" rule and a list of "rules"
+	RulesFile string
+	Default   RateLimitRule
+	Rules     []RateLimitRule
+	// MemoryMaxClients bounds the buckets kept in memory while Redis is
+	// unavailable, and MemorySweepInterval is how often refilled ones are
+	// dropped
+	MemoryMaxClients    int
+	MemorySweepInterval time.Duration
+}
+
+// RateLimitRule lets each client make Limit requests per Period to the
+// matching routes, in bursts of up to Burst requests.
+//
+// Path is matched segment by segment against the request path; "*" or a
+// ":name" segment matches any one segment and a final "**" any number of
+// them, none included. Methods limits the rule to those HTTP methods, all
+// when empty. Rules with the same Name share their buckets.
+type RateLimitRule struct {
+	Name    string
+	Methods []string
+	Path    string
+	Limit   int
+	Period  time.Duration
+	// Burst defaults to Limit
+	Burst int
+}
+
+type rateLimitRuleJSON struct {
+	Name    string   `json:"name"`
+	Methods []string `json:"methods,omitempty"`
+	Path    string   `json:"path"`
+	Limit   int      `json:"limit"`
+	Period  string   `json:"period"`
+	Burst   int      `json:"burst,omitempty"`
+}
+
+// UnmarshalJSON reads the period as a duration string such as "1m".
+func (r *RateLimitRule) UnmarshalJSON(data []byte) error {
+	var raw rateLimitRuleJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	period, err := time.ParseDuration(raw.Period)
+	if err != nil {
+		return fmt.Errorf("rate limit rule %q: invalid period: %w", raw.Name, err)
+	}
+
+	*r = RateLimitRule{
+		Name:    raw.Name,
+		Methods: raw.Methods,
+		Path:    raw.Path,
+		Limit:   raw.Limit,
+		Period:  period,
+		Burst:   raw.Burst,
+	}
+	return nil
+}
+
+func defaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Default: RateLimitRule{Name: "default", Limit: 100, Period: time.Minute},
+		Rules: []RateLimitRule{
+			// Autocomplete runs on every keystroke, so it gets its own budget
+			{Name: "suggest", Methods: []string{"GET"}, Path: "/api/v1/menu/suggest", Limit: 300, Period: time.Minute},
+			{Name: "search", Methods: []string{"GET"}, Path: "/api/v1/menu/search", Limit: 50, Period: time.Minute},
+			{Name: "upload", Path: "/api/v1/upload/**", Limit: 10, Period: time.Minute},
+			{Name: "menu", Methods: []string{"GET"}, Path: "/api/v1/menu/**", Limit: 200, Period: time.Minute},
+			{Name: "delete", Methods: []string{"DELETE"}, Path: "/api/v1/**", Limit: 20, Period: time.Minute},
+			{Name: "write", Methods: []string{"POST", "PUT", "PATCH"}, Path: "/api/v1/**", Limit: 30, Period: time.Minute},
+		},
+	}
+}
+
+// loadRateLimitConfig returns the built-in rules, or those in path when set.
+func loadRateLimitConfig(path string) (RateLimitConfig, error) {
+	cfg := defaultRateLimitConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read rate limit rules: %w", err)
+	}
+
+	var file struct {
+		Default *RateLimitRule  `json:"default"`
+		Rules   []RateLimitRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return cfg, fmt.Errorf("failed to parse rate limit rules: %w", err)
+	}
+
+	if file.Default != nil {
+		cfg.Default = *file.Default
+	}
+	cfg.Rules = file.Rules
+	cfg.RulesFile = path
+	return cfg, nil
+}
+
+func (c *RateLimitConfig) validate() error {
+	if c.MemoryMaxClients <= 0 || c.MemorySweepInterval <= 0 {
+		return fmt.Errorf("in-memory rate limit client cap and sweep interval must be positive")
+	}
+
+	if err := c.Default.validate(false); err != nil {
+		return err
+	}
+	for _, rule := range c.Rules {
+		if err := rule.validate(true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RateLimitRule) validate(needsPath bool) error {
+	if r.Name == "" {
+		return fmt.Errorf("rate limit rules need a name")
+	}
+	if needsPath && !strings.HasPrefix(r.Path, "/") {
+		return fmt.Errorf("rate limit rule %q: path must start with /", r.Name)
+	}
+	if r.Limit <= 0 || r.Period <= 0 || r.Burst < 0 {
+		return fmt.Errorf("rate limit rule %q: limit and period must be positive and burst not negative", r.Name)
+	}
+	return nil
+}
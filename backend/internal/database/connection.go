@@ -9,7 +9,6 @@ import (
 	"gorm.io/gorm/logger"
 
 	"restaurant-menu-api/internal/config"
-	"restaurant-menu-api/internal/domain/entities"
 )
 
 type Database struct {
@@ -53,21 +52,6 @@ func New(cfg *config.Config) (*Database, error) {
 	return &Database{DB: db}, nil
 }
 
-// AutoMigrate is deprecated - use proper migrations instead
-// This method is kept for backwards compatibility but should not be used in production
-func (d *Database) AutoMigrate() error {
-	// In production, migrations should be run separately using the migrate command
-	// This is kept only for development convenience and backwards compatibility
-	return d.DB.AutoMigrate(
-		&entities.Category{},
-		&entities.SubCategory{},
-		&entities.Item{},
-		&entities.RestaurantInfo{},
-		&entities.OperatingHour{},
-		&entities.ContentSection{},
-	)
-}
-
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
 	if err != nil {
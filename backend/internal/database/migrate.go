@@ -69,6 +69,18 @@ func NewMigrationRunner(cfg *config.Config) (*MigrationRunner, error) {
 	}, nil
 }
 
+// RunMigrations applies the pending versioned migrations, the same ones
+// make db-migrate runs.
+func RunMigrations(cfg *config.Config) error {
+	runner, err := NewMigrationRunner(cfg)
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	return runner.Up()
+}
+
 // Up runs all available migrations
 func (mr *MigrationRunner) Up() error {
 	err := mr.migrate.Up()
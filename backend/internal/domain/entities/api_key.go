@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+type APIKeyScope string
+
+const (
+	// APIKeyScopeMenu only reads the public menu and restaurant information
+	APIKeyScopeMenu APIKeyScope = "menu"
+	// APIKeyScopeAdmin may also manage menu data
+	APIKeyScopeAdmin APIKeyScope = "admin"
+)
+
+// APIKey identifies a partner or screen calling the API. Only a hash of the
+// key is stored; Prefix, its first characters, tells keys apart in listings.
+//
+// RateLimit replaces the configured limits with that many requests per
+// minute, and DailyQuota caps the requests per UTC day; 0 leaves either off.
+// A key with AllowedOrigins is only accepted from browsers on those origins.
+type APIKey struct {
+	ID             uint        `json:"id" gorm:"primarykey"`
+	Name           string      `json:"name" gorm:"size:100;not null"`
+	Prefix         string      `json:"prefix" gorm:"size:20;not null"`
+	KeyHash        string      `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Scope          APIKeyScope `json:"scope" gorm:"size:20;not null;default:'menu'"`
+	AllowedOrigins StringList  `json:"allowed_origins" gorm:"type:jsonb;not null;default:'[]'"`
+	RateLimit      int         `json:"rate_limit" gorm:"not null;default:0"`
+	DailyQuota     int         `json:"daily_quota" gorm:"not null;default:0"`
+	LastUsedAt     *time.Time  `json:"last_used_at,omitempty"`
+	RevokedAt      *time.Time  `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+func (k *APIKey) TableName() string {
+	return "api_keys"
+}
+
+// AllowsOrigin reports whether a request from origin may use the key.
+func (k *APIKey) AllowsOrigin(origin string) bool {
+	if len(k.AllowedOrigins) == 0 {
+		return true
+	}
+
+	origin, ok := NormalizeOrigin(origin)
+	if !ok {
+		return false
+	}
+	for _, allowed := range k.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeOrigin reduces an http or https origin to lowercase
+// scheme://host[:port], reporting false for anything else.
+func NormalizeOrigin(origin string) (string, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || strings.Trim(parsed.Path, "/") != "" {
+		return "", false
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host), true
+}
+
+// CreatedAPIKey is a new key with the only copy of its secret.
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyUsage counts the requests a key made to one endpoint on one UTC day.
+// Route is the route pattern, such as /api/v1/menu/:categorySlug.
+type APIKeyUsage struct {
+	APIKeyID uint      `json:"api_key_id" gorm:"primaryKey;autoIncrement:false"`
+	Day      time.Time `json:"day" gorm:"primaryKey;type:date;index"`
+	Method   string    `json:"method" gorm:"primaryKey;size:10"`
+	Route    string    `json:"route" gorm:"primaryKey;size:255"`
+	Requests int64     `json:"requests" gorm:"not null;default:0"`
+
+	// Relationships
+	APIKey *APIKey `json:"-" gorm:"foreignKey:APIKeyID;constraint:OnDelete:CASCADE"`
+}
+
+func (u *APIKeyUsage) TableName() string {
+	return "api_key_usages"
+}
+
+// APIKeyUsageFilter limits a usage report to one key, when APIKeyID is set,
+// and to days in [From, To).
+type APIKeyUsageFilter struct {
+	APIKeyID uint      `json:"api_key_id,omitempty"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Limit    int       `json:"limit"`
+}
+
+// APIKeyUsageReport sums the requests a key made to one endpoint.
+type APIKeyUsageReport struct {
+	APIKeyID   uint       `json:"api_key_id"`
+	APIKeyName string     `json:"api_key_name"`
+	Method     string     `json:"method"`
+	Route      string     `json:"route"`
+	Requests   int64      `json:"requests"`
+	LastDay    *time.Time `json:"last_day,omitempty"`
+}
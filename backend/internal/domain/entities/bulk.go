@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"math"
+)
+
+type BulkOperationType string
+
+const (
+	BulkOpPriceChange     BulkOperationType = "price_change"
+	BulkOpSetAvailability BulkOperationType = "set_availability"
+	BulkOpMoveSubCategory BulkOperationType = "move_sub_category"
+	BulkOpSetCurrency     BulkOperationType = "set_currency"
+	BulkOpSetActive       BulkOperationType = "set_active"
+	BulkOpDelete          BulkOperationType = "delete"
+)
+
+type PriceChangeMode string
+
+const (
+	PriceChangePercentage PriceChangeMode = "percentage"
+	PriceChangeAbsolute   PriceChangeMode = "absolute"
+)
+
+type RoundingRule string
+
+const (
+	RoundingNone    RoundingRule = "none"
+	RoundingNearest RoundingRule = "nearest"
+	RoundingUp      RoundingRule = "up"
+	RoundingDown    RoundingRule = "down"
+)
+
+// BulkItemSelector picks the items a bulk operation applies to.
+// All non-empty criteria are combined with AND.
+type BulkItemSelector struct {
+	IDs            []uint      `json:"ids"`
+	SubCategoryIDs []uint      `json:"sub_category_ids"`
+	CategoryIDs    []uint      `json:"category_ids"`
+	Tags           []string    `json:"tags"` // dietary_info labels that must be true
+	Filter         *ItemFilter `json:"filter"`
+}
+
+func (s BulkItemSelector) IsEmpty() bool {
+	return len(s.IDs) == 0 &&
+		len(s.SubCategoryIDs) == 0 &&
+		len(s.CategoryIDs) == 0 &&
+		len(s.Tags) == 0 &&
+		s.Filter == nil
+}
+
+type BulkItemOperation struct {
+	Type          BulkOperationType `json:"type"`
+	PriceMode     PriceChangeMode   `json:"price_mode,omitempty"`
+	Amount        float64           `json:"amount,omitempty"`
+	Rounding      RoundingRule      `json:"rounding,omitempty"`
+	RoundTo       float64           `json:"round_to,omitempty"`
+	Available     *bool             `json:"available,omitempty"`
+	SubCategoryID *uint             `json:"sub_category_id,omitempty"`
+	Currency      string            `json:"currency,omitempty"`
+}
+
+type BulkCategoryOperation struct {
+	Type   BulkOperationType `json:"type"`
+	Active *bool             `json:"active,omitempty"`
+}
+
+type BulkFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type BulkChange struct {
+	ID      uint                       `json:"id"`
+	Name    string                     `json:"name"`
+	Deleted bool                       `json:"deleted,omitempty"`
+	Changes map[string]BulkFieldChange `json:"changes,omitempty"`
+}
+
+type BulkResult struct {
+	DryRun   bool         `json:"dry_run"`
+	Matched  int          `json:"matched"`
+	Affected int          `json:"affected"`
+	Changes  []BulkChange `json:"changes"`
+}
+
+// Apply mutates item according to the operation and returns the columns
+// that changed. Delete operations leave the item untouched.
+func (op BulkItemOperation) Apply(item *Item) map[string]BulkFieldChange {
+	changes := make(map[string]BulkFieldChange)
+
+	switch op.Type {
+	case BulkOpPriceChange:
+		price := op.NewPrice(item.Price)
+		if price != item.Price {
+			changes["price"] = BulkFieldChange{From: item.Price, To: price}
+			item.Price = price
+		}
+	case BulkOpSetAvailability:
+		if op.Available != nil && *op.Available != item.Available {
+			changes["available"] = BulkFieldChange{From: item.Available, To: *op.Available}
+			item.Available = *op.Available
+		}
+	case BulkOpMoveSubCategory:
+		if op.SubCategoryID != nil && *op.SubCategoryID != item.SubCategoryID {
+			changes["sub_category_id"] = BulkFieldChange{From: item.SubCategoryID, To: *op.SubCategoryID}
+			item.SubCategoryID = *op.SubCategoryID
+		}
+	case BulkOpSetCurrency:
+		if op.Currency != "" && op.Currency != item.Currency {
+			changes["currency"] = BulkFieldChange{From: item.Currency, To: op.Currency}
+			item.Currency = op.Currency
+		}
+	}
+
+	return changes
+}
+
+// NewPrice computes the adjusted price, applying the rounding rule and
+// never going below zero.
+func (op BulkItemOperation) NewPrice(price float64) float64 {
+	switch op.PriceMode {
+	case PriceChangePercentage:
+		price = price * (1 + op.Amount/100)
+	case PriceChangeAbsolute:
+		price = price + op.Amount
+	}
+
+	price = roundPrice(price, op.Rounding, op.RoundTo)
+	if price < 0 {
+		price = 0
+	}
+	return price
+}
+
+func roundPrice(price float64, rule RoundingRule, step float64) float64 {
+	if step <= 0 {
+		step = 0.01
+	}
+
+	// The epsilon keeps values like 10.000000001 from being pushed up a full step
+	steps := price / step
+	switch rule {
+	case RoundingUp:
+		steps = math.Ceil(steps - 1e-9)
+	case RoundingDown:
+		steps = math.Floor(steps + 1e-9)
+	case RoundingNearest:
+		steps = math.Round(steps)
+	default:
+		step = 0.01
+		steps = math.Round(price / step)
+	}
+
+	// Prices are stored as decimal(10,2)
+	return math.Round(steps*step*100) / 100
+}
@@ -22,17 +22,27 @@ type Category struct {
 }
 
 func (c *Category) BeforeCreate(tx *gorm.DB) error {
-	if c.Slug == "" {
-		c.Slug = generateSlug(c.Name)
-	}
-	return nil
+	slug, err := categorySlugs.forCreate(tx, c.Name, c.Slug, nil)
+	c.Slug = slug
+	return err
 }
 
+// BeforeUpdate keeps the slug in line with the name when a loaded category is
+// saved, remembering the old slug for redirects.
 func (c *Category) BeforeUpdate(tx *gorm.DB) error {
-	if tx.Statement.Changed("Name") && c.Slug == "" {
-		c.Slug = generateSlug(c.Name)
+	// Column updates through Model(&Category{}) carry no row
+	if c.ID == 0 {
+		return nil
+	}
+
+	previous, ok, err := previousSlugState(tx, &Category{}, c.ID, "")
+	if err != nil || !ok {
+		return err
 	}
-	return nil
+
+	slug, err := categorySlugs.forUpdate(tx, c.ID, c.Name, c.Slug, previous, 0, nil)
+	c.Slug = slug
+	return err
 }
 
 func (c *Category) TableName() string {
@@ -44,7 +54,13 @@ type CategoryFilter struct {
 	Search       string `json:"search"`
 	Limit        int    `json:"limit"`
 	Offset       int    `json:"offset"`
-	OrderBy      string `json:"order_by"`
-	OrderDir     string `json:"order_dir"`
+	Sort         Sort   `json:"sort"`
 	IncludeCount bool   `json:"include_count"`
+
+	// Cursor selects keyset pagination in (display_order, id) order instead
+	// of Offset and Sort
+	Cursor *Cursor `json:"-"`
+
+	// Expand picks the relations loaded with each result; see Expand
+	Expand Expand `json:"-"`
 }
\ No newline at end of file
@@ -0,0 +1,26 @@
+package entities
+
+const DefaultCloneNameSuffix = " (Copy)"
+
+// CloneOptions controls how an entity and its subtree are duplicated.
+type CloneOptions struct {
+	NameSuffix     *string `json:"name_suffix"`
+	TargetParentID *uint   `json:"target_parent_id"`
+}
+
+func (o CloneOptions) Suffix() string {
+	if o.NameSuffix == nil {
+		return DefaultCloneNameSuffix
+	}
+	return *o.NameSuffix
+}
+
+// CloneName appends suffix to name, trimming name so the result fits maxLength.
+func CloneName(name, suffix string, maxLength int) string {
+	runes := []rune(name)
+	suffixLength := len([]rune(suffix))
+	if len(runes)+suffixLength > maxLength && maxLength > suffixLength {
+		runes = runes[:maxLength-suffixLength]
+	}
+	return string(runes) + suffix
+}
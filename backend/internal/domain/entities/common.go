@@ -1,25 +1,11 @@
 package entities
 
 import (
-	"regexp"
-	"strings"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 )
 
-var slugRegex = regexp.MustCompile(`[^a-z0-9\-]`)
-
-func generateSlug(name string) string {
-	slug := strings.ToLower(name)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = slugRegex.ReplaceAllString(slug, "")
-	slug = strings.Trim(slug, "-")
-	
-	if len(slug) > 100 {
-		slug = slug[:100]
-	}
-	
-	return slug
-}
-
 type Pagination struct {
 	Page       int   `json:"page"`
 	Limit      int   `json:"limit"`
@@ -27,6 +13,41 @@ type Pagination struct {
 	TotalPages int   `json:"total_pages"`
 	HasNext    bool  `json:"has_next"`
 	HasPrev    bool  `json:"has_prev"`
+
+	// NextCursor continues a cursor page; it is sent in the response meta
+	NextCursor string `json:"-"`
+}
+
+// Cursor is the position of the last row of a page in (display_order, id)
+// order. The zero cursor starts from the first row.
+type Cursor struct {
+	DisplayOrder int  `json:"o"`
+	ID           uint `json:"i"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token from Encode; an empty token is the zero cursor.
+func DecodeCursor(token string) (*Cursor, error) {
+	cursor := &Cursor{}
+	if token == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
 }
 
 func NewPagination(page, limit int, total int64) *Pagination {
@@ -52,6 +52,5 @@ type ContentSectionFilter struct {
 	Search      string `json:"search"`
 	Limit       int    `json:"limit"`
 	Offset      int    `json:"offset"`
-	OrderBy     string `json:"order_by"`
-	OrderDir    string `json:"order_dir"`
+	Sort        Sort   `json:"sort"`
 }
\ No newline at end of file
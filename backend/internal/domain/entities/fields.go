@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expand lists the relations to load with an entity, as paths such as
+// "sub_category.category". A nil Expand loads the relations a repository
+// loads by default; an empty one loads none.
+type Expand []string
+
+func (e Expand) Has(relation string) bool {
+	for _, expanded := range e {
+		if expanded == relation {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldRegistry lists the fields a kind of entity may be narrowed to and the
+// relations it may be expanded with, by their JSON names.
+type FieldRegistry struct {
+	fields    []string
+	relations []string
+}
+
+// NewFieldRegistry lists relations parents first, e.g. "sub_category" before
+// "sub_category.category".
+func NewFieldRegistry(fields, relations []string) FieldRegistry {
+	return FieldRegistry{fields: fields, relations: relations}
+}
+
+var (
+	ItemFields = NewFieldRegistry(
+		[]string{"id", "name", "slug", "description", "price", "currency", "dietary_info", "image_url", "sub_category_id", "available", "display_order", "created_at", "updated_at"},
+		[]string{"sub_category", "sub_category.category", "placements"},
+	)
+	CategoryFields = NewFieldRegistry(
+		[]string{"id", "name", "description", "slug", "display_order", "active", "created_at", "updated_at"},
+		[]string{"sub_categories"},
+	)
+	SubCategoryFields = NewFieldRegistry(
+		[]string{"id", "name", "description", "slug", "category_id", "parent_id", "path", "depth", "display_order", "active", "created_at", "updated_at"},
+		[]string{"category", "items"},
+	)
+)
+
+func (r FieldRegistry) Relations() []string {
+	return r.relations
+}
+
+// IsRelation reports whether key is the JSON name of a relation.
+func (r FieldRegistry) IsRelation(key string) bool {
+	for _, relation := range r.relations {
+		if strings.SplitN(relation, ".", 2)[0] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFields reads a field list such as "id,name,price".
+func (r FieldRegistry) ParseFields(param string) ([]string, error) {
+	return parseNameList(param, r.fields, "field")
+}
+
+// ParseExpand reads a relation list such as "sub_category.category". Nested
+// relations imply their parents.
+func (r FieldRegistry) ParseExpand(param string) (Expand, error) {
+	relations, err := parseNameList(param, r.relations, "relation")
+	if err != nil {
+		return nil, err
+	}
+
+	expand := Expand{}
+	for _, relation := range relations {
+		parts := strings.Split(relation, ".")
+		for i := range parts {
+			if parent := strings.Join(parts[:i+1], "."); !expand.Has(parent) {
+				expand = append(expand, parent)
+			}
+		}
+	}
+	return expand, nil
+}
+
+func parseNameList(param string, allowed []string, kind string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+
+		known := false
+		for _, candidate := range allowed {
+			if name == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown %s %q; allowed: %s", kind, name, strings.Join(allowed, ", "))
+		}
+
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DietaryInfo map[string]interface{}
@@ -32,6 +33,7 @@ func (di *DietaryInfo) Scan(value interface{}) error {
 type Item struct {
 	ID            uint           `json:"id" gorm:"primarykey"`
 	Name          string         `json:"name" gorm:"size:150;not null" validate:"required,min=1,max=150"`
+	Slug          string         `json:"slug" gorm:"size:160;uniqueIndex"`
 	Description   string         `json:"description" gorm:"type:text"`
 	Price         float64        `json:"price" gorm:"type:decimal(10,2);not null" validate:"required,min=0"`
 	Currency      string         `json:"currency" gorm:"size:3;default:'AED'" validate:"len=3"`
@@ -45,13 +47,66 @@ type Item struct {
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationships
-	SubCategory *SubCategory `json:"sub_category,omitempty" gorm:"foreignKey:SubCategoryID"`
+	SubCategory *SubCategory    `json:"sub_category,omitempty" gorm:"foreignKey:SubCategoryID;constraint:OnDelete:CASCADE"`
+	Placements  []ItemPlacement `json:"placements,omitempty" gorm:"foreignKey:ItemID;constraint:OnDelete:CASCADE"`
+}
+
+func (i *Item) BeforeCreate(tx *gorm.DB) error {
+	slug, err := itemSlugs.forCreate(tx, i.Name, i.Slug, nil)
+	i.Slug = slug
+	return err
+}
+
+// BeforeUpdate keeps the slug in line with the name when a loaded item is
+// saved, remembering the old slug for redirects.
+func (i *Item) BeforeUpdate(tx *gorm.DB) error {
+	// Column updates through Model(&Item{}) carry no row
+	if i.ID == 0 {
+		return nil
+	}
+
+	previous, ok, err := previousSlugState(tx, &Item{}, i.ID, "")
+	if err != nil || !ok {
+		return err
+	}
+
+	slug, err := itemSlugs.forUpdate(tx, i.ID, i.Name, i.Slug, previous, 0, nil)
+	i.Slug = slug
+	return err
+}
+
+// AfterCreate places a new item in its primary subcategory so placement-based
+// listings pick it up.
+func (i *Item) AfterCreate(tx *gorm.DB) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ItemPlacement{
+		ItemID:        i.ID,
+		SubCategoryID: i.SubCategoryID,
+		DisplayOrder:  i.DisplayOrder,
+	}).Error
 }
 
 func (i *Item) TableName() string {
 	return "items"
 }
 
+// ItemPlacement lists an item in a subcategory. Every item has a placement in
+// its primary SubCategoryID and may be placed in further subcategories.
+type ItemPlacement struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	ItemID        uint      `json:"item_id" gorm:"not null;uniqueIndex:idx_item_placements_item_sub_category"`
+	SubCategoryID uint      `json:"sub_category_id" gorm:"not null;uniqueIndex:idx_item_placements_item_sub_category;index"`
+	DisplayOrder  int       `json:"display_order" gorm:"default:0;index"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationships
+	SubCategory *SubCategory `json:"sub_category,omitempty" gorm:"foreignKey:SubCategoryID;constraint:OnDelete:CASCADE"`
+}
+
+func (ip *ItemPlacement) TableName() string {
+	return "item_placements"
+}
+
 type ItemFilter struct {
 	SubCategoryID *uint   `json:"sub_category_id"`
 	CategoryID    *uint   `json:"category_id"`
@@ -61,7 +116,26 @@ type ItemFilter struct {
 	Search        string  `json:"search"`
 	Limit         int     `json:"limit"`
 	Offset        int     `json:"offset"`
-	OrderBy       string  `json:"order_by"`
-	OrderDir      string  `json:"order_dir"`
+	Sort          Sort    `json:"sort"`
 	IncludeCount  bool    `json:"include_count"`
+
+	// Cursor selects keyset pagination in (display_order, id) order instead
+	// of Offset and Sort
+	Cursor *Cursor `json:"-"`
+
+	// Expand picks the relations loaded with each result; see Expand
+	Expand Expand `json:"-"`
+
+	// Facet selections used by search. Any of the listed categories,
+	// subcategories or price buckets match; every listed dietary label and tag
+	// must be set.
+	CategoryIDs    []uint   `json:"category_ids"`
+	SubCategoryIDs []uint   `json:"sub_category_ids"`
+	DietaryLabels  []string `json:"dietary_labels"`
+	Tags           []string `json:"tags"`
+	PriceBuckets   []string `json:"price_buckets"`
+	IncludeFacets  bool     `json:"include_facets"`
+
+	// Dictionary expands synonyms and drops stop words in search queries
+	Dictionary *SearchDictionary `json:"-"`
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// MenuVersion identifies the state of the menu data. It changes whenever a
+// category, subcategory, item or placement is written or removed.
+type MenuVersion struct {
+	LastModified time.Time
+	Rows         int64
+}
+
+// ETag is a weak entity tag for responses built from this version of the menu.
+func (v MenuVersion) ETag() string {
+	return fmt.Sprintf(`W/"%x-%x"`, v.LastModified.UnixNano(), v.Rows)
+}
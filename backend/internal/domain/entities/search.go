@@ -0,0 +1,94 @@
+package entities
+
+import "time"
+
+// SearchHighlight holds the matched terms of one search result wrapped in
+// <mark> tags. Text outside the marks is HTML-escaped.
+type SearchHighlight struct {
+	ItemID      uint    `json:"item_id"`
+	Rank        float64 `json:"rank"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+}
+
+// ItemSearchResult is a page of items ranked by relevance, most relevant first,
+// with a highlight for each item in the same order.
+type ItemSearchResult struct {
+	Items      []*Item           `json:"items"`
+	Highlights []SearchHighlight `json:"highlights"`
+	Facets     *SearchFacets     `json:"facets,omitempty"`
+	Pagination *Pagination       `json:"pagination,omitempty"`
+}
+
+// DietaryLabels are the dietary_info keys offered as the dietary facet. Any
+// other dietary_info key set to true counts as a tag.
+var DietaryLabels = []string{"vegetarian", "vegan", "gluten_free", "dairy_free", "halal"}
+
+// PriceBucket is a price range facet. Min is inclusive, Max exclusive and a
+// zero Max leaves the range open-ended.
+type PriceBucket struct {
+	Key   string
+	Label string
+	Min   float64
+	Max   float64
+}
+
+var PriceBuckets = []PriceBucket{
+	{Key: "under_30", Label: "Under 30", Min: 0, Max: 30},
+	{Key: "30_60", Label: "30 - 60", Min: 30, Max: 60},
+	{Key: "60_100", Label: "60 - 100", Min: 60, Max: 100},
+	{Key: "100_plus", Label: "100 and above", Min: 100},
+}
+
+// FacetValue is one facet chip and the number of results it would show.
+type FacetValue struct {
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Count    int64  `json:"count"`
+	Selected bool   `json:"selected"`
+}
+
+// SearchFacets counts the results per facet value. Counts for categories,
+// subcategories and price buckets ignore the selections of their own facet,
+// since picking another value there widens the results.
+type SearchFacets struct {
+	Categories    []FacetValue `json:"categories"`
+	SubCategories []FacetValue `json:"sub_categories"`
+	DietaryLabels []FacetValue `json:"dietary_labels"`
+	Tags          []FacetValue `json:"tags"`
+	PriceBuckets  []FacetValue `json:"price_buckets"`
+}
+
+// PriceBucketByKey looks up one of the PriceBuckets.
+func PriceBucketByKey(key string) (PriceBucket, bool) {
+	for _, bucket := range PriceBuckets {
+		if bucket.Key == key {
+			return bucket, true
+		}
+	}
+	return PriceBucket{}, false
+}
+
+// SearchQuery counts how often a normalized query that found something was
+// searched, for suggesting popular queries.
+type SearchQuery struct {
+	ID              uint       `json:"id" gorm:"primarykey"`
+	Query           string     `json:"query" gorm:"size:200;not null;uniqueIndex:idx_search_queries_query"`
+	SearchCount     int64      `json:"search_count" gorm:"not null;default:0"`
+	LastResultCount int        `json:"last_result_count" gorm:"not null;default:0"`
+	LastSearchedAt  *time.Time `json:"last_searched_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (sq *SearchQuery) TableName() string {
+	return "search_queries"
+}
+
+// Suggestion is one entry of the search-as-you-type dropdown. Queries carry
+// only the text.
+type Suggestion struct {
+	ID   uint   `json:"id,omitempty"`
+	Text string `json:"text"`
+	Slug string `json:"slug,omitempty"`
+}
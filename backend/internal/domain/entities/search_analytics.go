@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SearchEventFilters are the filters and facet selections a search used.
+type SearchEventFilters struct {
+	CategoryIDs    []uint   `json:"category_ids,omitempty"`
+	SubCategoryIDs []uint   `json:"sub_category_ids,omitempty"`
+	DietaryLabels  []string `json:"dietary_labels,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	PriceBuckets   []string `json:"price_buckets,omitempty"`
+	MinPrice       *float64 `json:"min_price,omitempty"`
+	MaxPrice       *float64 `json:"max_price,omitempty"`
+}
+
+func (f SearchEventFilters) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+func (f *SearchEventFilters) Scan(value interface{}) error {
+	if value == nil {
+		*f = SearchEventFilters{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into SearchEventFilters", value)
+	}
+
+	return json.Unmarshal(bytes, f)
+}
+
+// SearchEvent records one menu search. Only the normalized query and what it
+// found are kept, nothing about who searched.
+type SearchEvent struct {
+	ID          uint               `json:"id" gorm:"primarykey"`
+	Query       string             `json:"query" gorm:"size:200;not null;index"`
+	ResultCount int                `json:"result_count" gorm:"not null;default:0"`
+	Fuzzy       bool               `json:"fuzzy" gorm:"not null;default:false"`
+	Filters     SearchEventFilters `json:"filters" gorm:"type:jsonb"`
+	CreatedAt   time.Time          `json:"created_at" gorm:"index"`
+}
+
+func (se *SearchEvent) TableName() string {
+	return "search_events"
+}
+
+// SearchClick records a guest opening a result of a search.
+type SearchClick struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	SearchEventID uint      `json:"search_event_id" gorm:"not null;index"`
+	ItemID        uint      `json:"item_id" gorm:"not null;index"`
+	Position      int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relationships
+	SearchEvent *SearchEvent `json:"-" gorm:"foreignKey:SearchEventID;constraint:OnDelete:CASCADE"`
+	Item        *Item        `json:"-" gorm:"foreignKey:ItemID;constraint:OnDelete:CASCADE"`
+}
+
+func (sc *SearchClick) TableName() string {
+	return "search_clicks"
+}
+
+// SearchReportFilter limits a report to searches made in [From, To).
+type SearchReportFilter struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Limit int       `json:"limit"`
+}
+
+// SearchQueryReport summarises the searches for one normalized query.
+type SearchQueryReport struct {
+	Query              string    `json:"query"`
+	Searches           int64     `json:"searches"`
+	ZeroResultSearches int64     `json:"zero_result_searches"`
+	AvgResults         float64   `json:"avg_results"`
+	Clicks             int64     `json:"clicks"`
+	ClickThroughRate   float64   `json:"click_through_rate"`
+	LastSearchedAt     time.Time `json:"last_searched_at"`
+}
+
+// ItemClickReport counts how often an item was opened from search results.
+type ItemClickReport struct {
+	ItemID      uint    `json:"item_id"`
+	ItemName    string  `json:"item_name"`
+	Clicks      int64   `json:"clicks"`
+	Searches    int64   `json:"searches"`
+	AvgPosition float64 `json:"avg_position"`
+}
+
+// ClickThroughReport is the share of searches that led to a result being
+// opened, with the items opened most.
+type ClickThroughReport struct {
+	Searches           int64             `json:"searches"`
+	SearchesWithClicks int64             `json:"searches_with_clicks"`
+	ClickThroughRate   float64           `json:"click_through_rate"`
+	Items              []ItemClickReport `json:"items"`
+}
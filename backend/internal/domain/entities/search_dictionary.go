@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSearchLocale is used when a search or dictionary entry names none.
+const DefaultSearchLocale = "en"
+
+type StringList []string
+
+func (sl StringList) Value() (driver.Value, error) {
+	if sl == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(sl))
+}
+
+func (sl *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*sl = StringList{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+
+	return json.Unmarshal(bytes, sl)
+}
+
+// SearchSynonym is a group of interchangeable search terms; searching for any
+// of them finds items mentioning the others. Terms may be several words.
+type SearchSynonym struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	Locale    string     `json:"locale" gorm:"size:10;not null;default:'en';index"`
+	Terms     StringList `json:"terms" gorm:"type:jsonb;not null"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (ss *SearchSynonym) TableName() string {
+	return "search_synonyms"
+}
+
+// SearchStopWord is a word ignored in search queries, such as "dish".
+type SearchStopWord struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Locale    string    `json:"locale" gorm:"size:10;not null;default:'en';uniqueIndex:idx_search_stop_words_locale_word"`
+	Word      string    `json:"word" gorm:"size:50;not null;uniqueIndex:idx_search_stop_words_locale_word"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ssw *SearchStopWord) TableName() string {
+	return "search_stop_words"
+}
+
+// SearchDictionary is everything applied to the queries of one locale.
+type SearchDictionary struct {
+	Locale    string     `json:"locale"`
+	Synonyms  [][]string `json:"synonyms"`
+	StopWords []string   `json:"stop_words"`
+}
+
+// NormalizeSearchTerm lowercases a term and collapses its whitespace.
+func NormalizeSearchTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/pkg/utils"
+)
+
+type SlugEntityType string
+
+const (
+	SlugEntityCategory    SlugEntityType = "category"
+	SlugEntitySubCategory SlugEntityType = "sub_category"
+	SlugEntityItem        SlugEntityType = "item"
+)
+
+// SlugRedirect remembers a slug an entity used to have so that shared links
+// keep working after a rename. ScopeID is the category a subcategory slug was
+// used in and 0 for categories and items.
+type SlugRedirect struct {
+	ID         uint           `json:"id" gorm:"primarykey"`
+	EntityType SlugEntityType `json:"entity_type" gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_lookup"`
+	ScopeID    uint           `json:"scope_id" gorm:"not null;default:0;uniqueIndex:idx_slug_redirects_lookup"`
+	Slug       string         `json:"slug" gorm:"size:160;not null;uniqueIndex:idx_slug_redirects_lookup"`
+	EntityID   uint           `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+func (sr *SlugRedirect) TableName() string {
+	return "slug_redirects"
+}
+
+// reservedCategorySlugs are taken by fixed routes below /api/v1/menu.
+var reservedCategorySlugs = map[string]bool{
+	"tree":     true,
+	"suggest":  true,
+	"search":   true,
+	"featured": true,
+}
+
+// slugRules describes how slugs are built and kept unique for one table.
+type slugRules struct {
+	model     interface{}
+	entity    SlugEntityType
+	maxLength int
+	fallback  string
+	reserved  map[string]bool
+}
+
+var (
+	categorySlugs    = slugRules{model: &Category{}, entity: SlugEntityCategory, maxLength: 100, fallback: "category", reserved: reservedCategorySlugs}
+	subCategorySlugs = slugRules{model: &SubCategory{}, entity: SlugEntitySubCategory, maxLength: 100, fallback: "sub-category"}
+	itemSlugs        = slugRules{model: &Item{}, entity: SlugEntityItem, maxLength: 160, fallback: "item"}
+)
+
+// slugState is what a row looked like before an update.
+type slugState struct {
+	Name    string
+	Slug    string
+	ScopeID uint
+}
+
+// forCreate returns the slug for a new row: slug when set, otherwise one
+// derived from name, made unique within scope.
+func (r slugRules) forCreate(tx *gorm.DB, name, slug string, scope func(*gorm.DB) *gorm.DB) (string, error) {
+	if slug == "" {
+		slug = name
+	}
+	return r.unique(tx, r.generate(slug), 0, scope)
+}
+
+// forUpdate returns the slug for row id after an update. Renamed rows get a
+// slug derived from the new name; when the slug or its scope changes, the
+// previous slug is kept as a redirect.
+func (r slugRules) forUpdate(tx *gorm.DB, id uint, name, slug string, previous slugState, scopeID uint, scope func(*gorm.DB) *gorm.DB) (string, error) {
+	if slug == "" || name != previous.Name {
+		slug = r.generate(name)
+	}
+
+	if slug == previous.Slug && scopeID == previous.ScopeID {
+		return slug, nil
+	}
+
+	slug, err := r.unique(tx, slug, id, scope)
+	if err != nil {
+		return "", err
+	}
+
+	if previous.Slug != "" && (slug != previous.Slug || scopeID != previous.ScopeID) {
+		if err := recordSlugRedirect(tx, r.entity, previous.ScopeID, previous.Slug, id); err != nil {
+			return "", err
+		}
+	}
+
+	return slug, nil
+}
+
+func (r slugRules) generate(name string) string {
+	slug := truncateSlug(utils.GenerateSlug(name), r.maxLength)
+	if slug == "" {
+		slug = r.fallback
+	}
+	return slug
+}
+
+// unique returns base, or base with the lowest free "-n" suffix, such that no
+// other row selected by scope uses it. Soft-deleted rows count, as they keep
+// their index entries and may be restored.
+func (r slugRules) unique(tx *gorm.DB, base string, excludeID uint, scope func(*gorm.DB) *gorm.DB) (string, error) {
+	query := tx.Unscoped().Model(r.model).Where("slug = ? OR slug LIKE ?", base, base+"-%")
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if scope != nil {
+		query = scope(query)
+	}
+
+	var taken []string
+	if err := query.Pluck("slug", &taken).Error; err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+
+	candidate := base
+	for n := 2; used[candidate] || r.reserved[candidate]; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		candidate = truncateSlug(base, r.maxLength-len(suffix)) + suffix
+	}
+
+	return candidate, nil
+}
+
+func truncateSlug(slug string, maxLength int) string {
+	if len(slug) > maxLength {
+		slug = strings.TrimRight(slug[:maxLength], "-")
+	}
+	return slug
+}
+
+// recordSlugRedirect points slug at entityID, replacing any earlier entity
+// that gave it up.
+func recordSlugRedirect(tx *gorm.DB, entity SlugEntityType, scopeID uint, slug string, entityID uint) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "scope_id"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "updated_at"}),
+	}).Create(&SlugRedirect{
+		EntityType: entity,
+		ScopeID:    scopeID,
+		Slug:       slug,
+		EntityID:   entityID,
+	}).Error
+}
+
+// previousSlugState loads the name, slug and the given scope column of row id
+// as stored before the update. ok is false when the row does not exist yet.
+func previousSlugState(tx *gorm.DB, model interface{}, id uint, scopeColumn string) (state slugState, ok bool, err error) {
+	columns := "name, slug"
+	if scopeColumn != "" {
+		columns += ", " + scopeColumn + " AS scope_id"
+	}
+
+	result := tx.Unscoped().Model(model).Select(columns).Where("id = ?", id).Limit(1).Scan(&state)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return state, false, result.Error
+	}
+	return state, result.RowsAffected > 0, nil
+}
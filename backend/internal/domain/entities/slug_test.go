@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// newSlugTestDB returns a connection on which the slugs already taken are
+// the rows the mock returns.
+func newSlugTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+func TestSlugRulesGenerate(t *testing.T) {
+	tests := []struct {
+		rules slugRules
+		name  string
+		want  string
+	}{
+		{itemSlugs, "Chicken Shawarma", "chicken-shawarma"},
+		{itemSlugs, "شاورما", "shawrma"},
+		{itemSlugs, "寿司", "item"},
+		{categorySlugs, "寿司", "category"},
+		{subCategorySlugs, "!!!", "sub-category"},
+		{categorySlugs, strings.Repeat("abcd ", 30), strings.Repeat("abcd-", 19) + "abcd"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rules.generate(tt.name); got != tt.want {
+			t.Errorf("%s generate(%q) = %q, want %q", tt.rules.entity, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlugRulesUnique(t *testing.T) {
+	long := strings.Repeat("a", 160)
+
+	tests := []struct {
+		name  string
+		rules slugRules
+		base  string
+		taken []string
+		want  string
+	}{
+		{"free", itemSlugs, "soup", nil, "soup"},
+		{"taken", itemSlugs, "soup", []string{"soup"}, "soup-2"},
+		{"lowest free suffix", itemSlugs, "soup", []string{"soup", "soup-2", "soup-4"}, "soup-3"},
+		{"suffixed slug of another name", itemSlugs, "soup", []string{"soup-12"}, "soup"},
+		{"plain slug of another name", itemSlugs, "soup-12", []string{"soup-12"}, "soup-12-2"},
+		{"reserved", categorySlugs, "search", nil, "search-2"},
+		{"reserved and taken", categorySlugs, "search", []string{"search-2"}, "search-3"},
+		{"truncated before the suffix", itemSlugs, long, []string{long}, long[:158] + "-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newSlugTestDB(t)
+
+			rows := sqlmock.NewRows([]string{"slug"})
+			for _, slug := range tt.taken {
+				rows.AddRow(slug)
+			}
+			mock.ExpectQuery(`SELECT "slug" FROM .* WHERE \(?slug = \$1 OR slug LIKE \$2\)?`).
+				WithArgs(tt.base, tt.base+"-%").
+				WillReturnRows(rows)
+
+			got, err := tt.rules.unique(db, tt.base, 0, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("unique(%q) = %q, want %q", tt.base, got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestSlugRulesUniqueExcludesTheRowItself(t *testing.T) {
+	db, mock := newSlugTestDB(t)
+
+	mock.ExpectQuery(`SELECT "slug" FROM "items" WHERE \(?slug = \$1 OR slug LIKE \$2\)? AND id <> \$3`).
+		WithArgs("soup", "soup-%", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+
+	got, err := itemSlugs.unique(db, "soup", 7, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "soup" {
+		t.Errorf("unique = %q, want %q", got, "soup")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
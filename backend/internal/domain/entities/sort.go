@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxSortFields caps how many keys one sort may combine.
+const MaxSortFields = 3
+
+// SortField orders a list by one field, ascending unless Desc is set.
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// Sort orders a list by its fields in turn. An empty sort leaves the
+// repository's default order.
+type Sort []SortField
+
+// SortRegistry lists the fields a kind of entity may be sorted by.
+type SortRegistry struct {
+	fields []string
+}
+
+func NewSortRegistry(fields ...string) SortRegistry {
+	return SortRegistry{fields: fields}
+}
+
+var (
+	ItemSorts        = NewSortRegistry("name", "price", "available", "display_order", "created_at", "updated_at")
+	CategorySorts    = NewSortRegistry("name", "display_order", "created_at", "updated_at")
+	SubCategorySorts = NewSortRegistry("name", "display_order", "created_at", "updated_at")
+	ContentSorts     = NewSortRegistry("section_name", "title", "created_at", "updated_at")
+)
+
+func (r SortRegistry) Fields() []string {
+	return r.fields
+}
+
+// Parse reads a sort such as "-price,name", where a leading minus sorts that
+// field descending. Unknown fields are rejected with the allowed ones listed.
+func (r SortRegistry) Parse(param string) (Sort, error) {
+	var sort Sort
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Field: strings.TrimPrefix(part, "+")}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: part[1:], Desc: true}
+		}
+
+		if !r.allows(field.Field) {
+			return nil, fmt.Errorf("cannot sort by %q; allowed fields: %s", field.Field, strings.Join(r.fields, ", "))
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("%q appears more than once in the sort", field.Field)
+		}
+		seen[field.Field] = true
+
+		sort = append(sort, field)
+	}
+
+	if len(sort) > MaxSortFields {
+		return nil, fmt.Errorf("sort by at most %d fields", MaxSortFields)
+	}
+	return sort, nil
+}
+
+func (r SortRegistry) allows(field string) bool {
+	for _, allowed := range r.fields {
+		if field == allowed {
+			return true
+		}
+	}
+	return false
+}
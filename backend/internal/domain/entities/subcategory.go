@@ -1,6 +1,9 @@
 package entities
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,6 +15,9 @@ type SubCategory struct {
 	Description  string         `json:"description" gorm:"type:text"`
 	Slug         string         `json:"slug" gorm:"size:100"`
 	CategoryID   uint           `json:"category_id" gorm:"not null;index" validate:"required"`
+	ParentID     *uint          `json:"parent_id" gorm:"index"`
+	Path         string         `json:"path" gorm:"size:500;not null;default:'';index"`
+	Depth        int            `json:"depth" gorm:"default:0"`
 	DisplayOrder int            `json:"display_order" gorm:"default:0;index"`
 	Active       bool           `json:"active" gorm:"default:true;index"`
 	CreatedAt    time.Time      `json:"created_at"`
@@ -19,22 +25,78 @@ type SubCategory struct {
 	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationships
-	Category *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
-	Items    []Item    `json:"items,omitempty" gorm:"foreignKey:SubCategoryID;constraint:OnDelete:CASCADE"`
+	Category *Category      `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Parent   *SubCategory   `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+	Children []*SubCategory `json:"children,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
+	// Items are the items placed in the subcategory, in their position
+	// there. They are loaded through item_placements, not by gorm, since
+	// items.sub_category_id only holds each item's primary subcategory.
+	Items []Item `json:"items,omitempty" gorm:"-"`
 }
 
+// BeforeCreate assigns a slug that is unique within the category.
 func (sc *SubCategory) BeforeCreate(tx *gorm.DB) error {
-	if sc.Slug == "" {
-		sc.Slug = generateSlug(sc.Name)
+	slug, err := subCategorySlugs.forCreate(tx, sc.Name, sc.Slug, sc.inCategory)
+	sc.Slug = slug
+	return err
+}
+
+// AfterCreate records the materialized path, which includes the new ID, and
+// the depth below the category.
+func (sc *SubCategory) AfterCreate(tx *gorm.DB) error {
+	sc.Path = fmt.Sprintf("/%d/", sc.ID)
+	sc.Depth = 0
+
+	if sc.ParentID != nil {
+		var parent SubCategory
+		if err := tx.Select("id", "path", "depth").First(&parent, *sc.ParentID).Error; err != nil {
+			return err
+		}
+		sc.Path = fmt.Sprintf("%s%d/", parent.Path, sc.ID)
+		sc.Depth = parent.Depth + 1
+	}
+
+	return tx.Model(sc).UpdateColumns(map[string]interface{}{
+		"path":  sc.Path,
+		"depth": sc.Depth,
+	}).Error
+}
+
+// AncestorIDs returns the IDs on the path from the top-level subcategory down
+// to, and including, this one.
+func (sc *SubCategory) AncestorIDs() []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(sc.Path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
 	}
-	return nil
+	return ids
 }
 
+// BeforeUpdate keeps the slug in line with the name, and unique within the
+// category, when a loaded subcategory is saved. The old slug is remembered for
+// redirects.
 func (sc *SubCategory) BeforeUpdate(tx *gorm.DB) error {
-	if tx.Statement.Changed("Name") && sc.Slug == "" {
-		sc.Slug = generateSlug(sc.Name)
+	// Column updates through Model(&SubCategory{}) carry no row
+	if sc.ID == 0 {
+		return nil
 	}
-	return nil
+
+	previous, ok, err := previousSlugState(tx, &SubCategory{}, sc.ID, "category_id")
+	if err != nil || !ok {
+		return err
+	}
+
+	slug, err := subCategorySlugs.forUpdate(tx, sc.ID, sc.Name, sc.Slug, previous, sc.CategoryID, sc.inCategory)
+	sc.Slug = slug
+	return err
+}
+
+func (sc *SubCategory) inCategory(db *gorm.DB) *gorm.DB {
+	return db.Where("category_id = ?", sc.CategoryID)
 }
 
 func (sc *SubCategory) TableName() string {
@@ -43,11 +105,19 @@ func (sc *SubCategory) TableName() string {
 
 type SubCategoryFilter struct {
 	CategoryID   *uint  `json:"category_id"`
+	ParentID     *uint  `json:"parent_id"`
+	TopLevel     bool   `json:"top_level"` // only subcategories directly below the category
 	Active       *bool  `json:"active"`
 	Search       string `json:"search"`
 	Limit        int    `json:"limit"`
 	Offset       int    `json:"offset"`
-	OrderBy      string `json:"order_by"`
-	OrderDir     string `json:"order_dir"`
+	Sort         Sort   `json:"sort"`
 	IncludeCount bool   `json:"include_count"`
+
+	// Cursor selects keyset pagination in (display_order, id) order instead
+	// of Offset and Sort
+	Cursor *Cursor `json:"-"`
+
+	// Expand picks the relations loaded with each result; see Expand
+	Expand Expand `json:"-"`
 }
\ No newline at end of file
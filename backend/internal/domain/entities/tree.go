@@ -0,0 +1,63 @@
+package entities
+
+import "sort"
+
+type BreadcrumbType string
+
+const (
+	BreadcrumbCategory    BreadcrumbType = "category"
+	BreadcrumbSubCategory BreadcrumbType = "sub_category"
+	BreadcrumbItem        BreadcrumbType = "item"
+)
+
+type Breadcrumb struct {
+	Type BreadcrumbType `json:"type"`
+	ID   uint           `json:"id"`
+	Name string         `json:"name"`
+	Slug string         `json:"slug"`
+}
+
+// SortSubCategoryTree orders nodes parents-first, then by display order, as
+// NestSubCategories expects.
+func SortSubCategoryTree(nodes []*SubCategory) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		if nodes[i].DisplayOrder != nodes[j].DisplayOrder {
+			return nodes[i].DisplayOrder < nodes[j].DisplayOrder
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+}
+
+// NestSubCategories fills Children from a flat list sorted with
+// SortSubCategoryTree and returns the top-level nodes, or the topmost node of
+// a fetched subtree. Nodes below a parent that is missing from the list are
+// dropped with it, so a hidden branch hides everything under it.
+func NestSubCategories(nodes []*SubCategory) []*SubCategory {
+	roots := make([]*SubCategory, 0)
+	if len(nodes) == 0 {
+		return roots
+	}
+
+	// Parents come first, so a node's parent is already nested by the time
+	// the node is reached, or isn't in the list at all
+	nested := make(map[uint]*SubCategory, len(nodes))
+	for _, node := range nodes {
+		node.Children = nil
+
+		if node.ParentID == nil || node.Depth == nodes[0].Depth {
+			// Top level, or the top of a fetched subtree
+			roots = append(roots, node)
+		} else if parent, ok := nested[*node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			continue
+		}
+
+		nested[node.ID] = node
+	}
+
+	return roots
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func treeNode(id uint, parentID *uint, depth, displayOrder int) *SubCategory {
+	return &SubCategory{ID: id, ParentID: parentID, Depth: depth, DisplayOrder: displayOrder}
+}
+
+func uintPointer(value uint) *uint {
+	return &value
+}
+
+// nestedIDs lists the IDs of nodes and, in brackets, those of their children.
+func nestedIDs(nodes []*SubCategory) []interface{} {
+	ids := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+		if len(node.Children) > 0 {
+			ids = append(ids, nestedIDs(node.Children))
+		}
+	}
+	return ids
+}
+
+func TestNestSubCategories(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []*SubCategory
+		want  []interface{}
+	}{
+		{
+			name:  "empty",
+			nodes: nil,
+			want:  []interface{}{},
+		},
+		{
+			name: "siblings in display order",
+			nodes: []*SubCategory{
+				treeNode(1, nil, 0, 2),
+				treeNode(2, nil, 0, 1),
+				treeNode(3, uintPointer(1), 1, 2),
+				treeNode(4, uintPointer(1), 1, 1),
+				treeNode(5, uintPointer(4), 2, 1),
+				treeNode(6, uintPointer(2), 1, 1),
+			},
+			want: []interface{}{uint(2), []interface{}{uint(6)}, uint(1), []interface{}{uint(4), []interface{}{uint(5)}, uint(3)}},
+		},
+		{
+			name: "hidden branch",
+			nodes: []*SubCategory{
+				treeNode(1, nil, 0, 1),
+				treeNode(3, uintPointer(2), 1, 1),
+				treeNode(4, uintPointer(3), 2, 1),
+				treeNode(5, uintPointer(1), 1, 2),
+			},
+			want: []interface{}{uint(1), []interface{}{uint(5)}},
+		},
+		{
+			name: "subtree",
+			nodes: []*SubCategory{
+				treeNode(3, uintPointer(1), 1, 4),
+				treeNode(4, uintPointer(3), 2, 1),
+				treeNode(5, uintPointer(4), 3, 1),
+			},
+			want: []interface{}{uint(3), []interface{}{uint(4), []interface{}{uint(5)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SortSubCategoryTree(tt.nodes)
+			if got := nestedIDs(NestSubCategories(tt.nodes)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tree = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNestSubCategoriesSharesNodes(t *testing.T) {
+	parent := treeNode(1, nil, 0, 1)
+	child := treeNode(2, uintPointer(1), 1, 1)
+	grandchild := treeNode(3, uintPointer(2), 2, 1)
+
+	roots := NestSubCategories([]*SubCategory{parent, child, grandchild})
+
+	if len(roots) != 1 || roots[0] != parent {
+		t.Fatalf("roots = %v, want the parent node", roots)
+	}
+	if len(parent.Children) != 1 || parent.Children[0] != child {
+		t.Fatalf("parent children = %v, want the child node", parent.Children)
+	}
+	if len(child.Children) != 1 || child.Children[0] != grandchild {
+		t.Errorf("child children = %v, want the grandchild node", child.Children)
+	}
+}
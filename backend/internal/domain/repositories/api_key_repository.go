@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+type APIKeyRepository interface {
+	GetAll(ctx context.Context) ([]*entities.APIKey, error)
+	GetByID(ctx context.Context, id uint) (*entities.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	Create(ctx context.Context, key *entities.APIKey) error
+	Update(ctx context.Context, key *entities.APIKey) error
+	// RecordUsage adds the counted requests to the keys' daily usage and
+	// marks the keys as used at usedAt.
+	RecordUsage(ctx context.Context, usages []*entities.APIKeyUsage, usedAt time.Time) error
+	UsageReport(ctx context.Context, filter entities.APIKeyUsageFilter) ([]entities.APIKeyUsageReport, error)
+}
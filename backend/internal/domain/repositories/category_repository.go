@@ -12,9 +12,20 @@ type CategoryRepository interface {
 	GetAll(ctx context.Context, filter entities.CategoryFilter) ([]*entities.Category, *entities.Pagination, error)
 	Update(ctx context.Context, category *entities.Category) error
 	Delete(ctx context.Context, id uint) error
+	// GetWithSubCategories and GetAllWithSubCategories load the active
+	// subcategories of every depth as one flat list, parents first and then in
+	// display order; Children is left empty.
 	GetWithSubCategories(ctx context.Context, id uint) (*entities.Category, error)
 	GetAllWithSubCategories(ctx context.Context, filter entities.CategoryFilter) ([]*entities.Category, error)
 	Count(ctx context.Context, filter entities.CategoryFilter) (int64, error)
 	UpdateDisplayOrder(ctx context.Context, id uint, order int) error
 	ToggleActive(ctx context.Context, id uint) error
+	Reorder(ctx context.Context, ids []uint) error
+	CompactDisplayOrder(ctx context.Context) (int64, error)
+	// RegenerateFallbackSlugs replaces numbered slugs left by migration 000004
+	// with ones derived from the name, returning how many rows changed.
+	RegenerateFallbackSlugs(ctx context.Context) (int64, error)
+	Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Category, error)
+	BulkApply(ctx context.Context, ids []uint, op entities.BulkCategoryOperation, dryRun bool) (*entities.BulkResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]entities.Suggestion, error)
 }
\ No newline at end of file
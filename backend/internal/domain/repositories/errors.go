@@ -0,0 +1,19 @@
+package repositories
+
+import "errors"
+
+// ErrOrderMismatch is returned by Reorder when the submitted IDs are not
+// exactly the current children of the parent.
+var ErrOrderMismatch = errors.New("ordered IDs do not match the current children of the parent")
+
+// ErrDuplicatePlacement is returned by AddPlacement when the item is already
+// listed in the subcategory.
+var ErrDuplicatePlacement = errors.New("item is already placed in the subcategory")
+
+// ErrSubCategoryNotFound is returned by AddPlacement when the subcategory does
+// not exist.
+var ErrSubCategoryNotFound = errors.New("subcategory does not exist")
+
+// ErrInvalidMove is returned by SubCategoryRepository.Move when the new parent
+// is the subcategory itself or nested below it.
+var ErrInvalidMove = errors.New("subcategory cannot be moved below itself")
@@ -8,15 +8,32 @@ import (
 type ItemRepository interface {
 	Create(ctx context.Context, item *entities.Item) error
 	GetByID(ctx context.Context, id uint) (*entities.Item, error)
+	GetBySlug(ctx context.Context, slug string) (*entities.Item, error)
 	GetAll(ctx context.Context, filter entities.ItemFilter) ([]*entities.Item, *entities.Pagination, error)
 	GetBySubCategoryID(ctx context.Context, subCategoryID uint, filter entities.ItemFilter) ([]*entities.Item, error)
 	GetByCategoryID(ctx context.Context, categoryID uint, filter entities.ItemFilter) ([]*entities.Item, error)
+	GetBySubCategoryIDs(ctx context.Context, subCategoryIDs []uint, filter entities.ItemFilter) (map[uint][]*entities.Item, error)
 	Update(ctx context.Context, item *entities.Item) error
 	Delete(ctx context.Context, id uint) error
-	Search(ctx context.Context, query string, filter entities.ItemFilter) ([]*entities.Item, *entities.Pagination, error)
+	Search(ctx context.Context, query string, filter entities.ItemFilter) (*entities.ItemSearchResult, error)
+	SearchSimilar(ctx context.Context, query string, threshold float64, filter entities.ItemFilter) (*entities.ItemSearchResult, error)
+	SuggestQuery(ctx context.Context, query string, threshold float64) (string, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]entities.Suggestion, error)
 	Count(ctx context.Context, filter entities.ItemFilter) (int64, error)
 	UpdateDisplayOrder(ctx context.Context, id uint, order int) error
 	ToggleAvailable(ctx context.Context, id uint) error
 	UpdatePrice(ctx context.Context, id uint, price float64) error
 	GetFeatured(ctx context.Context, limit int) ([]*entities.Item, error)
+	Reorder(ctx context.Context, subCategoryID uint, ids []uint) error
+	CompactDisplayOrder(ctx context.Context) (int64, error)
+	// RegenerateFallbackSlugs replaces numbered slugs left by migration 000004
+	// with ones derived from the name, returning how many rows changed.
+	RegenerateFallbackSlugs(ctx context.Context) (int64, error)
+	Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Item, error)
+	BulkApply(ctx context.Context, selector entities.BulkItemSelector, op entities.BulkItemOperation, dryRun bool) (*entities.BulkResult, error)
+	GetPlacements(ctx context.Context, itemID uint) ([]entities.ItemPlacement, error)
+	// AddPlacement returns ErrSubCategoryNotFound for an unknown subcategory and
+	// ErrDuplicatePlacement when the item is already listed in it.
+	AddPlacement(ctx context.Context, placement *entities.ItemPlacement) error
+	RemovePlacement(ctx context.Context, itemID, subCategoryID uint) (bool, error)
 }
\ No newline at end of file
@@ -0,0 +1,10 @@
+package repositories
+
+import (
+	"context"
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+type MenuVersionRepository interface {
+	GetVersion(ctx context.Context) (*entities.MenuVersion, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+type SearchDictionaryRepository interface {
+	GetDictionary(ctx context.Context, locale string) (*entities.SearchDictionary, error)
+	GetSynonyms(ctx context.Context, locale string) ([]*entities.SearchSynonym, error)
+	GetSynonymByID(ctx context.Context, id uint) (*entities.SearchSynonym, error)
+	CreateSynonym(ctx context.Context, synonym *entities.SearchSynonym) error
+	UpdateSynonym(ctx context.Context, synonym *entities.SearchSynonym) error
+	DeleteSynonym(ctx context.Context, id uint) error
+	GetStopWords(ctx context.Context, locale string) ([]*entities.SearchStopWord, error)
+	GetStopWordByID(ctx context.Context, id uint) (*entities.SearchStopWord, error)
+	CreateStopWord(ctx context.Context, stopWord *entities.SearchStopWord) (bool, error)
+	DeleteStopWord(ctx context.Context, id uint) error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+type SearchQueryRepository interface {
+	Record(ctx context.Context, event *entities.SearchEvent) error
+	RecordClick(ctx context.Context, click *entities.SearchClick) (bool, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]entities.Suggestion, error)
+	TopQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error)
+	ZeroResultQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error)
+	ClickThrough(ctx context.Context, filter entities.SearchReportFilter) (*entities.ClickThroughReport, error)
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import (
+	"context"
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+type SlugRedirectRepository interface {
+	GetRedirect(ctx context.Context, entityType entities.SlugEntityType, scopeID uint, slug string) (*entities.SlugRedirect, error)
+}
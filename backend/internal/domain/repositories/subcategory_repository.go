@@ -9,6 +9,7 @@ type SubCategoryRepository interface {
 	Create(ctx context.Context, subcategory *entities.SubCategory) error
 	GetByID(ctx context.Context, id uint) (*entities.SubCategory, error)
 	GetBySlug(ctx context.Context, slug string) (*entities.SubCategory, error)
+	GetBySlugInCategory(ctx context.Context, categoryID uint, slug string) (*entities.SubCategory, error)
 	GetAll(ctx context.Context, filter entities.SubCategoryFilter) ([]*entities.SubCategory, *entities.Pagination, error)
 	GetByCategoryID(ctx context.Context, categoryID uint, filter entities.SubCategoryFilter) ([]*entities.SubCategory, error)
 	Update(ctx context.Context, subcategory *entities.SubCategory) error
@@ -18,4 +19,13 @@ type SubCategoryRepository interface {
 	Count(ctx context.Context, filter entities.SubCategoryFilter) (int64, error)
 	UpdateDisplayOrder(ctx context.Context, id uint, order int) error
 	ToggleActive(ctx context.Context, id uint) error
+	Reorder(ctx context.Context, categoryID uint, parentID *uint, ids []uint) error
+	Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.SubCategory, error)
+	CompactDisplayOrder(ctx context.Context) (int64, error)
+	// RegenerateFallbackSlugs replaces numbered slugs left by migration 000004
+	// with ones derived from the name, returning how many rows changed.
+	RegenerateFallbackSlugs(ctx context.Context) (int64, error)
+	GetSubtree(ctx context.Context, id uint) ([]*entities.SubCategory, error)
+	GetAncestors(ctx context.Context, id uint) ([]*entities.SubCategory, error)
+	Move(ctx context.Context, id, categoryID uint, parentID *uint) error
 }
\ No newline at end of file
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+const (
+	// apiKeySecretPrefix starts every key, so leaked keys are easy to spot
+	apiKeySecretPrefix = "rmk_"
+	// apiKeyDisplayLength is how much of a key is kept to tell it apart
+	apiKeyDisplayLength = 12
+	// apiKeyCacheTTL bounds how long an authenticated key is trusted without
+	// looking it up again; updates and revocations drop it straight away
+	apiKeyCacheTTL = time.Minute
+	// apiKeyUsageFlushInterval is how often counted usage is written
+	apiKeyUsageFlushInterval = 30 * time.Second
+	// apiKeyUsageWriteTimeout bounds writing counted usage in the background
+	apiKeyUsageWriteTimeout = 10 * time.Second
+
+	defaultAPIKeyUsagePeriod = 30 * 24 * time.Hour
+	defaultAPIKeyUsageLimit  = 50
+	maxAPIKeyUsageLimit      = 500
+)
+
+type APIKeyService interface {
+	GetAll(ctx context.Context) ([]*entities.APIKey, error)
+	GetByID(ctx context.Context, id uint) (*entities.APIKey, error)
+	// Create issues a key, returning its secret this one time.
+	Create(ctx context.Context, key *entities.APIKey) (*entities.CreatedAPIKey, error)
+	Update(ctx context.Context, id uint, key *entities.APIKey) (*entities.APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	// Authenticate returns the active key with secret.
+	Authenticate(ctx context.Context, secret string) (*entities.APIKey, error)
+	// RecordUsage counts a request made with a key; counts are written in
+	// the background.
+	RecordUsage(ctx context.Context, keyID uint, method, route string)
+	UsageReport(ctx context.Context, filter entities.APIKeyUsageFilter) ([]entities.APIKeyUsageReport, error)
+	// Close stops writing usage in the background and writes what is still
+	// counted. Usage recorded afterwards is not written.
+	Close()
+}
+
+type apiKeyUsageKey struct {
+	keyID  uint
+	day    time.Time
+	method string
+	route  string
+}
+
+type apiKeyService struct {
+	repo   repositories.APIKeyRepository
+	cache  Cache
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	pending map[apiKeyUsageKey]int64
+
+	stop      chan struct{}
+	stopOnce  sync.Once
+	flushDone chan struct{}
+}
+
+// NewAPIKeyService writes counted usage every apiKeyUsageFlushInterval.
+func NewAPIKeyService(repo repositories.APIKeyRepository, cache Cache, logger *logger.Logger) APIKeyService {
+	s := &apiKeyService{
+		repo:      repo,
+		cache:     cache,
+		logger:    logger,
+		pending:   make(map[apiKeyUsageKey]int64),
+		stop:      make(chan struct{}),
+		flushDone: make(chan struct{}),
+	}
+
+	go s.flushEvery(apiKeyUsageFlushInterval)
+	return s
+}
+
+func (s *apiKeyService) GetAll(ctx context.Context) ([]*entities.APIKey, error) {
+	keys, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get API keys")
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) GetByID(ctx context.Context, id uint) (*entities.APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get API key")
+	}
+	if key == nil {
+		return nil, appErrors.NewNotFoundError("API key")
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) Create(ctx context.Context, key *entities.APIKey) (*entities.CreatedAPIKey, error) {
+	if err := normalizeAPIKey(key); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateAPIKeySecret()
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to generate API key")
+	}
+	key.Prefix = secret[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKeySecret(secret)
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create API key", map[string]interface{}{
+			"name":  key.Name,
+			"scope": key.Scope,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to create API key")
+	}
+
+	return &entities.CreatedAPIKey{APIKey: key, Key: secret}, nil
+}
+
+func (s *apiKeyService) Update(ctx context.Context, id uint, updateData *entities.APIKey) (*entities.APIKey, error) {
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = updateData.Name
+	existing.Scope = updateData.Scope
+	existing.AllowedOrigins = updateData.AllowedOrigins
+	existing.RateLimit = updateData.RateLimit
+	existing.DailyQuota = updateData.DailyQuota
+
+	if err := normalizeAPIKey(existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, existing); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update API key", map[string]interface{}{
+			"api_key_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to update API key")
+	}
+
+	s.invalidate(ctx, existing)
+	return existing, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id uint) error {
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	existing.RevokedAt = &now
+	if err := s.repo.Update(ctx, existing); err != nil {
+		s.logger.LogError(ctx, err, "Failed to revoke API key", map[string]interface{}{
+			"api_key_id": id,
+		})
+		return appErrors.WrapInternalError(err, "Failed to revoke API key")
+	}
+
+	s.invalidate(ctx, existing)
+	return nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, secret string) (*entities.APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeySecretPrefix) {
+		return nil, appErrors.NewUnauthorizedError("Invalid API key")
+	}
+	keyHash := hashAPIKeySecret(secret)
+
+	var key *entities.APIKey
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, apiKeyCacheKey(keyHash), &key); err != nil {
+			key = nil
+		}
+	}
+
+	if key == nil {
+		var err error
+		key, err = s.repo.GetByHash(ctx, keyHash)
+		if err != nil {
+			return nil, appErrors.WrapInternalError(err, "Failed to check API key")
+		}
+		if key == nil {
+			return nil, appErrors.NewUnauthorizedError("Invalid API key")
+		}
+		if s.cache != nil {
+			// The cache logs failures; the key is looked up again next time
+			_ = s.cache.Set(ctx, apiKeyCacheKey(keyHash), key, apiKeyCacheTTL)
+		}
+	}
+
+	if key.RevokedAt != nil {
+		return nil, appErrors.NewUnauthorizedError("API key has been revoked")
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) RecordUsage(ctx context.Context, keyID uint, method, route string) {
+	usage := apiKeyUsageKey{
+		keyID:  keyID,
+		day:    time.Now().UTC().Truncate(24 * time.Hour),
+		method: method,
+		route:  route,
+	}
+
+	s.mu.Lock()
+	s.pending[usage]++
+	s.mu.Unlock()
+}
+
+func (s *apiKeyService) UsageReport(ctx context.Context, filter entities.APIKeyUsageFilter) ([]entities.APIKeyUsageReport, error) {
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC()
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultAPIKeyUsagePeriod)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, appErrors.NewValidationError("Invalid report period", "from must be before to")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAPIKeyUsageLimit
+	}
+	if filter.Limit > maxAPIKeyUsageLimit {
+		filter.Limit = maxAPIKeyUsageLimit
+	}
+
+	reports, err := s.repo.UsageReport(ctx, filter)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get API key usage", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get API key usage")
+	}
+	return reports, nil
+}
+
+func (s *apiKeyService) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		// Wait for a flush in progress, then write what it left behind
+		<-s.flushDone
+		s.flush()
+	})
+}
+
+func (s *apiKeyService) flushEvery(interval time.Duration) {
+	defer close(s.flushDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// flush writes the usage counted since the last flush. Counts that fail to
+// be written are dropped rather than retried, so the report may fall short
+// but never counts a request twice.
+func (s *apiKeyService) flush() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	pending := s.pending
+	s.pending = make(map[apiKeyUsageKey]int64)
+	s.mu.Unlock()
+
+	usages := make([]*entities.APIKeyUsage, 0, len(pending))
+	for usage, requests := range pending {
+		usages = append(usages, &entities.APIKeyUsage{
+			APIKeyID: usage.keyID,
+			Day:      usage.day,
+			Method:   usage.method,
+			Route:    usage.route,
+			Requests: requests,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyUsageWriteTimeout)
+	defer cancel()
+
+	if err := s.repo.RecordUsage(ctx, usages, time.Now().UTC()); err != nil {
+		s.logger.LogError(ctx, err, "Failed to record API key usage", map[string]interface{}{
+			"usages": len(usages),
+		})
+	}
+}
+
+// invalidate drops the cached copy of a key so that changes apply to its next
+// request.
+func (s *apiKeyService) invalidate(ctx context.Context, key *entities.APIKey) {
+	if s.cache == nil {
+		return
+	}
+	// The cache logs failures; the entry still expires with its TTL
+	_ = s.cache.Delete(ctx, apiKeyCacheKey(key.KeyHash))
+}
+
+func apiKeyCacheKey(keyHash string) string {
+	return "api_key:" + keyHash
+}
+
+// normalizeAPIKey trims the name, defaults the scope to menu and reduces the
+// allowed origins to scheme://host[:port].
+func normalizeAPIKey(key *entities.APIKey) error {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return appErrors.NewValidationError("Invalid API key name", "Name is required")
+	}
+
+	if key.Scope == "" {
+		key.Scope = entities.APIKeyScopeMenu
+	}
+	if key.Scope != entities.APIKeyScopeMenu && key.Scope != entities.APIKeyScopeAdmin {
+		return appErrors.NewValidationError("Invalid API key scope", "Scope must be menu or admin")
+	}
+
+	if key.RateLimit < 0 || key.DailyQuota < 0 {
+		return appErrors.NewValidationError("Invalid API key limits", "Rate limit and daily quota must not be negative")
+	}
+
+	origins := make(entities.StringList, 0, len(key.AllowedOrigins))
+	seen := make(map[string]bool, len(key.AllowedOrigins))
+	for _, origin := range key.AllowedOrigins {
+		origin, ok := entities.NormalizeOrigin(origin)
+		if !ok {
+			return appErrors.NewValidationError("Invalid allowed origin", "Origins look like https://example.com")
+		}
+
+		if !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+	key.AllowedOrigins = origins
+
+	return nil
+}
+
+func generateAPIKeySecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return apiKeySecretPrefix + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// hashAPIKeySecret hashes a key for storage. The secrets are random, so a
+// plain SHA-256 is enough to keep them from being recovered.
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// recordingAPIKeyRepository keeps the usage written to it.
+type recordingAPIKeyRepository struct {
+	repositories.APIKeyRepository
+
+	mu       sync.Mutex
+	requests map[string]int64
+	writes   int
+}
+
+func (r *recordingAPIKeyRepository) RecordUsage(ctx context.Context, usages []*entities.APIKeyUsage, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writes++
+	for _, usage := range usages {
+		r.requests[usage.Method+" "+usage.Route] += usage.Requests
+	}
+	return nil
+}
+
+func TestAPIKeyServiceCloseFlushesUsage(t *testing.T) {
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+
+	repo := &recordingAPIKeyRepository{requests: make(map[string]int64)}
+	service := NewAPIKeyService(repo, nil, log)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		service.RecordUsage(ctx, 1, "GET", "/api/v1/menu")
+	}
+	service.RecordUsage(ctx, 2, "GET", "/api/v1/status")
+
+	service.Close()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.writes != 1 {
+		t.Errorf("usage written %d times, want 1", repo.writes)
+	}
+	if got := repo.requests["GET /api/v1/menu"]; got != 3 {
+		t.Errorf("menu requests = %d, want 3", got)
+	}
+	if got := repo.requests["GET /api/v1/status"]; got != 1 {
+		t.Errorf("status requests = %d, want 1", got)
+	}
+}
+
+func TestAPIKeyServiceCloseTwice(t *testing.T) {
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+
+	repo := &recordingAPIKeyRepository{requests: make(map[string]int64)}
+	service := NewAPIKeyService(repo, nil, log)
+
+	service.RecordUsage(context.Background(), 1, "GET", "/api/v1/menu")
+	service.Close()
+	service.Close()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.writes != 1 {
+		t.Errorf("usage written %d times, want 1", repo.writes)
+	}
+}
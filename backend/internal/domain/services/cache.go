@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores short-lived results shared between requests, in Redis or in
+// process memory. Get returns an error for missing entries. Entries written
+// with SetWithTags are dropped together by InvalidateTags for any of their
+// tags.
+type Cache interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	// SetNX stores value only when key is not set and reports whether it did.
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+	// DeleteIfEquals deletes key only while it still holds value and reports
+	// whether it did.
+	DeleteIfEquals(ctx context.Context, key string, value interface{}) (bool, error)
+	SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+// CacheStats counts cache lookups since the server started.
+type CacheStats struct {
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Invalidations int64   `json:"invalidations"`
+}
+
+type CacheStatsReporter interface {
+	CacheStats() CacheStats
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
@@ -18,6 +20,9 @@ type CategoryService interface {
 	ToggleActive(ctx context.Context, id uint) (*entities.Category, error)
 	UpdateDisplayOrder(ctx context.Context, id uint, order int) (*entities.Category, error)
 	GetWithSubCategories(ctx context.Context, id uint) (*entities.Category, error)
+	Reorder(ctx context.Context, ids []uint) ([]*entities.Category, error)
+	Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Category, error)
+	BulkApply(ctx context.Context, ids []uint, op entities.BulkCategoryOperation, dryRun bool) (*entities.BulkResult, error)
 }
 
 type categoryService struct {
@@ -309,4 +314,104 @@ func (s *categoryService) GetWithSubCategories(ctx context.Context, id uint) (*e
 	}
 
 	return category, nil
-}
\ No newline at end of file
+}
+
+func (s *categoryService) BulkApply(ctx context.Context, ids []uint, op entities.BulkCategoryOperation, dryRun bool) (*entities.BulkResult, error) {
+	if len(ids) == 0 {
+		return nil, appErrors.NewValidationError("Category IDs are required", "Provide at least one category ID")
+	}
+
+	switch op.Type {
+	case entities.BulkOpSetActive:
+		if op.Active == nil {
+			return nil, appErrors.NewValidationError("Active status is required", "Provide 'active' for set_active")
+		}
+	case entities.BulkOpDelete:
+		// Same rule as single deletion: categories with subcategories cannot be removed
+		for _, id := range ids {
+			category, err := s.categoryRepo.GetWithSubCategories(ctx, id)
+			if err != nil {
+				s.logger.LogError(ctx, err, "Failed to check category subcategories", map[string]interface{}{
+					"category_id": id,
+				})
+				return nil, appErrors.WrapInternalError(err, "Failed to validate category deletion")
+			}
+
+			if category != nil && len(category.SubCategories) > 0 {
+				return nil, appErrors.NewConflictError(fmt.Sprintf("Cannot delete category %d with existing subcategories", id))
+			}
+		}
+	default:
+		return nil, appErrors.NewValidationError("Unsupported bulk operation", fmt.Sprintf("Unknown operation type '%s'", op.Type))
+	}
+
+	result, err := s.categoryRepo.BulkApply(ctx, ids, op, dryRun)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to apply bulk category operation", map[string]interface{}{
+			"operation": op.Type,
+			"dry_run":   dryRun,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to apply bulk category operation")
+	}
+
+	s.logger.LogInfo(ctx, "Bulk category operation applied", map[string]interface{}{
+		"operation": op.Type,
+		"dry_run":   dryRun,
+		"matched":   result.Matched,
+		"affected":  result.Affected,
+	})
+
+	return result, nil
+}
+
+func (s *categoryService) Reorder(ctx context.Context, ids []uint) ([]*entities.Category, error) {
+	if len(ids) == 0 {
+		return nil, appErrors.NewValidationError("Category IDs are required", "Provide the full ordered list of category IDs")
+	}
+
+	if err := s.categoryRepo.Reorder(ctx, ids); err != nil {
+		if errors.Is(err, repositories.ErrOrderMismatch) {
+			return nil, appErrors.NewValidationError("Invalid category order", "The list must contain every category exactly once")
+		}
+		s.logger.LogError(ctx, err, "Failed to reorder categories", map[string]interface{}{
+			"category_ids": ids,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to reorder categories")
+	}
+
+	categories, _, err := s.categoryRepo.GetAll(ctx, entities.CategoryFilter{
+		Sort: entities.Sort{{Field: "display_order"}},
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get reordered categories", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get reordered categories")
+	}
+
+	s.logger.LogInfo(ctx, "Categories reordered successfully", map[string]interface{}{
+		"count": len(ids),
+	})
+
+	return categories, nil
+}
+
+func (s *categoryService) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Category, error) {
+	clone, err := s.categoryRepo.Clone(ctx, id, opts)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to clone category", map[string]interface{}{
+			"category_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to clone category")
+	}
+
+	if clone == nil {
+		return nil, appErrors.NewNotFoundError("Category")
+	}
+
+	s.logger.LogInfo(ctx, "Category cloned successfully", map[string]interface{}{
+		"source_category_id": id,
+		"category_id":        clone.ID,
+		"category_name":      clone.Name,
+	})
+
+	return clone, nil
+}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
+	appErrors "restaurant-menu-api/pkg/errors"
 	"restaurant-menu-api/pkg/logger"
 	"restaurant-menu-api/pkg/utils"
 )
@@ -22,6 +25,12 @@ type ItemService interface {
 	ToggleAvailable(ctx context.Context, id uint) error
 	UpdateDisplayOrder(ctx context.Context, id uint, order int) error
 	UpdatePrice(ctx context.Context, id uint, price float64) error
+	Reorder(ctx context.Context, subCategoryID uint, ids []uint) ([]*entities.Item, error)
+	Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Item, error)
+	BulkApply(ctx context.Context, selector entities.BulkItemSelector, op entities.BulkItemOperation, dryRun bool) (*entities.BulkResult, error)
+	GetPlacements(ctx context.Context, itemID uint) ([]entities.ItemPlacement, error)
+	AddPlacement(ctx context.Context, itemID, subCategoryID uint, displayOrder int) (*entities.ItemPlacement, error)
+	RemovePlacement(ctx context.Context, itemID, subCategoryID uint) error
 }
 
 type itemService struct {
@@ -46,21 +55,17 @@ func (s *itemService) GetByID(ctx context.Context, id uint) (*entities.Item, err
 
 func (s *itemService) GetBySubCategoryID(ctx context.Context, subCategoryID uint) ([]*entities.Item, error) {
 	filter := entities.ItemFilter{
-		SubCategoryID: &subCategoryID,
-		Available:     utils.BoolPtr(true),
-		OrderBy:       "display_order",
-		OrderDir:      "ASC",
+		Available: utils.BoolPtr(true),
 	}
-	
-	items, _, err := s.repo.GetAll(ctx, filter)
-	return items, err
+
+	// Ordered by position within the subcategory
+	return s.repo.GetBySubCategoryID(ctx, subCategoryID, filter)
 }
 
 func (s *itemService) GetFeatured(ctx context.Context, limit int) ([]*entities.Item, error) {
 	filter := entities.ItemFilter{
 		Available: utils.BoolPtr(true),
-		OrderBy:   "created_at",
-		OrderDir:  "DESC",
+		Sort:      entities.Sort{{Field: "created_at", Desc: true}},
 		Limit:     limit,
 	}
 	
@@ -69,8 +74,11 @@ func (s *itemService) GetFeatured(ctx context.Context, limit int) ([]*entities.I
 }
 
 func (s *itemService) Search(ctx context.Context, query string, filter entities.ItemFilter) ([]*entities.Item, *entities.Pagination, error) {
-	filter.Search = query
-	return s.repo.GetAll(ctx, filter)
+	result, err := s.repo.Search(ctx, query, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Items, result.Pagination, nil
 }
 
 func (s *itemService) Create(ctx context.Context, item *entities.Item) error {
@@ -148,3 +156,190 @@ func (s *itemService) UpdatePrice(ctx context.Context, id uint, price float64) e
 	return s.repo.UpdatePrice(ctx, id, price)
 }
 
+func (s *itemService) Reorder(ctx context.Context, subCategoryID uint, ids []uint) ([]*entities.Item, error) {
+	if len(ids) == 0 {
+		return nil, appErrors.NewValidationError("Item IDs are required", "Provide the full ordered list of item IDs")
+	}
+
+	if err := s.repo.Reorder(ctx, subCategoryID, ids); err != nil {
+		if errors.Is(err, repositories.ErrOrderMismatch) {
+			return nil, appErrors.NewValidationError("Invalid item order", "The list must contain every item of the subcategory exactly once")
+		}
+		s.logger.LogError(ctx, err, "Failed to reorder items", map[string]interface{}{
+			"sub_category_id": subCategoryID,
+			"item_ids":        ids,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to reorder items")
+	}
+
+	// The default ordering follows positions within the subcategory
+	return s.repo.GetBySubCategoryID(ctx, subCategoryID, entities.ItemFilter{})
+}
+
+func (s *itemService) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Item, error) {
+	clone, err := s.repo.Clone(ctx, id, opts)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to clone item", map[string]interface{}{
+			"item_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to clone item")
+	}
+
+	if clone == nil {
+		return nil, appErrors.NewNotFoundError("Item")
+	}
+
+	s.logger.LogInfo(ctx, "Item cloned successfully", map[string]interface{}{
+		"source_item_id":  id,
+		"item_id":         clone.ID,
+		"sub_category_id": clone.SubCategoryID,
+	})
+
+	return clone, nil
+}
+
+func (s *itemService) BulkApply(ctx context.Context, selector entities.BulkItemSelector, op entities.BulkItemOperation, dryRun bool) (*entities.BulkResult, error) {
+	if selector.IsEmpty() {
+		return nil, appErrors.NewValidationError("Item selector is required", "Provide ids, sub_category_ids, category_ids, tags or filter")
+	}
+
+	if err := validateBulkItemOperation(&op); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.BulkApply(ctx, selector, op, dryRun)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to apply bulk item operation", map[string]interface{}{
+			"operation": op.Type,
+			"dry_run":   dryRun,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to apply bulk item operation")
+	}
+
+	s.logger.LogInfo(ctx, "Bulk item operation applied", map[string]interface{}{
+		"operation": op.Type,
+		"dry_run":   dryRun,
+		"matched":   result.Matched,
+		"affected":  result.Affected,
+	})
+
+	return result, nil
+}
+
+func (s *itemService) GetPlacements(ctx context.Context, itemID uint) ([]entities.ItemPlacement, error) {
+	item, err := s.repo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get item")
+	}
+	if item == nil {
+		return nil, appErrors.NewNotFoundError("Item")
+	}
+
+	placements, err := s.repo.GetPlacements(ctx, itemID)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get item placements")
+	}
+	return placements, nil
+}
+
+func (s *itemService) AddPlacement(ctx context.Context, itemID, subCategoryID uint, displayOrder int) (*entities.ItemPlacement, error) {
+	item, err := s.repo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get item")
+	}
+	if item == nil {
+		return nil, appErrors.NewNotFoundError("Item")
+	}
+
+	placement := &entities.ItemPlacement{
+		ItemID:        itemID,
+		SubCategoryID: subCategoryID,
+		DisplayOrder:  displayOrder,
+	}
+	if err := s.repo.AddPlacement(ctx, placement); err != nil {
+		if errors.Is(err, repositories.ErrDuplicatePlacement) {
+			return nil, appErrors.NewConflictError("Item is already placed in this subcategory")
+		}
+		if errors.Is(err, repositories.ErrSubCategoryNotFound) {
+			return nil, appErrors.NewValidationError("Invalid subcategory ID", "SubCategory does not exist")
+		}
+		s.logger.LogError(ctx, err, "Failed to add item placement", map[string]interface{}{
+			"item_id":         itemID,
+			"sub_category_id": subCategoryID,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to add item placement")
+	}
+
+	s.logger.LogInfo(ctx, "Item placed in subcategory", map[string]interface{}{
+		"item_id":         itemID,
+		"sub_category_id": subCategoryID,
+	})
+
+	return placement, nil
+}
+
+func (s *itemService) RemovePlacement(ctx context.Context, itemID, subCategoryID uint) error {
+	item, err := s.repo.GetByID(ctx, itemID)
+	if err != nil {
+		return appErrors.WrapInternalError(err, "Failed to get item")
+	}
+	if item == nil {
+		return appErrors.NewNotFoundError("Item")
+	}
+
+	if item.SubCategoryID == subCategoryID {
+		return appErrors.NewConflictError("Cannot remove an item from its primary subcategory; move it to another subcategory instead")
+	}
+
+	removed, err := s.repo.RemovePlacement(ctx, itemID, subCategoryID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to remove item placement", map[string]interface{}{
+			"item_id":         itemID,
+			"sub_category_id": subCategoryID,
+		})
+		return appErrors.WrapInternalError(err, "Failed to remove item placement")
+	}
+	if !removed {
+		return appErrors.NewNotFoundError("Item placement")
+	}
+
+	return nil
+}
+
+func validateBulkItemOperation(op *entities.BulkItemOperation) error {
+	switch op.Type {
+	case entities.BulkOpPriceChange:
+		if op.PriceMode != entities.PriceChangePercentage && op.PriceMode != entities.PriceChangeAbsolute {
+			return appErrors.NewValidationError("Invalid price mode", "price_mode must be 'percentage' or 'absolute'")
+		}
+		if op.PriceMode == entities.PriceChangePercentage && op.Amount < -100 {
+			return appErrors.NewValidationError("Invalid price change", "Percentage decrease cannot exceed 100")
+		}
+		switch op.Rounding {
+		case "", entities.RoundingNone, entities.RoundingNearest, entities.RoundingUp, entities.RoundingDown:
+		default:
+			return appErrors.NewValidationError("Invalid rounding rule", "rounding must be one of: none, nearest, up, down")
+		}
+		if op.RoundTo < 0 {
+			return appErrors.NewValidationError("Invalid rounding step", "round_to must not be negative")
+		}
+	case entities.BulkOpSetAvailability:
+		if op.Available == nil {
+			return appErrors.NewValidationError("Availability is required", "Provide 'available' for set_availability")
+		}
+	case entities.BulkOpMoveSubCategory:
+		if op.SubCategoryID == nil || *op.SubCategoryID == 0 {
+			return appErrors.NewValidationError("Target subcategory is required", "Provide 'sub_category_id' for move_sub_category")
+		}
+	case entities.BulkOpSetCurrency:
+		op.Currency = strings.ToUpper(strings.TrimSpace(op.Currency))
+		if len(op.Currency) != 3 {
+			return appErrors.NewValidationError("Invalid currency", "currency must be a 3-letter ISO code")
+		}
+	case entities.BulkOpDelete:
+	default:
+		return appErrors.NewValidationError("Unsupported bulk operation", fmt.Sprintf("Unknown operation type '%s'", op.Type))
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// lockPollInterval is how often a request waiting on another request's
+// rebuild checks the cache again.
+const lockPollInterval = 50 * time.Millisecond
+
+// menuCacheTag marks every cached menu read, for MenuChanged to drop.
+const menuCacheTag = "menu"
+
+// menuGenerationKey holds when the menu last changed, for servers to tell
+// whether a menu they built may already be out of date.
+const menuGenerationKey = "menu_generation"
+
+// MenuCacheConfig holds how long each kind of menu read stays cached.
+type MenuCacheConfig struct {
+	MenuTTL         time.Duration
+	CategoryMenuTTL time.Duration
+	FeaturedTTL     time.Duration
+	LockTimeout     time.Duration
+}
+
+// MenuChangeListener is told after a write that may change what the menu
+// shows. Source names the kind of data written, for logging.
+type MenuChangeListener interface {
+	MenuChanged(ctx context.Context, source string)
+}
+
+// cachedMenuService serves the complete menu, category menus and featured
+// items from the cache, rebuilding them through the wrapped service on a
+// miss. Only one request rebuilds an entry at a time; the others wait for it.
+type cachedMenuService struct {
+	MenuService
+	store  Cache
+	config MenuCacheConfig
+	logger *logger.Logger
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+
+	// generation counts the changes this server was told about
+	generation atomic.Uint64
+}
+
+// menuGeneration identifies the menu data a rebuild starts from. MenuChanged
+// moves it on, on this server and, through the store, on the others.
+type menuGeneration struct {
+	local  uint64
+	shared int64
+}
+
+// CachedMenuService is a MenuService that also reports its cache use and
+// drops cached menus on change.
+type CachedMenuService interface {
+	MenuService
+	MenuChangeListener
+	CacheStatsReporter
+}
+
+func NewCachedMenuService(service MenuService, store Cache, config MenuCacheConfig, logger *logger.Logger) CachedMenuService {
+	return &cachedMenuService{
+		MenuService: service,
+		store:       store,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+func (s *cachedMenuService) GetCompleteMenu(ctx context.Context, itemExpand entities.Expand) (*MenuResponse, error) {
+	var menu *MenuResponse
+	err := s.cached(ctx, "complete:"+expandCacheKey(itemExpand), s.config.MenuTTL, &menu, func() (err error) {
+		menu, err = s.MenuService.GetCompleteMenu(ctx, itemExpand)
+		return err
+	})
+	return menu, err
+}
+
+func (s *cachedMenuService) GetMenuByCategory(ctx context.Context, categoryID uint, itemExpand entities.Expand) (*MenuCategoryResponse, error) {
+	var menu *MenuCategoryResponse
+	key := fmt.Sprintf("category:%d:%s", categoryID, expandCacheKey(itemExpand))
+	err := s.cached(ctx, key, s.config.CategoryMenuTTL, &menu, func() (err error) {
+		menu, err = s.MenuService.GetMenuByCategory(ctx, categoryID, itemExpand)
+		return err
+	}, fmt.Sprintf("category:%d", categoryID))
+	return menu, err
+}
+
+func (s *cachedMenuService) GetFeaturedItems(ctx context.Context, limit int) ([]*entities.Item, error) {
+	var items []*entities.Item
+	err := s.cached(ctx, fmt.Sprintf("featured:%d", limit), s.config.FeaturedTTL, &items, func() (err error) {
+		items, err = s.MenuService.GetFeaturedItems(ctx, limit)
+		return err
+	})
+	return items, err
+}
+
+func (s *cachedMenuService) MenuChanged(ctx context.Context, source string) {
+	s.invalidations.Add(1)
+
+	// Move the generation on before dropping the entries, so that a rebuild
+	// that read the old data either sees the change or is dropped with them
+	s.generation.Add(1)
+	// The store logs failures; this server still sees its own changes
+	_ = s.store.Set(ctx, menuGenerationKey, time.Now().UnixNano(), 0)
+
+	if err := s.store.InvalidateTags(ctx, menuCacheTag); err != nil {
+		// The store logs the failure; entries still expire with their TTL
+		return
+	}
+	s.logger.LogInfo(ctx, "Menu cache invalidated", map[string]interface{}{
+		"source": source,
+	})
+}
+
+func (s *cachedMenuService) CacheStats() CacheStats {
+	stats := CacheStats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Invalidations: s.invalidations.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// cached fills dest from the cache entry at key, or runs build, which must set
+// dest, and caches the result under tags. On a miss a lock makes concurrent requests
+// wait for a single rebuild; if it takes longer than the lock timeout they
+// build for themselves. A result is not cached when the menu changed while it
+// was being built, since it may show the data from before the change.
+func (s *cachedMenuService) cached(ctx context.Context, key string, ttl time.Duration, dest interface{}, build func() error, tags ...string) error {
+	key = "menu:" + key
+	if err := s.store.Get(ctx, key, dest); err == nil {
+		s.hits.Add(1)
+		return nil
+	}
+	s.misses.Add(1)
+
+	token, err := newLockToken()
+	if err != nil {
+		return build()
+	}
+
+	lockKey := "lock:" + key
+	locked, err := s.store.SetNX(ctx, lockKey, token, s.config.LockTimeout)
+	if err != nil {
+		// The store is failing; serve straight from the database
+		return build()
+	}
+
+	if !locked {
+		if s.waitForRebuild(ctx, key, dest) {
+			return nil
+		}
+		return build()
+	}
+
+	defer func() {
+		// A rebuild outlasting the lock timeout leaves alone the lock another
+		// request has taken since
+		_, _ = s.store.DeleteIfEquals(ctx, lockKey, token)
+	}()
+
+	generation := s.currentGeneration(ctx)
+	if err := build(); err != nil {
+		return err
+	}
+
+	if s.currentGeneration(ctx) != generation {
+		return nil
+	}
+
+	// The store logs failures and the menu is served uncached
+	_ = s.store.SetWithTags(ctx, key, dest, ttl, append([]string{menuCacheTag}, tags...)...)
+	return nil
+}
+
+// currentGeneration returns the generation of the menu data.
+func (s *cachedMenuService) currentGeneration(ctx context.Context) menuGeneration {
+	generation := menuGeneration{local: s.generation.Load()}
+	// A missing entry reads as zero, the generation before any change
+	_ = s.store.Get(ctx, menuGenerationKey, &generation.shared)
+	return generation
+}
+
+// waitForRebuild polls the cache while another request rebuilds key, up to
+// the lock timeout.
+func (s *cachedMenuService) waitForRebuild(ctx context.Context, key string, dest interface{}) bool {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.After(s.config.LockTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline:
+			return false
+		case <-ticker.C:
+			if err := s.store.Get(ctx, key, dest); err == nil {
+				return true
+			}
+		}
+	}
+}
+
+// newLockToken returns a random value identifying the holder of a lock.
+func newLockToken() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
+
+// expandCacheKey tells apart menus built with different item relations.
+func expandCacheKey(expand entities.Expand) string {
+	if expand == nil {
+		return "default"
+	}
+	if len(expand) == 0 {
+		return "none"
+	}
+
+	relations := append([]string(nil), expand...)
+	sort.Strings(relations)
+	return strings.Join(relations, ",")
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"restaurant-menu-api/pkg/logger"
+)
+
+// mapCache is a Cache over a map that ignores expiry. Values are kept
+// JSON-encoded, as the real stores keep them.
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	tags    map[string][]string
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{entries: make(map[string][]byte), tags: make(map[string][]string)}
+}
+
+func (c *mapCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	data, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		return errors.New("cache miss")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (c *mapCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return c.SetWithTags(ctx, key, value, expiration)
+}
+
+func (c *mapCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return false, nil
+	}
+	c.entries[key] = data
+	return true, nil
+}
+
+func (c *mapCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *mapCache) DeleteIfEquals(ctx context.Context, key string, value interface{}) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if string(c.entries[key]) != string(data) {
+		return false, nil
+	}
+	delete(c.entries, key)
+	return true, nil
+}
+
+func (c *mapCache) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = data
+	for _, tag := range tags {
+		c.tags[tag] = append(c.tags[tag], key)
+	}
+	return nil
+}
+
+func (c *mapCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tag := range tags {
+		for _, key := range c.tags[tag] {
+			delete(c.entries, key)
+		}
+		delete(c.tags, tag)
+	}
+	return nil
+}
+
+func (c *mapCache) has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[key]
+	return ok
+}
+
+func newTestCachedMenuService(store Cache) *cachedMenuService {
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+	return NewCachedMenuService(nil, store, MenuCacheConfig{
+		MenuTTL:     time.Minute,
+		LockTimeout: time.Second,
+	}, log).(*cachedMenuService)
+}
+
+func TestCachedStoresRebuiltMenu(t *testing.T) {
+	store := newMapCache()
+	service := newTestCachedMenuService(store)
+	ctx := context.Background()
+
+	builds := 0
+	build := func(dest *string) func() error {
+		return func() error {
+			builds++
+			*dest = "menu"
+			return nil
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		var menu string
+		if err := service.cached(ctx, "complete", time.Minute, &menu, build(&menu)); err != nil {
+			t.Fatal(err)
+		}
+		if menu != "menu" {
+			t.Errorf("menu = %q, want %q", menu, "menu")
+		}
+	}
+
+	if builds != 1 {
+		t.Errorf("built %d times, want 1", builds)
+	}
+	if store.has("lock:menu:complete") {
+		t.Error("rebuild lock was not released")
+	}
+}
+
+func TestCachedSkipsMenuBuiltAcrossAChange(t *testing.T) {
+	store := newMapCache()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		change func(service *cachedMenuService)
+	}{
+		{"on this server", func(service *cachedMenuService) {
+			service.MenuChanged(ctx, "test")
+		}},
+		{"on another server", func(*cachedMenuService) {
+			newTestCachedMenuService(store).MenuChanged(ctx, "test")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestCachedMenuService(store)
+
+			// The menu is read, then changed and invalidated before the
+			// rebuild stores it
+			var menu string
+			err := service.cached(ctx, "complete", time.Minute, &menu, func() error {
+				menu = "old menu"
+				tt.change(service)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if menu != "old menu" {
+				t.Errorf("menu = %q, want the rebuilt menu to be served", menu)
+			}
+			if store.has("menu:complete") {
+				t.Error("menu built before the change was cached")
+			}
+		})
+	}
+}
+
+func TestCachedLeavesLockTakenByAnotherRequest(t *testing.T) {
+	store := newMapCache()
+	service := newTestCachedMenuService(store)
+	ctx := context.Background()
+
+	var menu string
+	err := service.cached(ctx, "complete", time.Minute, &menu, func() error {
+		// The rebuild outlasts the lock, which another request then takes
+		_ = store.Delete(ctx, "lock:menu:complete")
+		if locked, _ := store.SetNX(ctx, "lock:menu:complete", "other", time.Second); !locked {
+			t.Fatal("lock was not free")
+		}
+		menu = "menu"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var holder string
+	if err := store.Get(ctx, "lock:menu:complete", &holder); err != nil || holder != "other" {
+		t.Errorf("lock held by %q, want the other request's lock kept", holder)
+	}
+}
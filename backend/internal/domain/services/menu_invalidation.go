@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+// menuNotifier tells a listener about successful writes of one kind of data.
+type menuNotifier struct {
+	listener MenuChangeListener
+	source   string
+}
+
+func (n menuNotifier) notify(ctx context.Context, err error) {
+	if err == nil {
+		n.listener.MenuChanged(ctx, n.source)
+	}
+}
+
+// notifyBulk notifies after a bulk operation that changed something; dry
+// runs and operations matching nothing leave the cached menu alone.
+func (n menuNotifier) notifyBulk(ctx context.Context, dryRun bool, result *entities.BulkResult, err error) {
+	if err != nil || dryRun || result == nil || result.Affected == 0 {
+		return
+	}
+	n.listener.MenuChanged(ctx, n.source)
+}
+
+// The wrappers below notify a MenuChangeListener after every successful write
+// of their service and pass reads straight through. Each returns the service
+// unwrapped when there is no listener.
+
+type menuInvalidatingCategoryService struct {
+	CategoryService
+	menuNotifier
+}
+
+func NewMenuInvalidatingCategoryService(service CategoryService, listener MenuChangeListener) CategoryService {
+	if listener == nil {
+		return service
+	}
+	return &menuInvalidatingCategoryService{service, menuNotifier{listener, "category"}}
+}
+
+func (s *menuInvalidatingCategoryService) Create(ctx context.Context, req CreateCategoryRequest) (*entities.Category, error) {
+	category, err := s.CategoryService.Create(ctx, req)
+	s.notify(ctx, err)
+	return category, err
+}
+
+func (s *menuInvalidatingCategoryService) Update(ctx context.Context, id uint, req UpdateCategoryRequest) (*entities.Category, error) {
+	category, err := s.CategoryService.Update(ctx, id, req)
+	s.notify(ctx, err)
+	return category, err
+}
+
+func (s *menuInvalidatingCategoryService) Delete(ctx context.Context, id uint) error {
+	err := s.CategoryService.Delete(ctx, id)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingCategoryService) ToggleActive(ctx context.Context, id uint) (*entities.Category, error) {
+	category, err := s.CategoryService.ToggleActive(ctx, id)
+	s.notify(ctx, err)
+	return category, err
+}
+
+func (s *menuInvalidatingCategoryService) UpdateDisplayOrder(ctx context.Context, id uint, order int) (*entities.Category, error) {
+	category, err := s.CategoryService.UpdateDisplayOrder(ctx, id, order)
+	s.notify(ctx, err)
+	return category, err
+}
+
+func (s *menuInvalidatingCategoryService) Reorder(ctx context.Context, ids []uint) ([]*entities.Category, error) {
+	categories, err := s.CategoryService.Reorder(ctx, ids)
+	s.notify(ctx, err)
+	return categories, err
+}
+
+func (s *menuInvalidatingCategoryService) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Category, error) {
+	category, err := s.CategoryService.Clone(ctx, id, opts)
+	s.notify(ctx, err)
+	return category, err
+}
+
+func (s *menuInvalidatingCategoryService) BulkApply(ctx context.Context, ids []uint, op entities.BulkCategoryOperation, dryRun bool) (*entities.BulkResult, error) {
+	result, err := s.CategoryService.BulkApply(ctx, ids, op, dryRun)
+	s.notifyBulk(ctx, dryRun, result, err)
+	return result, err
+}
+
+type menuInvalidatingSubCategoryService struct {
+	SubCategoryService
+	menuNotifier
+}
+
+func NewMenuInvalidatingSubCategoryService(service SubCategoryService, listener MenuChangeListener) SubCategoryService {
+	if listener == nil {
+		return service
+	}
+	return &menuInvalidatingSubCategoryService{service, menuNotifier{listener, "subcategory"}}
+}
+
+func (s *menuInvalidatingSubCategoryService) Create(ctx context.Context, subCategory *entities.SubCategory) error {
+	err := s.SubCategoryService.Create(ctx, subCategory)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingSubCategoryService) Update(ctx context.Context, id uint, subCategory *entities.SubCategory) error {
+	err := s.SubCategoryService.Update(ctx, id, subCategory)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingSubCategoryService) Delete(ctx context.Context, id uint) error {
+	err := s.SubCategoryService.Delete(ctx, id)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingSubCategoryService) ToggleActive(ctx context.Context, id uint) error {
+	err := s.SubCategoryService.ToggleActive(ctx, id)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingSubCategoryService) UpdateDisplayOrder(ctx context.Context, id uint, order int) error {
+	err := s.SubCategoryService.UpdateDisplayOrder(ctx, id, order)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingSubCategoryService) Reorder(ctx context.Context, categoryID uint, parentID *uint, ids []uint) ([]*entities.SubCategory, error) {
+	subCategories, err := s.SubCategoryService.Reorder(ctx, categoryID, parentID, ids)
+	s.notify(ctx, err)
+	return subCategories, err
+}
+
+func (s *menuInvalidatingSubCategoryService) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.SubCategory, error) {
+	subCategory, err := s.SubCategoryService.Clone(ctx, id, opts)
+	s.notify(ctx, err)
+	return subCategory, err
+}
+
+func (s *menuInvalidatingSubCategoryService) Move(ctx context.Context, id, categoryID uint, parentID *uint) (*entities.SubCategory, error) {
+	subCategory, err := s.SubCategoryService.Move(ctx, id, categoryID, parentID)
+	s.notify(ctx, err)
+	return subCategory, err
+}
+
+type menuInvalidatingItemService struct {
+	ItemService
+	menuNotifier
+}
+
+func NewMenuInvalidatingItemService(service ItemService, listener MenuChangeListener) ItemService {
+	if listener == nil {
+		return service
+	}
+	return &menuInvalidatingItemService{service, menuNotifier{listener, "item"}}
+}
+
+func (s *menuInvalidatingItemService) Create(ctx context.Context, item *entities.Item) error {
+	err := s.ItemService.Create(ctx, item)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingItemService) Update(ctx context.Context, id uint, item *entities.Item) error {
+	err := s.ItemService.Update(ctx, id, item)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingItemService) Delete(ctx context.Context, id uint) error {
+	err := s.ItemService.Delete(ctx, id)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingItemService) ToggleAvailable(ctx context.Context, id uint) error {
+	err := s.ItemService.ToggleAvailable(ctx, id)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingItemService) UpdateDisplayOrder(ctx context.Context, id uint, order int) error {
+	err := s.ItemService.UpdateDisplayOrder(ctx, id, order)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingItemService) UpdatePrice(ctx context.Context, id uint, price float64) error {
+	err := s.ItemService.UpdatePrice(ctx, id, price)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingItemService) Reorder(ctx context.Context, subCategoryID uint, ids []uint) ([]*entities.Item, error) {
+	items, err := s.ItemService.Reorder(ctx, subCategoryID, ids)
+	s.notify(ctx, err)
+	return items, err
+}
+
+func (s *menuInvalidatingItemService) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Item, error) {
+	item, err := s.ItemService.Clone(ctx, id, opts)
+	s.notify(ctx, err)
+	return item, err
+}
+
+func (s *menuInvalidatingItemService) BulkApply(ctx context.Context, selector entities.BulkItemSelector, op entities.BulkItemOperation, dryRun bool) (*entities.BulkResult, error) {
+	result, err := s.ItemService.BulkApply(ctx, selector, op, dryRun)
+	s.notifyBulk(ctx, dryRun, result, err)
+	return result, err
+}
+
+func (s *menuInvalidatingItemService) AddPlacement(ctx context.Context, itemID, subCategoryID uint, displayOrder int) (*entities.ItemPlacement, error) {
+	placement, err := s.ItemService.AddPlacement(ctx, itemID, subCategoryID, displayOrder)
+	s.notify(ctx, err)
+	return placement, err
+}
+
+func (s *menuInvalidatingItemService) RemovePlacement(ctx context.Context, itemID, subCategoryID uint) error {
+	err := s.ItemService.RemovePlacement(ctx, itemID, subCategoryID)
+	s.notify(ctx, err)
+	return err
+}
+
+type menuInvalidatingRestaurantService struct {
+	RestaurantService
+	menuNotifier
+}
+
+func NewMenuInvalidatingRestaurantService(service RestaurantService, listener MenuChangeListener) RestaurantService {
+	if listener == nil {
+		return service
+	}
+	return &menuInvalidatingRestaurantService{service, menuNotifier{listener, "restaurant"}}
+}
+
+func (s *menuInvalidatingRestaurantService) UpdateInfo(ctx context.Context, info *entities.RestaurantInfo) error {
+	err := s.RestaurantService.UpdateInfo(ctx, info)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingRestaurantService) CreateInfo(ctx context.Context, info *entities.RestaurantInfo) error {
+	err := s.RestaurantService.CreateInfo(ctx, info)
+	s.notify(ctx, err)
+	return err
+}
+
+func (s *menuInvalidatingRestaurantService) UpdateOperatingHours(ctx context.Context, hours []entities.OperatingHour) error {
+	err := s.RestaurantService.UpdateOperatingHours(ctx, hours)
+	s.notify(ctx, err)
+	return err
+}
@@ -2,25 +2,45 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
 	"restaurant-menu-api/pkg/logger"
 	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/utils"
+)
+
+const (
+	suggestCacheTTL        = 2 * time.Minute
+	maxSuggestPrefixLength = 100
+	maxSearchQueryLength   = 200
 )
 
 type MenuService interface {
-	GetCompleteMenu(ctx context.Context) (*MenuResponse, error)
-	GetMenuByCategory(ctx context.Context, categoryID uint) (*MenuCategoryResponse, error)
+	GetCompleteMenu(ctx context.Context, itemExpand entities.Expand) (*MenuResponse, error)
+	GetMenuByCategory(ctx context.Context, categoryID uint, itemExpand entities.Expand) (*MenuCategoryResponse, error)
 	SearchMenuItems(ctx context.Context, query string, filters SearchFilters) (*SearchResponse, error)
 	GetFeaturedItems(ctx context.Context, limit int) ([]*entities.Item, error)
+	GetMenuTree(ctx context.Context) (*MenuTreeResponse, error)
+	GetCategoryTree(ctx context.Context, categoryID uint) (*MenuTreeCategory, error)
+	GetMenuBySlug(ctx context.Context, path MenuSlugPath) (*MenuSlugResponse, error)
+	Suggest(ctx context.Context, query string, limit int) (*SuggestResponse, error)
 }
 
 type menuService struct {
 	categoryRepo    repositories.CategoryRepository
 	subCategoryRepo repositories.SubCategoryRepository
 	itemRepo        repositories.ItemRepository
+	slugRepo        repositories.SlugRedirectRepository
+	searchQueryRepo repositories.SearchQueryRepository
+	dictionaryRepo  repositories.SearchDictionaryRepository
+	cache           Cache
 	logger          *logger.Logger
+
+	similarityThreshold float64
 }
 
 type MenuResponse struct {
@@ -38,16 +58,65 @@ type MenuSubCategory struct {
 	Items []*entities.Item `json:"items"`
 }
 
+// MenuTreeResponse is the menu with subcategories nested to any depth.
+type MenuTreeResponse struct {
+	Categories []*MenuTreeCategory `json:"categories"`
+	Stats      MenuStats           `json:"stats"`
+}
+
+type MenuTreeCategory struct {
+	*entities.Category
+	Children []*MenuTreeNode `json:"children"`
+}
+
+type MenuTreeNode struct {
+	*entities.SubCategory
+	Items    []*entities.Item `json:"items"`
+	Children []*MenuTreeNode  `json:"children"`
+}
+
 type MenuCategoryResponse struct {
 	Category      *entities.Category    `json:"category"`
 	SubCategories []*MenuSubCategory    `json:"sub_categories"`
 	Stats         MenuCategoryStats     `json:"stats"`
 }
 
+// MenuSlugPath addresses a category, subcategory or item below /menu by slug.
+type MenuSlugPath struct {
+	CategorySlug    string
+	SubCategorySlug string
+	ItemSlug        string
+}
+
+func (p MenuSlugPath) String() string {
+	parts := []string{p.CategorySlug}
+	if p.SubCategorySlug != "" {
+		parts = append(parts, p.SubCategorySlug)
+		if p.ItemSlug != "" {
+			parts = append(parts, p.ItemSlug)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// MenuSlugResponse holds whichever of category, subcategory or item the path
+// addressed. Canonical is set instead when the path used an outdated slug.
+type MenuSlugResponse struct {
+	Category    *MenuCategoryResponse `json:"category,omitempty"`
+	SubCategory *MenuSubCategory      `json:"sub_category,omitempty"`
+	Item        *entities.Item        `json:"item,omitempty"`
+	Breadcrumbs []entities.Breadcrumb `json:"breadcrumbs"`
+	Canonical   *MenuSlugPath         `json:"-"`
+}
+
 type SearchResponse struct {
-	Items      []*entities.Item      `json:"items"`
-	Pagination *entities.Pagination  `json:"pagination,omitempty"`
-	Stats      SearchStats           `json:"stats"`
+	// SearchID identifies the search when reporting a result click
+	SearchID   uint                       `json:"search_id,omitempty"`
+	Items      []*entities.Item           `json:"items"`
+	Highlights []entities.SearchHighlight `json:"highlights"`
+	Facets     *entities.SearchFacets     `json:"facets"`
+	Pagination *entities.Pagination       `json:"pagination,omitempty"`
+	Stats      SearchStats                `json:"stats"`
 }
 
 type SearchFilters struct {
@@ -58,6 +127,15 @@ type SearchFilters struct {
 	Available     *bool    `json:"available"`
 	Limit         int      `json:"limit"`
 	Offset        int      `json:"offset"`
+	// Locale selects the synonyms and stop words applied to the query
+	Locale string `json:"locale"`
+
+	// Facet selections; see entities.ItemFilter
+	CategoryIDs    []uint   `json:"category_ids"`
+	SubCategoryIDs []uint   `json:"sub_category_ids"`
+	DietaryLabels  []string `json:"dietary_labels"`
+	Tags           []string `json:"tags"`
+	PriceBuckets   []string `json:"price_buckets"`
 }
 
 type MenuStats struct {
@@ -73,31 +151,54 @@ type MenuCategoryStats struct {
 	AvailableItems     int `json:"available_items"`
 }
 
+// SuggestResponse lists search-as-you-type suggestions for a prefix.
+type SuggestResponse struct {
+	Query      string                `json:"query"`
+	Items      []entities.Suggestion `json:"items"`
+	Categories []entities.Suggestion `json:"categories"`
+	Queries    []entities.Suggestion `json:"queries"`
+}
+
 type SearchStats struct {
 	TotalResults int `json:"total_results"`
 	SearchQuery  string `json:"search_query"`
+	// Fuzzy is set when nothing matched exactly and the results are close
+	// spellings of the query instead
+	Fuzzy      bool   `json:"fuzzy"`
+	DidYouMean string `json:"did_you_mean,omitempty"`
 }
 
 func NewMenuService(
 	categoryRepo repositories.CategoryRepository,
 	subCategoryRepo repositories.SubCategoryRepository,
 	itemRepo repositories.ItemRepository,
+	slugRepo repositories.SlugRedirectRepository,
+	searchQueryRepo repositories.SearchQueryRepository,
+	dictionaryRepo repositories.SearchDictionaryRepository,
+	cache Cache,
+	similarityThreshold float64,
 	logger *logger.Logger,
 ) MenuService {
 	return &menuService{
-		categoryRepo:    categoryRepo,
-		subCategoryRepo: subCategoryRepo,
-		itemRepo:        itemRepo,
-		logger:          logger,
+		categoryRepo:        categoryRepo,
+		subCategoryRepo:     subCategoryRepo,
+		itemRepo:            itemRepo,
+		slugRepo:            slugRepo,
+		searchQueryRepo:     searchQueryRepo,
+		dictionaryRepo:      dictionaryRepo,
+		cache:               cache,
+		logger:              logger,
+		similarityThreshold: similarityThreshold,
 	}
 }
 
-func (s *menuService) GetCompleteMenu(ctx context.Context) (*MenuResponse, error) {
+// GetCompleteMenu returns the active menu. itemExpand picks the relations
+// loaded with each item.
+func (s *menuService) GetCompleteMenu(ctx context.Context, itemExpand entities.Expand) (*MenuResponse, error) {
 	// Get all active categories with subcategories
 	categoryFilter := entities.CategoryFilter{
-		Active:   boolPtr(true),
-		OrderBy:  "display_order",
-		OrderDir: "ASC",
+		Active: boolPtr(true),
+		Sort:   entities.Sort{{Field: "display_order"}},
 	}
 
 	categories, err := s.categoryRepo.GetAllWithSubCategories(ctx, categoryFilter)
@@ -106,34 +207,37 @@ func (s *menuService) GetCompleteMenu(ctx context.Context) (*MenuResponse, error
 		return nil, appErrors.WrapInternalError(err, "Failed to get menu categories")
 	}
 
+	// Load the items of every subcategory at once, in their position there
+	var subCategoryIDs []uint
+	for _, category := range categories {
+		subCategoryIDs = append(subCategoryIDs, subCategoryIDsOf(category.SubCategories)...)
+	}
+
+	itemsBySubCategory, err := s.itemRepo.GetBySubCategoryIDs(ctx, subCategoryIDs, entities.ItemFilter{
+		Available: boolPtr(true),
+		Expand:    itemExpand,
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get items for complete menu", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get menu items")
+	}
+
 	menuCategories := make([]*MenuCategory, 0, len(categories))
 	totalSubCategories := 0
 	totalItems := 0
 	availableItems := 0
 
 	for _, category := range categories {
+		sections := menuSections(category.SubCategories)
 		menuCategory := &MenuCategory{
 			Category:      category,
-			SubCategories: make([]*MenuSubCategory, 0, len(category.SubCategories)),
+			SubCategories: make([]*MenuSubCategory, 0, len(sections)),
 		}
 
-		totalSubCategories += len(category.SubCategories)
+		totalSubCategories += len(sections)
 
-		for _, subCategory := range category.SubCategories {
-			// Get items for this subcategory
-			itemFilter := entities.ItemFilter{
-				Available: boolPtr(true),
-				OrderBy:   "display_order",
-				OrderDir:  "ASC",
-			}
-
-			items, err := s.itemRepo.GetBySubCategoryID(ctx, subCategory.ID, itemFilter)
-			if err != nil {
-				s.logger.LogError(ctx, err, "Failed to get items for subcategory", map[string]interface{}{
-					"subcategory_id": subCategory.ID,
-				})
-				continue
-			}
+		for _, subCategory := range sections {
+			items := subCategoryItems(itemsBySubCategory, subCategory.ID)
 
 			menuSubCategory := &MenuSubCategory{
 				SubCategory: &subCategory,
@@ -161,7 +265,7 @@ func (s *menuService) GetCompleteMenu(ctx context.Context) (*MenuResponse, error
 	}, nil
 }
 
-func (s *menuService) GetMenuByCategory(ctx context.Context, categoryID uint) (*MenuCategoryResponse, error) {
+func (s *menuService) GetMenuByCategory(ctx context.Context, categoryID uint, itemExpand entities.Expand) (*MenuCategoryResponse, error) {
 	// Get category with subcategories
 	category, err := s.categoryRepo.GetWithSubCategories(ctx, categoryID)
 	if err != nil {
@@ -175,24 +279,24 @@ func (s *menuService) GetMenuByCategory(ctx context.Context, categoryID uint) (*
 		return nil, appErrors.NewNotFoundError("Category")
 	}
 
-	menuSubCategories := make([]*MenuSubCategory, 0, len(category.SubCategories))
+	// Load the items of every subcategory at once, in their position there
+	itemsBySubCategory, err := s.itemRepo.GetBySubCategoryIDs(ctx, subCategoryIDsOf(category.SubCategories), entities.ItemFilter{
+		Expand: itemExpand,
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get items for category menu", map[string]interface{}{
+			"category_id": categoryID,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get menu items")
+	}
+
+	sections := menuSections(category.SubCategories)
+	menuSubCategories := make([]*MenuSubCategory, 0, len(sections))
 	totalItems := 0
 	availableItems := 0
 
-	for _, subCategory := range category.SubCategories {
-		// Get items for this subcategory
-		itemFilter := entities.ItemFilter{
-			OrderBy:  "display_order",
-			OrderDir: "ASC",
-		}
-
-		items, err := s.itemRepo.GetBySubCategoryID(ctx, subCategory.ID, itemFilter)
-		if err != nil {
-			s.logger.LogError(ctx, err, "Failed to get items for subcategory", map[string]interface{}{
-				"subcategory_id": subCategory.ID,
-			})
-			continue
-		}
+	for _, subCategory := range sections {
+		items := subCategoryItems(itemsBySubCategory, subCategory.ID)
 
 		menuSubCategory := &MenuSubCategory{
 			SubCategory: &subCategory,
@@ -211,7 +315,7 @@ func (s *menuService) GetMenuByCategory(ctx context.Context, categoryID uint) (*
 	}
 
 	stats := MenuCategoryStats{
-		TotalSubCategories: len(category.SubCategories),
+		TotalSubCategories: len(sections),
 		TotalItems:         totalItems,
 		AvailableItems:     availableItems,
 	}
@@ -237,13 +341,42 @@ func (s *menuService) SearchMenuItems(ctx context.Context, query string, filters
 		Limit:         filters.Limit,
 		Offset:        filters.Offset,
 		IncludeCount:  true,
+
+		CategoryIDs:    filters.CategoryIDs,
+		SubCategoryIDs: filters.SubCategoryIDs,
+		DietaryLabels:  filters.DietaryLabels,
+		Tags:           filters.Tags,
+		PriceBuckets:   filters.PriceBuckets,
+		IncludeFacets:  true,
+	}
+
+	for _, label := range filters.DietaryLabels {
+		if !utils.Contains(entities.DietaryLabels, label) {
+			return nil, appErrors.NewValidationError("Invalid dietary label", "Allowed labels: "+strings.Join(entities.DietaryLabels, ", "))
+		}
+	}
+
+	for _, key := range filters.PriceBuckets {
+		if _, ok := entities.PriceBucketByKey(key); !ok {
+			allowed := make([]string, len(entities.PriceBuckets))
+			for i, bucket := range entities.PriceBuckets {
+				allowed[i] = bucket.Key
+			}
+			return nil, appErrors.NewValidationError("Invalid price bucket", "Allowed buckets: "+strings.Join(allowed, ", "))
+		}
+	}
+
+	locale, err := normalizeSearchLocale(filters.Locale)
+	if err != nil {
+		return nil, err
 	}
+	itemFilter.Dictionary = s.searchDictionary(ctx, locale)
 
 	if itemFilter.Limit == 0 {
 		itemFilter.Limit = 20 // Default limit
 	}
 
-	items, pagination, err := s.itemRepo.Search(ctx, query, itemFilter)
+	result, err := s.itemRepo.Search(ctx, query, itemFilter)
 	if err != nil {
 		s.logger.LogError(ctx, err, "Failed to search menu items", map[string]interface{}{
 			"search_query": query,
@@ -253,17 +386,164 @@ func (s *menuService) SearchMenuItems(ctx context.Context, query string, filters
 	}
 
 	stats := SearchStats{
-		TotalResults: int(pagination.Total),
+		TotalResults: int(result.Pagination.Total),
 		SearchQuery:  query,
 	}
 
+	// Fall back to close spellings when the words matched nothing at all
+	if result.Pagination.Total == 0 {
+		similar, err := s.itemRepo.SearchSimilar(ctx, query, s.similarityThreshold, itemFilter)
+		if err != nil {
+			s.logger.LogError(ctx, err, "Failed to fuzzy search menu items", map[string]interface{}{
+				"search_query": query,
+				"filters":      filters,
+			})
+			return nil, appErrors.WrapInternalError(err, "Failed to search menu items")
+		}
+
+		if similar.Pagination.Total > 0 {
+			result = similar
+			stats.TotalResults = int(similar.Pagination.Total)
+			stats.Fuzzy = true
+		}
+
+		// A failed suggestion shouldn't fail the search
+		suggestion, err := s.itemRepo.SuggestQuery(ctx, query, s.similarityThreshold)
+		if err != nil {
+			s.logger.LogError(ctx, err, "Failed to suggest search query", map[string]interface{}{
+				"search_query": query,
+			})
+		}
+		stats.DidYouMean = suggestion
+	}
+
+	// Later pages of the same search aren't counted again
+	var searchID uint
+	if normalized := normalizeSearchQuery(query); normalized != "" && filters.Offset == 0 {
+		event := &entities.SearchEvent{
+			Query:       normalized,
+			ResultCount: stats.TotalResults,
+			Fuzzy:       stats.Fuzzy,
+			Filters: entities.SearchEventFilters{
+				CategoryIDs:    filters.CategoryIDs,
+				SubCategoryIDs: filters.SubCategoryIDs,
+				DietaryLabels:  filters.DietaryLabels,
+				Tags:           filters.Tags,
+				PriceBuckets:   filters.PriceBuckets,
+				MinPrice:       filters.MinPrice,
+				MaxPrice:       filters.MaxPrice,
+			},
+		}
+		if err := s.searchQueryRepo.Record(ctx, event); err != nil {
+			s.logger.LogError(ctx, err, "Failed to record search query", map[string]interface{}{
+				"search_query": normalized,
+			})
+		} else {
+			searchID = event.ID
+		}
+	}
+
 	return &SearchResponse{
-		Items:      items,
-		Pagination: pagination,
+		SearchID:   searchID,
+		Items:      result.Items,
+		Highlights: result.Highlights,
+		Facets:     result.Facets,
+		Pagination: result.Pagination,
 		Stats:      stats,
 	}, nil
 }
 
+// Suggest returns item names, categories and popular queries starting with
+// query, cached briefly since it runs on every keystroke.
+func (s *menuService) Suggest(ctx context.Context, query string, limit int) (*SuggestResponse, error) {
+	prefix := normalizeSearchQuery(query)
+	if prefix == "" {
+		return nil, appErrors.NewBadRequestError("Search query is required", "")
+	}
+	if runes := []rune(prefix); len(runes) > maxSuggestPrefixLength {
+		prefix = string(runes[:maxSuggestPrefixLength])
+	}
+
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 10 {
+		limit = 10
+	}
+
+	cacheKey := fmt.Sprintf("suggest:%d:%s", limit, prefix)
+	if s.cache != nil {
+		var cached SuggestResponse
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	items, err := s.itemRepo.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to suggest items", map[string]interface{}{
+			"prefix": prefix,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get suggestions")
+	}
+
+	categories, err := s.categoryRepo.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to suggest categories", map[string]interface{}{
+			"prefix": prefix,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get suggestions")
+	}
+
+	queries, err := s.searchQueryRepo.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to suggest popular queries", map[string]interface{}{
+			"prefix": prefix,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get suggestions")
+	}
+
+	suggestions := &SuggestResponse{
+		Query:      prefix,
+		Items:      nonNilSuggestions(items),
+		Categories: nonNilSuggestions(categories),
+		Queries:    nonNilSuggestions(queries),
+	}
+
+	// The client logs failures and suggestions still work uncached
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cacheKey, suggestions, suggestCacheTTL)
+	}
+
+	return suggestions, nil
+}
+
+// searchDictionary loads the synonyms and stop words of a locale, cached until
+// an admin changes them. Search carries on without them if loading fails.
+func (s *menuService) searchDictionary(ctx context.Context, locale string) *entities.SearchDictionary {
+	cacheKey := searchDictionaryCacheKey(locale)
+	if s.cache != nil {
+		var cached entities.SearchDictionary
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			return &cached
+		}
+	}
+
+	dictionary, err := s.dictionaryRepo.GetDictionary(ctx, locale)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to load search dictionary", map[string]interface{}{
+			"locale": locale,
+		})
+		return nil
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cacheKey, dictionary, searchDictionaryCacheTTL)
+	}
+
+	return dictionary
+}
+
 func (s *menuService) GetFeaturedItems(ctx context.Context, limit int) ([]*entities.Item, error) {
 	if limit <= 0 {
 		limit = 10
@@ -283,4 +563,386 @@ func (s *menuService) GetFeaturedItems(ctx context.Context, limit int) ([]*entit
 // Helper function
 func boolPtr(b bool) *bool {
 	return &b
-}
\ No newline at end of file
+}
+
+func (s *menuService) GetMenuTree(ctx context.Context) (*MenuTreeResponse, error) {
+	categoryFilter := entities.CategoryFilter{
+		Active: boolPtr(true),
+		Sort:   entities.Sort{{Field: "display_order"}},
+	}
+
+	categories, err := s.categoryRepo.GetAllWithSubCategories(ctx, categoryFilter)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get categories for menu tree", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get menu categories")
+	}
+
+	// Load the items of every subcategory at once, in their position there
+	var subCategoryIDs []uint
+	for _, category := range categories {
+		subCategoryIDs = append(subCategoryIDs, subCategoryIDsOf(category.SubCategories)...)
+	}
+
+	itemsBySubCategory, err := s.itemRepo.GetBySubCategoryIDs(ctx, subCategoryIDs, entities.ItemFilter{Available: boolPtr(true)})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get items for menu tree", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get menu items")
+	}
+
+	stats := MenuStats{TotalCategories: len(categories)}
+	treeCategories := make([]*MenuTreeCategory, 0, len(categories))
+
+	for _, category := range categories {
+		children, subCategoryCount, itemCount := buildMenuTree(category.SubCategories, itemsBySubCategory)
+		category.SubCategories = nil
+
+		treeCategories = append(treeCategories, &MenuTreeCategory{
+			Category: category,
+			Children: children,
+		})
+
+		stats.TotalSubCategories += subCategoryCount
+		stats.TotalItems += itemCount
+		stats.AvailableItems += itemCount // Only available items are listed
+	}
+
+	return &MenuTreeResponse{
+		Categories: treeCategories,
+		Stats:      stats,
+	}, nil
+}
+
+func (s *menuService) GetCategoryTree(ctx context.Context, categoryID uint) (*MenuTreeCategory, error) {
+	category, err := s.categoryRepo.GetWithSubCategories(ctx, categoryID)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get category", map[string]interface{}{
+			"category_id": categoryID,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get category")
+	}
+
+	if category == nil {
+		return nil, appErrors.NewNotFoundError("Category")
+	}
+
+	itemsBySubCategory, err := s.itemRepo.GetBySubCategoryIDs(ctx, subCategoryIDsOf(category.SubCategories), entities.ItemFilter{})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get items for category tree", map[string]interface{}{
+			"category_id": categoryID,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get menu items")
+	}
+
+	children, _, _ := buildMenuTree(category.SubCategories, itemsBySubCategory)
+	category.SubCategories = nil
+
+	return &MenuTreeCategory{
+		Category: category,
+		Children: children,
+	}, nil
+}
+
+// buildMenuTree nests a category's subcategories, with their items, and
+// returns the top-level nodes along with the number of subcategories and items
+// included. Subcategories below one that is missing from the list are left out.
+func buildMenuTree(subCategories []entities.SubCategory, itemsBySubCategory map[uint][]*entities.Item) ([]*MenuTreeNode, int, int) {
+	sorted := make([]*entities.SubCategory, 0, len(subCategories))
+	for i := range subCategories {
+		sorted = append(sorted, &subCategories[i])
+	}
+	entities.SortSubCategoryTree(sorted)
+
+	roots := make([]*MenuTreeNode, 0)
+	nodes := make(map[uint]*MenuTreeNode, len(sorted))
+	itemCount := 0
+
+	for _, subCategory := range sorted {
+		var parent *MenuTreeNode
+		if subCategory.ParentID != nil {
+			var ok bool
+			if parent, ok = nodes[*subCategory.ParentID]; !ok {
+				continue
+			}
+		}
+
+		node := &MenuTreeNode{
+			SubCategory: subCategory,
+			Items:       subCategoryItems(itemsBySubCategory, subCategory.ID),
+			Children:    make([]*MenuTreeNode, 0),
+		}
+		nodes[subCategory.ID] = node
+		itemCount += len(node.Items)
+
+		if parent == nil {
+			roots = append(roots, node)
+		} else {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	return roots, len(nodes), itemCount
+}
+
+// menuSections orders a category's flat subcategory list for the flat menu
+// listings: each subcategory comes once, followed by the ones nested below it,
+// siblings in display order. Subcategories below a parent missing from the
+// list are dropped with it, as in the menu tree.
+func menuSections(subCategories []entities.SubCategory) []entities.SubCategory {
+	sorted := make([]*entities.SubCategory, 0, len(subCategories))
+	for i := range subCategories {
+		sorted = append(sorted, &subCategories[i])
+	}
+	entities.SortSubCategoryTree(sorted)
+
+	var roots []*entities.SubCategory
+	children := make(map[uint][]*entities.SubCategory)
+	for _, subCategory := range sorted {
+		if subCategory.ParentID == nil {
+			roots = append(roots, subCategory)
+		} else {
+			children[*subCategory.ParentID] = append(children[*subCategory.ParentID], subCategory)
+		}
+	}
+
+	sections := make([]entities.SubCategory, 0, len(sorted))
+	var visit func(nodes []*entities.SubCategory)
+	visit = func(nodes []*entities.SubCategory) {
+		for _, node := range nodes {
+			sections = append(sections, *node)
+			visit(children[node.ID])
+		}
+	}
+	visit(roots)
+
+	return sections
+}
+
+// subCategoryIDsOf lists the IDs of subCategories.
+func subCategoryIDsOf(subCategories []entities.SubCategory) []uint {
+	ids := make([]uint, len(subCategories))
+	for i, subCategory := range subCategories {
+		ids[i] = subCategory.ID
+	}
+	return ids
+}
+
+// subCategoryItems returns the items loaded for a subcategory, never nil so it
+// encodes as an empty list.
+func subCategoryItems(itemsBySubCategory map[uint][]*entities.Item, subCategoryID uint) []*entities.Item {
+	if items, ok := itemsBySubCategory[subCategoryID]; ok {
+		return items
+	}
+	return []*entities.Item{}
+}
+
+// GetMenuBySlug resolves a public slug path. Slugs an entity has since given up
+// are followed through the redirect history and reported via Canonical.
+func (s *menuService) GetMenuBySlug(ctx context.Context, path MenuSlugPath) (*MenuSlugResponse, error) {
+	redirected := false
+
+	category, err := s.categoryRepo.GetBySlug(ctx, path.CategorySlug)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get category")
+	}
+	if category == nil {
+		id, err := s.redirectedEntityID(ctx, entities.SlugEntityCategory, 0, path.CategorySlug)
+		if err != nil {
+			return nil, err
+		}
+		if id != 0 {
+			if category, err = s.categoryRepo.GetByID(ctx, id); err != nil {
+				return nil, appErrors.WrapInternalError(err, "Failed to get category")
+			}
+			redirected = true
+		}
+	}
+	if category == nil || !category.Active {
+		return nil, appErrors.NewNotFoundError("Category")
+	}
+
+	canonical := MenuSlugPath{CategorySlug: category.Slug}
+
+	if path.SubCategorySlug == "" {
+		if redirected {
+			return &MenuSlugResponse{Canonical: &canonical}, nil
+		}
+
+		menu, err := s.GetMenuByCategory(ctx, category.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &MenuSlugResponse{
+			Category:    menu,
+			Breadcrumbs: []entities.Breadcrumb{categoryBreadcrumb(category)},
+		}, nil
+	}
+
+	subCategory, err := s.subCategoryRepo.GetBySlugInCategory(ctx, category.ID, path.SubCategorySlug)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get subcategory")
+	}
+	if subCategory == nil {
+		id, err := s.redirectedEntityID(ctx, entities.SlugEntitySubCategory, category.ID, path.SubCategorySlug)
+		if err != nil {
+			return nil, err
+		}
+		if id != 0 {
+			if subCategory, err = s.subCategoryRepo.GetByID(ctx, id); err != nil {
+				return nil, appErrors.WrapInternalError(err, "Failed to get subcategory")
+			}
+			redirected = true
+		}
+	}
+	if subCategory == nil || !subCategory.Active || subCategory.Category == nil || !subCategory.Category.Active {
+		return nil, appErrors.NewNotFoundError("SubCategory")
+	}
+
+	// The subcategory may have moved to another category since
+	category = subCategory.Category
+	canonical = MenuSlugPath{CategorySlug: category.Slug, SubCategorySlug: subCategory.Slug}
+
+	if path.ItemSlug == "" {
+		if redirected {
+			return &MenuSlugResponse{Canonical: &canonical}, nil
+		}
+
+		items, err := s.itemRepo.GetBySubCategoryID(ctx, subCategory.ID, entities.ItemFilter{Available: boolPtr(true)})
+		if err != nil {
+			s.logger.LogError(ctx, err, "Failed to get items for subcategory", map[string]interface{}{
+				"subcategory_id": subCategory.ID,
+			})
+			return nil, appErrors.WrapInternalError(err, "Failed to get items")
+		}
+
+		breadcrumbs, err := s.subCategoryBreadcrumbs(ctx, category, subCategory)
+		if err != nil {
+			return nil, err
+		}
+		return &MenuSlugResponse{
+			SubCategory: &MenuSubCategory{SubCategory: subCategory, Items: items},
+			Breadcrumbs: breadcrumbs,
+		}, nil
+	}
+
+	item, err := s.itemRepo.GetBySlug(ctx, path.ItemSlug)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get item")
+	}
+	if item == nil {
+		id, err := s.redirectedEntityID(ctx, entities.SlugEntityItem, 0, path.ItemSlug)
+		if err != nil {
+			return nil, err
+		}
+		if id != 0 {
+			if item, err = s.itemRepo.GetByID(ctx, id); err != nil {
+				return nil, appErrors.WrapInternalError(err, "Failed to get item")
+			}
+			redirected = true
+		}
+	}
+	if item == nil {
+		return nil, appErrors.NewNotFoundError("Item")
+	}
+
+	// Items no longer listed in the subcategory are sent to their primary one
+	if !isPlacedIn(item, subCategory.ID) {
+		if item.SubCategory == nil || item.SubCategory.Category == nil {
+			return nil, appErrors.NewNotFoundError("Item")
+		}
+		canonical = MenuSlugPath{
+			CategorySlug:    item.SubCategory.Category.Slug,
+			SubCategorySlug: item.SubCategory.Slug,
+		}
+		redirected = true
+	}
+	canonical.ItemSlug = item.Slug
+
+	if redirected {
+		return &MenuSlugResponse{Canonical: &canonical}, nil
+	}
+
+	breadcrumbs, err := s.subCategoryBreadcrumbs(ctx, category, subCategory)
+	if err != nil {
+		return nil, err
+	}
+	breadcrumbs = append(breadcrumbs, entities.Breadcrumb{
+		Type: entities.BreadcrumbItem,
+		ID:   item.ID,
+		Name: item.Name,
+		Slug: item.Slug,
+	})
+
+	return &MenuSlugResponse{
+		Item:        item,
+		Breadcrumbs: breadcrumbs,
+	}, nil
+}
+
+// redirectedEntityID returns the entity that used to have slug, or 0.
+func (s *menuService) redirectedEntityID(ctx context.Context, entityType entities.SlugEntityType, scopeID uint, slug string) (uint, error) {
+	redirect, err := s.slugRepo.GetRedirect(ctx, entityType, scopeID, slug)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to look up slug redirect", map[string]interface{}{
+			"entity_type": entityType,
+			"slug":        slug,
+		})
+		return 0, appErrors.WrapInternalError(err, "Failed to look up slug")
+	}
+	if redirect == nil {
+		return 0, nil
+	}
+	return redirect.EntityID, nil
+}
+
+func (s *menuService) subCategoryBreadcrumbs(ctx context.Context, category *entities.Category, subCategory *entities.SubCategory) ([]entities.Breadcrumb, error) {
+	ancestors, err := s.subCategoryRepo.GetAncestors(ctx, subCategory.ID)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get subcategory ancestors")
+	}
+
+	breadcrumbs := []entities.Breadcrumb{categoryBreadcrumb(category)}
+	for _, ancestor := range ancestors {
+		breadcrumbs = append(breadcrumbs, entities.Breadcrumb{
+			Type: entities.BreadcrumbSubCategory,
+			ID:   ancestor.ID,
+			Name: ancestor.Name,
+			Slug: ancestor.Slug,
+		})
+	}
+	return breadcrumbs, nil
+}
+
+func categoryBreadcrumb(category *entities.Category) entities.Breadcrumb {
+	return entities.Breadcrumb{
+		Type: entities.BreadcrumbCategory,
+		ID:   category.ID,
+		Name: category.Name,
+		Slug: category.Slug,
+	}
+}
+
+func isPlacedIn(item *entities.Item, subCategoryID uint) bool {
+	for _, placement := range item.Placements {
+		if placement.SubCategoryID == subCategoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSearchQuery lowercases a query and collapses its whitespace so
+// equivalent queries are counted and cached together.
+func normalizeSearchQuery(query string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if runes := []rune(normalized); len(runes) > maxSearchQueryLength {
+		normalized = strings.TrimSpace(string(runes[:maxSearchQueryLength]))
+	}
+	return normalized
+}
+
+func nonNilSuggestions(suggestions []entities.Suggestion) []entities.Suggestion {
+	if suggestions == nil {
+		return []entities.Suggestion{}
+	}
+	return suggestions
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// countingCategoryRepository serves a fixed menu and counts the calls made to
+// it. Methods the menu service should not need panic through the nil
+// embedded interface.
+type countingCategoryRepository struct {
+	repositories.CategoryRepository
+	categories []*entities.Category
+	calls      int
+}
+
+func (r *countingCategoryRepository) GetAllWithSubCategories(ctx context.Context, filter entities.CategoryFilter) ([]*entities.Category, error) {
+	r.calls++
+	return r.categories, nil
+}
+
+type countingItemRepository struct {
+	repositories.ItemRepository
+	items map[uint][]*entities.Item
+	calls int
+}
+
+func (r *countingItemRepository) GetBySubCategoryIDs(ctx context.Context, subCategoryIDs []uint, filter entities.ItemFilter) (map[uint][]*entities.Item, error) {
+	r.calls++
+	items := make(map[uint][]*entities.Item, len(subCategoryIDs))
+	for _, id := range subCategoryIDs {
+		if list, ok := r.items[id]; ok {
+			items[id] = list
+		}
+	}
+	return items, nil
+}
+
+// newTestMenu builds categories with subCategoriesPerCategory subcategories
+// each, every second one nested below the one before, holding
+// itemsPerSubCategory items.
+func newTestMenu(categories, subCategoriesPerCategory, itemsPerSubCategory int) (*countingCategoryRepository, *countingItemRepository) {
+	categoryRepo := &countingCategoryRepository{}
+	itemRepo := &countingItemRepository{items: make(map[uint][]*entities.Item)}
+
+	var nextID, nextItemID uint
+	for c := 0; c < categories; c++ {
+		nextID++
+		category := &entities.Category{ID: nextID, Name: fmt.Sprintf("Category %d", c), Active: true}
+
+		for s := 0; s < subCategoriesPerCategory; s++ {
+			nextID++
+			subCategory := entities.SubCategory{ID: nextID, CategoryID: category.ID, DisplayOrder: s, Active: true}
+			if s%2 == 1 {
+				parentID := nextID - 1
+				subCategory.ParentID = &parentID
+				subCategory.Depth = 1
+			}
+			category.SubCategories = append(category.SubCategories, subCategory)
+
+			for i := 0; i < itemsPerSubCategory; i++ {
+				nextItemID++
+				itemRepo.items[subCategory.ID] = append(itemRepo.items[subCategory.ID], &entities.Item{
+					ID:            nextItemID,
+					SubCategoryID: subCategory.ID,
+					Available:     true,
+				})
+			}
+		}
+
+		categoryRepo.categories = append(categoryRepo.categories, category)
+	}
+
+	return categoryRepo, itemRepo
+}
+
+func newTestMenuService(categoryRepo repositories.CategoryRepository, itemRepo repositories.ItemRepository) MenuService {
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+	return NewMenuService(categoryRepo, nil, itemRepo, nil, nil, nil, nil, 0.3, log)
+}
+
+func TestGetCompleteMenuQueryCount(t *testing.T) {
+	for _, subCategories := range []int{0, 1, 10, 200} {
+		t.Run(fmt.Sprintf("%d subcategories", subCategories), func(t *testing.T) {
+			categoryRepo, itemRepo := newTestMenu(5, subCategories, 3)
+			service := newTestMenuService(categoryRepo, itemRepo)
+
+			menu, err := service.GetCompleteMenu(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("GetCompleteMenu: %v", err)
+			}
+
+			if categoryRepo.calls != 1 || itemRepo.calls != 1 {
+				t.Errorf("got %d category and %d item repository calls, want 1 each", categoryRepo.calls, itemRepo.calls)
+			}
+			if want := 5 * subCategories; menu.Stats.TotalSubCategories != want {
+				t.Errorf("got %d subcategories, want %d", menu.Stats.TotalSubCategories, want)
+			}
+			if want := 5 * subCategories * 3; menu.Stats.TotalItems != want {
+				t.Errorf("got %d items, want %d", menu.Stats.TotalItems, want)
+			}
+		})
+	}
+}
+
+func BenchmarkGetCompleteMenu(b *testing.B) {
+	for _, subCategories := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d subcategories", subCategories), func(b *testing.B) {
+			categoryRepo, itemRepo := newTestMenu(10, subCategories, 10)
+			service := newTestMenuService(categoryRepo, itemRepo)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := service.GetCompleteMenu(ctx, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64(categoryRepo.calls+itemRepo.calls)/float64(b.N), "queries/op")
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// menuVersionCacheKey holds the computed version under menuCacheTag, so it is
+// dropped along with the cached menus whenever menu data is written.
+const menuVersionCacheKey = "menu:version"
+
+// MenuVersionService tells which version of the menu data reads are served
+// from, so clients can revalidate what they already have.
+type MenuVersionService interface {
+	GetVersion(ctx context.Context) (*entities.MenuVersion, error)
+}
+
+type menuVersionService struct {
+	repo   repositories.MenuVersionRepository
+	cache  Cache
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewMenuVersionService keeps the computed version in cache for up to ttl, so
+// conditional reads don't scan the menu tables on every request. Writes
+// reported to a MenuChangeListener drop it straight away.
+func NewMenuVersionService(repo repositories.MenuVersionRepository, cache Cache, ttl time.Duration, logger *logger.Logger) MenuVersionService {
+	return &menuVersionService{
+		repo:   repo,
+		cache:  cache,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+func (s *menuVersionService) GetVersion(ctx context.Context) (*entities.MenuVersion, error) {
+	var version *entities.MenuVersion
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, menuVersionCacheKey, &version); err == nil && version != nil {
+			return version, nil
+		}
+	}
+
+	version, err := s.repo.GetVersion(ctx)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get menu version", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get menu version")
+	}
+
+	if s.cache != nil {
+		// The cache logs failures; the version is computed again next time
+		_ = s.cache.SetWithTags(ctx, menuVersionCacheKey, version, s.ttl, menuCacheTag)
+	}
+	return version, nil
+}
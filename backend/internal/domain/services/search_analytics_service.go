@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+const (
+	defaultSearchReportPeriod = 30 * 24 * time.Hour
+	defaultSearchReportLimit  = 20
+	maxSearchReportLimit      = 100
+)
+
+type SearchAnalyticsService interface {
+	RecordClick(ctx context.Context, searchID, itemID uint, position int) error
+	TopQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error)
+	ZeroResultQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error)
+	ClickThrough(ctx context.Context, filter entities.SearchReportFilter) (*entities.ClickThroughReport, error)
+}
+
+type searchAnalyticsService struct {
+	repo     repositories.SearchQueryRepository
+	itemRepo repositories.ItemRepository
+	logger   *logger.Logger
+}
+
+func NewSearchAnalyticsService(repo repositories.SearchQueryRepository, itemRepo repositories.ItemRepository, logger *logger.Logger) SearchAnalyticsService {
+	return &searchAnalyticsService{
+		repo:     repo,
+		itemRepo: itemRepo,
+		logger:   logger,
+	}
+}
+
+func (s *searchAnalyticsService) RecordClick(ctx context.Context, searchID, itemID uint, position int) error {
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return appErrors.WrapInternalError(err, "Failed to get item")
+	}
+	if item == nil {
+		return appErrors.NewValidationError("Invalid item ID", "Item does not exist")
+	}
+
+	found, err := s.repo.RecordClick(ctx, &entities.SearchClick{
+		SearchEventID: searchID,
+		ItemID:        itemID,
+		Position:      position,
+	})
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to record search click", map[string]interface{}{
+			"search_id": searchID,
+			"item_id":   itemID,
+		})
+		return appErrors.WrapInternalError(err, "Failed to record search click")
+	}
+	if !found {
+		return appErrors.NewNotFoundError("Search")
+	}
+
+	return nil
+}
+
+func (s *searchAnalyticsService) TopQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error) {
+	filter, err := normalizeSearchReportFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.TopQueries(ctx, filter)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get top search queries", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get top search queries")
+	}
+	return reports, nil
+}
+
+func (s *searchAnalyticsService) ZeroResultQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error) {
+	filter, err := normalizeSearchReportFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.ZeroResultQueries(ctx, filter)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get zero-result search queries", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get zero-result search queries")
+	}
+	return reports, nil
+}
+
+func (s *searchAnalyticsService) ClickThrough(ctx context.Context, filter entities.SearchReportFilter) (*entities.ClickThroughReport, error) {
+	filter, err := normalizeSearchReportFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.ClickThrough(ctx, filter)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get search click-through", nil)
+		return nil, appErrors.WrapInternalError(err, "Failed to get search click-through")
+	}
+	return report, nil
+}
+
+// normalizeSearchReportFilter defaults to the last 30 days and 20 rows.
+func normalizeSearchReportFilter(filter entities.SearchReportFilter) (entities.SearchReportFilter, error) {
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC()
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultSearchReportPeriod)
+	}
+	if !filter.From.Before(filter.To) {
+		return filter, appErrors.NewValidationError("Invalid report period", "from must be before to")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchReportLimit
+	}
+	if filter.Limit > maxSearchReportLimit {
+		filter.Limit = maxSearchReportLimit
+	}
+
+	return filter, nil
+}
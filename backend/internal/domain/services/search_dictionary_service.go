@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+const searchDictionaryCacheTTL = 10 * time.Minute
+
+var (
+	searchLocaleRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+	stopWordRegex     = regexp.MustCompile(`^[\p{L}\p{N}]+$`)
+)
+
+type SearchDictionaryService interface {
+	GetSynonyms(ctx context.Context, locale string) ([]*entities.SearchSynonym, error)
+	CreateSynonym(ctx context.Context, synonym *entities.SearchSynonym) error
+	UpdateSynonym(ctx context.Context, id uint, synonym *entities.SearchSynonym) (*entities.SearchSynonym, error)
+	DeleteSynonym(ctx context.Context, id uint) error
+	GetStopWords(ctx context.Context, locale string) ([]*entities.SearchStopWord, error)
+	CreateStopWord(ctx context.Context, stopWord *entities.SearchStopWord) error
+	DeleteStopWord(ctx context.Context, id uint) error
+}
+
+type searchDictionaryService struct {
+	repo   repositories.SearchDictionaryRepository
+	cache  Cache
+	logger *logger.Logger
+}
+
+func NewSearchDictionaryService(repo repositories.SearchDictionaryRepository, cache Cache, logger *logger.Logger) SearchDictionaryService {
+	return &searchDictionaryService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+func (s *searchDictionaryService) GetSynonyms(ctx context.Context, locale string) ([]*entities.SearchSynonym, error) {
+	if locale != "" {
+		var err error
+		if locale, err = normalizeSearchLocale(locale); err != nil {
+			return nil, err
+		}
+	}
+
+	synonyms, err := s.repo.GetSynonyms(ctx, locale)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get search synonyms")
+	}
+	return synonyms, nil
+}
+
+func (s *searchDictionaryService) CreateSynonym(ctx context.Context, synonym *entities.SearchSynonym) error {
+	if err := normalizeSynonym(synonym); err != nil {
+		return err
+	}
+
+	if err := s.repo.CreateSynonym(ctx, synonym); err != nil {
+		s.logger.LogError(ctx, err, "Failed to create search synonym", map[string]interface{}{
+			"locale": synonym.Locale,
+			"terms":  synonym.Terms,
+		})
+		return appErrors.WrapInternalError(err, "Failed to create search synonym")
+	}
+
+	s.invalidate(ctx, synonym.Locale)
+	return nil
+}
+
+func (s *searchDictionaryService) UpdateSynonym(ctx context.Context, id uint, updateData *entities.SearchSynonym) (*entities.SearchSynonym, error) {
+	existing, err := s.repo.GetSynonymByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get search synonym")
+	}
+	if existing == nil {
+		return nil, appErrors.NewNotFoundError("Search synonym")
+	}
+
+	previousLocale := existing.Locale
+	if updateData.Locale != "" {
+		existing.Locale = updateData.Locale
+	}
+	existing.Terms = updateData.Terms
+
+	if err := normalizeSynonym(existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSynonym(ctx, existing); err != nil {
+		s.logger.LogError(ctx, err, "Failed to update search synonym", map[string]interface{}{
+			"synonym_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to update search synonym")
+	}
+
+	s.invalidate(ctx, previousLocale, existing.Locale)
+	return existing, nil
+}
+
+func (s *searchDictionaryService) DeleteSynonym(ctx context.Context, id uint) error {
+	existing, err := s.repo.GetSynonymByID(ctx, id)
+	if err != nil {
+		return appErrors.WrapInternalError(err, "Failed to get search synonym")
+	}
+	if existing == nil {
+		return appErrors.NewNotFoundError("Search synonym")
+	}
+
+	if err := s.repo.DeleteSynonym(ctx, id); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete search synonym", map[string]interface{}{
+			"synonym_id": id,
+		})
+		return appErrors.WrapInternalError(err, "Failed to delete search synonym")
+	}
+
+	s.invalidate(ctx, existing.Locale)
+	return nil
+}
+
+func (s *searchDictionaryService) GetStopWords(ctx context.Context, locale string) ([]*entities.SearchStopWord, error) {
+	if locale != "" {
+		var err error
+		if locale, err = normalizeSearchLocale(locale); err != nil {
+			return nil, err
+		}
+	}
+
+	stopWords, err := s.repo.GetStopWords(ctx, locale)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get search stop words")
+	}
+	return stopWords, nil
+}
+
+func (s *searchDictionaryService) CreateStopWord(ctx context.Context, stopWord *entities.SearchStopWord) error {
+	locale, err := normalizeSearchLocale(stopWord.Locale)
+	if err != nil {
+		return err
+	}
+	stopWord.Locale = locale
+
+	stopWord.Word = strings.ToLower(strings.TrimSpace(stopWord.Word))
+	if !stopWordRegex.MatchString(stopWord.Word) {
+		return appErrors.NewValidationError("Invalid stop word", "A stop word is a single word of letters and digits")
+	}
+
+	created, err := s.repo.CreateStopWord(ctx, stopWord)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to create search stop word", map[string]interface{}{
+			"locale": stopWord.Locale,
+			"word":   stopWord.Word,
+		})
+		return appErrors.WrapInternalError(err, "Failed to create search stop word")
+	}
+	if !created {
+		return appErrors.NewConflictError("Stop word already exists for this locale")
+	}
+
+	s.invalidate(ctx, stopWord.Locale)
+	return nil
+}
+
+func (s *searchDictionaryService) DeleteStopWord(ctx context.Context, id uint) error {
+	existing, err := s.repo.GetStopWordByID(ctx, id)
+	if err != nil {
+		return appErrors.WrapInternalError(err, "Failed to get search stop word")
+	}
+	if existing == nil {
+		return appErrors.NewNotFoundError("Search stop word")
+	}
+
+	if err := s.repo.DeleteStopWord(ctx, id); err != nil {
+		s.logger.LogError(ctx, err, "Failed to delete search stop word", map[string]interface{}{
+			"stop_word_id": id,
+		})
+		return appErrors.WrapInternalError(err, "Failed to delete search stop word")
+	}
+
+	s.invalidate(ctx, existing.Locale)
+	return nil
+}
+
+// invalidate drops the cached dictionaries of the locales so the next search
+// picks up the change.
+func (s *searchDictionaryService) invalidate(ctx context.Context, locales ...string) {
+	if s.cache == nil {
+		return
+	}
+
+	keys := make([]string, len(locales))
+	for i, locale := range locales {
+		keys[i] = searchDictionaryCacheKey(locale)
+	}
+	// The client logs failures; entries still expire with their TTL
+	_ = s.cache.Delete(ctx, keys...)
+}
+
+// normalizeSynonym normalizes the locale and terms of a synonym group and
+// drops duplicate terms.
+func normalizeSynonym(synonym *entities.SearchSynonym) error {
+	locale, err := normalizeSearchLocale(synonym.Locale)
+	if err != nil {
+		return err
+	}
+	synonym.Locale = locale
+
+	terms := make(entities.StringList, 0, len(synonym.Terms))
+	seen := make(map[string]bool, len(synonym.Terms))
+	for _, term := range synonym.Terms {
+		term = entities.NormalizeSearchTerm(term)
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+
+	if len(terms) < 2 {
+		return appErrors.NewValidationError("Invalid synonym terms", "A synonym group needs at least two different terms")
+	}
+	synonym.Terms = terms
+	return nil
+}
+
+// normalizeSearchLocale lowercases a locale such as "en" or "ar-AE",
+// defaulting to DefaultSearchLocale.
+func normalizeSearchLocale(locale string) (string, error) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "" {
+		return entities.DefaultSearchLocale, nil
+	}
+	if !searchLocaleRegex.MatchString(locale) {
+		return "", appErrors.NewValidationError("Invalid locale", "Use a language code such as en or ar-ae")
+	}
+	return locale, nil
+}
+
+func searchDictionaryCacheKey(locale string) string {
+	return "search:dictionary:" + locale
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+const (
+	// snapshotTTL lets snapshots nobody reads any more age out of the cache
+	snapshotTTL = 7 * 24 * time.Hour
+	// degradedWindow is how long after serving a snapshot the server still
+	// reports degraded mode
+	degradedWindow = time.Minute
+	// snapshotWriteTimeout bounds saving a snapshot in the background
+	snapshotWriteTimeout = 5 * time.Second
+)
+
+var snapshotFileNameRegex = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// SnapshotStore keeps the last good copy of public reads, in the cache and on
+// disk, to serve while the database is unavailable.
+type SnapshotStore interface {
+	// Save records data under key, at most once per save interval.
+	Save(ctx context.Context, key string, data interface{})
+	// Load fills dest from the snapshot under key and returns when it was
+	// taken.
+	Load(ctx context.Context, key string, dest interface{}) (time.Time, error)
+	DegradedReporter
+}
+
+// DegradedStatus tells whether reads are being answered from snapshots.
+type DegradedStatus struct {
+	Degraded    bool       `json:"degraded"`
+	StaleServed int64      `json:"stale_served"`
+	LastStaleAt *time.Time `json:"last_stale_at,omitempty"`
+}
+
+type DegradedReporter interface {
+	DegradedStatus() DegradedStatus
+}
+
+type snapshot struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type snapshotStore struct {
+	cache        Cache
+	dir          string
+	saveInterval time.Duration
+	logger       *logger.Logger
+
+	mu      sync.Mutex
+	savedAt map[string]time.Time
+
+	staleServed atomic.Int64
+	lastStaleAt atomic.Int64
+}
+
+// NewSnapshotStore keeps snapshots in cache, when not nil, and in files below
+// dir, when not empty.
+func NewSnapshotStore(cache Cache, dir string, saveInterval time.Duration, logger *logger.Logger) SnapshotStore {
+	return &snapshotStore{
+		cache:        cache,
+		dir:          dir,
+		saveInterval: saveInterval,
+		logger:       logger,
+		savedAt:      make(map[string]time.Time),
+	}
+}
+
+func (s *snapshotStore) Save(ctx context.Context, key string, data interface{}) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if last, ok := s.savedAt[key]; ok && now.Sub(last) < s.saveInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.savedAt[key] = now
+	s.mu.Unlock()
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to encode snapshot", map[string]interface{}{
+			"key": key,
+		})
+		return
+	}
+	encoded, err := json.Marshal(snapshot{SavedAt: now.UTC(), Data: raw})
+	if err != nil {
+		return
+	}
+
+	// The response doesn't wait for the snapshot to be written
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotWriteTimeout)
+		defer cancel()
+
+		if s.cache != nil {
+			// The cache logs its own failures
+			_ = s.cache.Set(ctx, s.cacheKey(key), json.RawMessage(encoded), snapshotTTL)
+		}
+		if s.dir != "" {
+			if err := s.writeFile(key, encoded); err != nil {
+				s.logger.LogError(ctx, err, "Failed to write snapshot file", map[string]interface{}{
+					"key": key,
+					"dir": s.dir,
+				})
+			}
+		}
+	}()
+}
+
+func (s *snapshotStore) Load(ctx context.Context, key string, dest interface{}) (time.Time, error) {
+	var found snapshot
+	err := errors.New("no snapshot stored")
+
+	if s.cache != nil {
+		err = s.cache.Get(ctx, s.cacheKey(key), &found)
+	}
+	if err != nil && s.dir != "" {
+		err = s.readFile(key, &found)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := json.Unmarshal(found.Data, dest); err != nil {
+		return time.Time{}, err
+	}
+
+	s.staleServed.Add(1)
+	s.lastStaleAt.Store(time.Now().UnixNano())
+	return found.SavedAt, nil
+}
+
+func (s *snapshotStore) DegradedStatus() DegradedStatus {
+	status := DegradedStatus{StaleServed: s.staleServed.Load()}
+	if last := s.lastStaleAt.Load(); last != 0 {
+		lastStaleAt := time.Unix(0, last).UTC()
+		status.LastStaleAt = &lastStaleAt
+		status.Degraded = time.Since(lastStaleAt) < degradedWindow
+	}
+	return status
+}
+
+func (s *snapshotStore) cacheKey(key string) string {
+	return "snapshot:" + key
+}
+
+func (s *snapshotStore) filePath(key string) string {
+	return filepath.Join(s.dir, snapshotFileNameRegex.ReplaceAllString(key, "_")+".json")
+}
+
+// writeFile replaces the snapshot file in one step, so a crash mid-write
+// leaves the previous snapshot in place.
+func (s *snapshotStore) writeFile(key string, encoded []byte) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(encoded); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.filePath(key))
+}
+
+func (s *snapshotStore) readFile(key string, dest *snapshot) error {
+	encoded, err := os.ReadFile(s.filePath(key))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, dest)
+}
+
+// serveSnapshot handles the result of a read backed by snapshots. A
+// successful result is saved; when the read failed on the server side, dest
+// is filled from the last snapshot instead, the context is marked as served
+// stale and the error is cleared. Other errors, and failures without a
+// snapshot, are returned as they are.
+func serveSnapshot(ctx context.Context, snapshots SnapshotStore, logger *logger.Logger, key string, dest interface{}, err error) error {
+	if err == nil {
+		snapshots.Save(ctx, key, dest)
+		return nil
+	}
+	if appErrors.GetStatusCode(err) < 500 {
+		return err
+	}
+
+	savedAt, loadErr := snapshots.Load(ctx, key, dest)
+	if loadErr != nil {
+		return err
+	}
+
+	markStale(ctx, savedAt)
+	logger.LogWarning(ctx, "Serving stale snapshot", map[string]interface{}{
+		"key":      key,
+		"saved_at": savedAt,
+		"error":    err.Error(),
+	})
+	return nil
+}
+
+type staleTrackerKey struct{}
+
+type staleTracker struct {
+	mu      sync.Mutex
+	savedAt time.Time
+	stale   bool
+}
+
+// WithStaleTracking returns a context in which reads answered from snapshots
+// are recorded, for ServedStale to report.
+func WithStaleTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleTrackerKey{}, &staleTracker{})
+}
+
+// ServedStale reports whether a read in ctx was answered from a snapshot and
+// when the oldest such snapshot was taken.
+func ServedStale(ctx context.Context) (time.Time, bool) {
+	tracker, ok := ctx.Value(staleTrackerKey{}).(*staleTracker)
+	if !ok {
+		return time.Time{}, false
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return tracker.savedAt, tracker.stale
+}
+
+func markStale(ctx context.Context, savedAt time.Time) {
+	tracker, ok := ctx.Value(staleTrackerKey{}).(*staleTracker)
+	if !ok {
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if !tracker.stale || savedAt.Before(tracker.savedAt) {
+		tracker.savedAt = savedAt
+	}
+	tracker.stale = true
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// staleMenuService answers the public menu reads from the last good snapshot
+// when the wrapped service fails, so guests keep seeing a menu while the
+// database is down.
+type staleMenuService struct {
+	MenuService
+	snapshots SnapshotStore
+	logger    *logger.Logger
+}
+
+func NewStaleMenuService(service MenuService, snapshots SnapshotStore, logger *logger.Logger) MenuService {
+	if snapshots == nil {
+		return service
+	}
+	return &staleMenuService{
+		MenuService: service,
+		snapshots:   snapshots,
+		logger:      logger,
+	}
+}
+
+func (s *staleMenuService) GetCompleteMenu(ctx context.Context, itemExpand entities.Expand) (*MenuResponse, error) {
+	menu, err := s.MenuService.GetCompleteMenu(ctx, itemExpand)
+	err = serveSnapshot(ctx, s.snapshots, s.logger, "menu:complete:"+expandCacheKey(itemExpand), &menu, err)
+	return menu, err
+}
+
+func (s *staleMenuService) GetMenuTree(ctx context.Context) (*MenuTreeResponse, error) {
+	tree, err := s.MenuService.GetMenuTree(ctx)
+	err = serveSnapshot(ctx, s.snapshots, s.logger, "menu:tree", &tree, err)
+	return tree, err
+}
+
+func (s *staleMenuService) GetCategoryTree(ctx context.Context, categoryID uint) (*MenuTreeCategory, error) {
+	tree, err := s.MenuService.GetCategoryTree(ctx, categoryID)
+	err = serveSnapshot(ctx, s.snapshots, s.logger, fmt.Sprintf("menu:tree:%d", categoryID), &tree, err)
+	return tree, err
+}
+
+// staleRestaurantService does the same for the restaurant's info and hours.
+type staleRestaurantService struct {
+	RestaurantService
+	snapshots SnapshotStore
+	logger    *logger.Logger
+}
+
+func NewStaleRestaurantService(service RestaurantService, snapshots SnapshotStore, logger *logger.Logger) RestaurantService {
+	if snapshots == nil {
+		return service
+	}
+	return &staleRestaurantService{
+		RestaurantService: service,
+		snapshots:         snapshots,
+		logger:            logger,
+	}
+}
+
+func (s *staleRestaurantService) GetInfo(ctx context.Context) (*entities.RestaurantInfo, error) {
+	info, err := s.RestaurantService.GetInfo(ctx)
+	if err == nil && info == nil {
+		return nil, nil
+	}
+	err = serveSnapshot(ctx, s.snapshots, s.logger, "restaurant:info", &info, err)
+	return info, err
+}
+
+func (s *staleRestaurantService) GetOperatingHours(ctx context.Context) ([]entities.OperatingHour, error) {
+	hours, err := s.RestaurantService.GetOperatingHours(ctx)
+	err = serveSnapshot(ctx, s.snapshots, s.logger, "restaurant:hours", &hours, err)
+	return hours, err
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
+	appErrors "restaurant-menu-api/pkg/errors"
 	"restaurant-menu-api/pkg/logger"
 	"restaurant-menu-api/pkg/utils"
 )
@@ -13,12 +15,18 @@ import (
 type SubCategoryService interface {
 	GetAll(ctx context.Context, filter entities.SubCategoryFilter) ([]*entities.SubCategory, *entities.Pagination, error)
 	GetByID(ctx context.Context, id uint) (*entities.SubCategory, error)
+	GetWithItems(ctx context.Context, id uint) (*entities.SubCategory, error)
 	GetByCategoryID(ctx context.Context, categoryID uint) ([]*entities.SubCategory, error)
 	Create(ctx context.Context, subCategory *entities.SubCategory) error
 	Update(ctx context.Context, id uint, subCategory *entities.SubCategory) error
 	Delete(ctx context.Context, id uint) error
 	ToggleActive(ctx context.Context, id uint) error
 	UpdateDisplayOrder(ctx context.Context, id uint, order int) error
+	Reorder(ctx context.Context, categoryID uint, parentID *uint, ids []uint) ([]*entities.SubCategory, error)
+	Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.SubCategory, error)
+	GetSubtree(ctx context.Context, id uint) (*entities.SubCategory, error)
+	GetBreadcrumbs(ctx context.Context, id uint) ([]entities.Breadcrumb, error)
+	Move(ctx context.Context, id, categoryID uint, parentID *uint) (*entities.SubCategory, error)
 }
 
 type subCategoryService struct {
@@ -41,12 +49,15 @@ func (s *subCategoryService) GetByID(ctx context.Context, id uint) (*entities.Su
 	return s.repo.GetByID(ctx, id)
 }
 
+func (s *subCategoryService) GetWithItems(ctx context.Context, id uint) (*entities.SubCategory, error) {
+	return s.repo.GetWithItems(ctx, id)
+}
+
 func (s *subCategoryService) GetByCategoryID(ctx context.Context, categoryID uint) ([]*entities.SubCategory, error) {
 	filter := entities.SubCategoryFilter{
 		CategoryID: &categoryID,
 		Active:     utils.BoolPtr(true),
-		OrderBy:    "display_order",
-		OrderDir:   "ASC",
+		Sort:       entities.Sort{{Field: "display_order"}},
 	}
 	
 	subCategories, _, err := s.repo.GetAll(ctx, filter)
@@ -61,7 +72,12 @@ func (s *subCategoryService) Create(ctx context.Context, subCategory *entities.S
 
 	// Set default display order if not provided
 	if subCategory.DisplayOrder == 0 {
-		count, err := s.repo.Count(ctx, entities.SubCategoryFilter{CategoryID: &subCategory.CategoryID})
+		siblings := entities.SubCategoryFilter{CategoryID: &subCategory.CategoryID, TopLevel: true}
+		if subCategory.ParentID != nil {
+			siblings = entities.SubCategoryFilter{ParentID: subCategory.ParentID}
+		}
+
+		count, err := s.repo.Count(ctx, siblings)
 		if err != nil {
 			return fmt.Errorf("failed to get subcategory count: %w", err)
 		}
@@ -86,16 +102,22 @@ func (s *subCategoryService) Update(ctx context.Context, id uint, updateData *en
 	if updateData.Description != "" {
 		existing.Description = updateData.Description
 	}
-	if updateData.CategoryID != 0 {
-		existing.CategoryID = updateData.CategoryID
-	}
 	if updateData.DisplayOrder != 0 {
 		existing.DisplayOrder = updateData.DisplayOrder
 	}
 	// Always update active status if specified
 	existing.Active = updateData.Active
 
-	return s.repo.Update(ctx, existing)
+	if err := s.repo.Update(ctx, existing); err != nil {
+		return err
+	}
+
+	// Changing the category moves the subcategory, and everything nested
+	// below it, to the top level of the new category
+	if updateData.CategoryID != 0 && updateData.CategoryID != existing.CategoryID {
+		return s.repo.Move(ctx, id, updateData.CategoryID, nil)
+	}
+	return nil
 }
 
 func (s *subCategoryService) Delete(ctx context.Context, id uint) error {
@@ -120,4 +142,158 @@ func (s *subCategoryService) UpdateDisplayOrder(ctx context.Context, id uint, or
 	
 	subCategory.DisplayOrder = order
 	return s.repo.Update(ctx, subCategory)
-}
\ No newline at end of file
+}
+
+func (s *subCategoryService) Reorder(ctx context.Context, categoryID uint, parentID *uint, ids []uint) ([]*entities.SubCategory, error) {
+	if len(ids) == 0 {
+		return nil, appErrors.NewValidationError("SubCategory IDs are required", "Provide the full ordered list of subcategory IDs")
+	}
+
+	if err := s.repo.Reorder(ctx, categoryID, parentID, ids); err != nil {
+		if errors.Is(err, repositories.ErrOrderMismatch) {
+			return nil, appErrors.NewValidationError("Invalid subcategory order", "The list must contain every subcategory directly below the parent exactly once")
+		}
+		s.logger.LogError(ctx, err, "Failed to reorder subcategories", map[string]interface{}{
+			"category_id":     categoryID,
+			"parent_id":       parentID,
+			"subcategory_ids": ids,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to reorder subcategories")
+	}
+
+	return s.repo.GetByCategoryID(ctx, categoryID, entities.SubCategoryFilter{
+		ParentID: parentID,
+		TopLevel: parentID == nil,
+		Sort:     entities.Sort{{Field: "display_order"}},
+	})
+}
+
+func (s *subCategoryService) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.SubCategory, error) {
+	clone, err := s.repo.Clone(ctx, id, opts)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to clone subcategory", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to clone subcategory")
+	}
+
+	if clone == nil {
+		return nil, appErrors.NewNotFoundError("SubCategory")
+	}
+
+	s.logger.LogInfo(ctx, "SubCategory cloned successfully", map[string]interface{}{
+		"source_subcategory_id": id,
+		"subcategory_id":        clone.ID,
+		"category_id":           clone.CategoryID,
+	})
+
+	return clone, nil
+}
+
+// GetSubtree returns the subcategory with everything nested below it filled
+// into Children.
+func (s *subCategoryService) GetSubtree(ctx context.Context, id uint) (*entities.SubCategory, error) {
+	nodes, err := s.repo.GetSubtree(ctx, id)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get subcategory subtree", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get subcategory subtree")
+	}
+
+	if len(nodes) == 0 {
+		return nil, appErrors.NewNotFoundError("SubCategory")
+	}
+
+	entities.SortSubCategoryTree(nodes)
+	roots := entities.NestSubCategories(nodes)
+	return roots[0], nil
+}
+
+// GetBreadcrumbs returns the trail from the category down to the subcategory.
+func (s *subCategoryService) GetBreadcrumbs(ctx context.Context, id uint) ([]entities.Breadcrumb, error) {
+	subCategory, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get subcategory")
+	}
+	if subCategory == nil {
+		return nil, appErrors.NewNotFoundError("SubCategory")
+	}
+
+	ancestors, err := s.repo.GetAncestors(ctx, id)
+	if err != nil {
+		s.logger.LogError(ctx, err, "Failed to get subcategory ancestors", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to get subcategory ancestors")
+	}
+
+	breadcrumbs := make([]entities.Breadcrumb, 0, len(ancestors)+1)
+	if subCategory.Category != nil {
+		breadcrumbs = append(breadcrumbs, entities.Breadcrumb{
+			Type: entities.BreadcrumbCategory,
+			ID:   subCategory.Category.ID,
+			Name: subCategory.Category.Name,
+			Slug: subCategory.Category.Slug,
+		})
+	}
+	for _, ancestor := range ancestors {
+		breadcrumbs = append(breadcrumbs, entities.Breadcrumb{
+			Type: entities.BreadcrumbSubCategory,
+			ID:   ancestor.ID,
+			Name: ancestor.Name,
+			Slug: ancestor.Slug,
+		})
+	}
+
+	return breadcrumbs, nil
+}
+
+// Move re-parents the subcategory below parentID or, when nil, directly below
+// categoryID.
+func (s *subCategoryService) Move(ctx context.Context, id, categoryID uint, parentID *uint) (*entities.SubCategory, error) {
+	subCategory, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, appErrors.WrapInternalError(err, "Failed to get subcategory")
+	}
+	if subCategory == nil {
+		return nil, appErrors.NewNotFoundError("SubCategory")
+	}
+
+	if parentID == nil && categoryID == 0 {
+		return nil, appErrors.NewValidationError("Move target is required", "Provide a category_id or parent_id")
+	}
+
+	if parentID != nil {
+		parent, err := s.repo.GetByID(ctx, *parentID)
+		if err != nil {
+			return nil, appErrors.WrapInternalError(err, "Failed to get parent subcategory")
+		}
+		if parent == nil {
+			return nil, appErrors.NewValidationError("Invalid parent ID", "Parent subcategory does not exist")
+		}
+		if categoryID != 0 && categoryID != parent.CategoryID {
+			return nil, appErrors.NewValidationError("Invalid category ID", "The parent subcategory belongs to another category")
+		}
+	}
+
+	if err := s.repo.Move(ctx, id, categoryID, parentID); err != nil {
+		if errors.Is(err, repositories.ErrInvalidMove) {
+			return nil, appErrors.NewValidationError("Invalid parent ID", "A subcategory cannot be moved below itself or its own subcategories")
+		}
+		s.logger.LogError(ctx, err, "Failed to move subcategory", map[string]interface{}{
+			"subcategory_id": id,
+			"category_id":    categoryID,
+			"parent_id":      parentID,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to move subcategory")
+	}
+
+	s.logger.LogInfo(ctx, "SubCategory moved successfully", map[string]interface{}{
+		"subcategory_id": id,
+		"category_id":    categoryID,
+		"parent_id":      parentID,
+	})
+
+	return s.repo.GetByID(ctx, id)
+}
@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"restaurant-menu-api/internal/config"
+	"restaurant-menu-api/internal/domain/services"
+	"restaurant-menu-api/internal/infrastructure/redis"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// New returns the cache the configuration selects: Redis through
+// redisClient, or process memory when configured so or when redisClient is
+// nil because Redis was unreachable at startup. The choice is made once; if
+// Redis goes down later the cache stays on Redis, its failures are logged and
+// reads are built from the database until it is back.
+func New(cfg *config.CacheConfig, redisClient *redis.Client, logger *logger.Logger) services.Cache {
+	if cfg.Driver == "redis" {
+		if redisClient != nil {
+			return redisClient
+		}
+		logger.Warn("Redis is unavailable, caching in memory instead")
+	}
+
+	return NewMemoryCache(cfg.MemoryMaxEntries, cfg.MemoryMaxBytes, logger)
+}
@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// MemoryCache keeps cached results in process memory, for when Redis is not
+// available. Values are stored JSON-encoded, like in Redis, so readers never
+// share them with writers. Beyond maxEntries entries or maxBytes of encoded
+// values the least recently used entries are dropped; expired entries are
+// dropped when read or when they reach the end of the list.
+type MemoryCache struct {
+	maxEntries int
+	maxBytes   int
+	logger     *logger.Logger
+
+	mu      sync.Mutex
+	size    int
+	order   *list.List // most recently used first
+	entries map[string]*list.Element
+	tags    map[string]map[string]struct{}
+}
+
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time // zero when the entry doesn't expire
+	tags      []string
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func NewMemoryCache(maxEntries, maxBytes int, logger *logger.Logger) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		logger:     logger,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	entry := c.lookup(key, time.Now())
+	if entry == nil {
+		c.mu.Unlock()
+		return appErrors.NewNotFoundError("Cache entry")
+	}
+	// Stored data is never modified, only replaced
+	data := entry.data
+	c.mu.Unlock()
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		c.logger.LogError(ctx, err, "Failed to unmarshal cached value", map[string]interface{}{
+			"key": key,
+		})
+		return appErrors.WrapInternalError(err, "Failed to deserialize cached data")
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return c.SetWithTags(ctx, key, value, expiration)
+}
+
+func (c *MemoryCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := c.marshal(ctx, key, value)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lookup(key, time.Now()) != nil {
+		return false, nil
+	}
+	c.store(key, data, expiration, nil)
+	return true, nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) DeleteIfEquals(ctx context.Context, key string, value interface{}) (bool, error) {
+	data, err := c.marshal(ctx, key, value)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.lookup(key, time.Now())
+	if entry == nil || !bytes.Equal(entry.data, data) {
+		return false, nil
+	}
+	c.remove(c.entries[key])
+	return true, nil
+}
+
+func (c *MemoryCache) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	data, err := c.marshal(ctx, key, value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store(key, data, expiration, tags)
+	return nil
+}
+
+func (c *MemoryCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if element, ok := c.entries[key]; ok {
+				c.remove(element)
+			}
+		}
+		delete(c.tags, tag)
+	}
+	return nil
+}
+
+func (c *MemoryCache) marshal(ctx context.Context, key string, value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.LogError(ctx, err, "Failed to marshal value for cache", map[string]interface{}{
+			"key": key,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to serialize data")
+	}
+	return data, nil
+}
+
+// lookup returns the live entry under key, marking it as recently used, or
+// nil. The caller holds the lock.
+func (c *MemoryCache) lookup(key string, now time.Time) *memoryEntry {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if entry.expired(now) {
+		c.remove(element)
+		return nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry
+}
+
+// store replaces the entry under key and evicts entries until the cache is
+// within its limits again. A value larger than the whole cache isn't kept.
+// The caller holds the lock.
+func (c *MemoryCache) store(key string, data []byte, expiration time.Duration, tags []string) {
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	if len(data) > c.maxBytes {
+		return
+	}
+
+	entry := &memoryEntry{key: key, data: data, tags: tags}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	c.size += len(data)
+	for _, tag := range tags {
+		keys, ok := c.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for len(c.entries) > c.maxEntries || c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove drops an entry and its tag references. The caller holds the lock.
+func (c *MemoryCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*memoryEntry)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.data)
+
+	for _, tag := range entry.tags {
+		keys := c.tags[tag]
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"restaurant-menu-api/pkg/logger"
+)
+
+func newTestMemoryCache(maxEntries, maxBytes int) *MemoryCache {
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+	return NewMemoryCache(maxEntries, maxBytes, log)
+}
+
+// cached reports whether key holds a live entry.
+func cached(c *MemoryCache, key string) bool {
+	var value string
+	return c.Get(context.Background(), key, &value) == nil
+}
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	c := newTestMemoryCache(10, 1<<20)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "key", map[string]int{"a": 1}, 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	var got map[string]int
+	if err := c.Get(ctx, "key", &got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got["a"] != 1 {
+		t.Errorf("got %v, want a=1", got)
+	}
+
+	var missing string
+	if err := c.Get(ctx, "missing", &missing); err == nil {
+		t.Error("Get of a missing key succeeded")
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newTestMemoryCache(3, 1<<20)
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		c.Set(ctx, key, key, 0)
+	}
+	// Reading a makes b the least recently used
+	cached(c, "a")
+	c.Set(ctx, "d", "d", 0)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if got := cached(c, key); got != want {
+			t.Errorf("%s cached = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestMemoryCacheEvictsBeyondMaxBytes(t *testing.T) {
+	// Each value encodes to 6 bytes, "aaaa" with its quotes
+	c := newTestMemoryCache(100, 12)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", "aaaa", 0)
+	c.Set(ctx, "b", "bbbb", 0)
+	c.Set(ctx, "c", "cccc", 0)
+
+	if cached(c, "a") {
+		t.Error("oldest entry kept beyond the byte limit")
+	}
+	if !cached(c, "b") || !cached(c, "c") {
+		t.Error("newer entries dropped")
+	}
+
+	c.Set(ctx, "big", "a value larger than the whole cache", 0)
+	if cached(c, "big") {
+		t.Error("value larger than the cache was kept")
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	c := newTestMemoryCache(10, 1<<20)
+	ctx := context.Background()
+
+	c.Set(ctx, "short", "value", 20*time.Millisecond)
+	c.Set(ctx, "forever", "value", 0)
+
+	if !cached(c, "short") {
+		t.Fatal("entry missing before its TTL")
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	if cached(c, "short") {
+		t.Error("entry served after its TTL")
+	}
+	if !cached(c, "forever") {
+		t.Error("entry without TTL expired")
+	}
+}
+
+func TestMemoryCacheSetNX(t *testing.T) {
+	c := newTestMemoryCache(10, 1<<20)
+	ctx := context.Background()
+
+	ok, err := c.SetNX(ctx, "lock", 1, 20*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("first SetNX = %v, %v; want true", ok, err)
+	}
+	if ok, _ := c.SetNX(ctx, "lock", 2, 20*time.Millisecond); ok {
+		t.Error("SetNX replaced a live entry")
+	}
+
+	var value int
+	c.Get(ctx, "lock", &value)
+	if value != 1 {
+		t.Errorf("got %d, want the first value", value)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if ok, _ := c.SetNX(ctx, "lock", 3, 0); !ok {
+		t.Error("SetNX refused to replace an expired entry")
+	}
+}
+
+func TestMemoryCacheDeleteIfEquals(t *testing.T) {
+	c := newTestMemoryCache(10, 1<<20)
+	ctx := context.Background()
+
+	c.Set(ctx, "lock", "mine", 0)
+	if ok, err := c.DeleteIfEquals(ctx, "lock", "theirs"); err != nil || ok {
+		t.Errorf("DeleteIfEquals with another value = %v, %v; want false", ok, err)
+	}
+	if !cached(c, "lock") {
+		t.Fatal("entry deleted for another value")
+	}
+
+	if ok, err := c.DeleteIfEquals(ctx, "lock", "mine"); err != nil || !ok {
+		t.Errorf("DeleteIfEquals with the stored value = %v, %v; want true", ok, err)
+	}
+	if cached(c, "lock") {
+		t.Error("entry kept")
+	}
+
+	if ok, _ := c.DeleteIfEquals(ctx, "lock", "mine"); ok {
+		t.Error("DeleteIfEquals deleted a missing entry")
+	}
+}
+
+func TestMemoryCacheInvalidateTags(t *testing.T) {
+	c := newTestMemoryCache(10, 1<<20)
+	ctx := context.Background()
+
+	c.SetWithTags(ctx, "menu", "value", 0, "menu")
+	c.SetWithTags(ctx, "category", "value", 0, "menu", "category:1")
+	c.SetWithTags(ctx, "other", "value", 0, "category:2")
+	c.Set(ctx, "untagged", "value", 0)
+
+	if err := c.InvalidateTags(ctx, "category:1"); err != nil {
+		t.Fatalf("InvalidateTags: %v", err)
+	}
+	if cached(c, "category") || !cached(c, "menu") {
+		t.Error("invalidating category:1 dropped the wrong entries")
+	}
+
+	c.InvalidateTags(ctx, "menu")
+	for key, want := range map[string]bool{"menu": false, "other": true, "untagged": true} {
+		if got := cached(c, key); got != want {
+			t.Errorf("%s cached = %v, want %v", key, got, want)
+		}
+	}
+
+	// Evicted and replaced entries leave no tag references behind
+	c.SetWithTags(ctx, "menu", "value", 0, "menu")
+	c.Set(ctx, "menu", "value", 0)
+	c.InvalidateTags(ctx, "menu")
+	if !cached(c, "menu") {
+		t.Error("entry replaced without tags was dropped by its old tag")
+	}
+	if len(c.tags["menu"]) != 0 {
+		t.Errorf("tag index keeps %d stale keys", len(c.tags["menu"]))
+	}
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) repositories.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) GetAll(ctx context.Context) ([]*entities.APIKey, error) {
+	var keys []*entities.APIKey
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (*entities.APIKey, error) {
+	var key entities.APIKey
+	err := r.db.WithContext(ctx).First(&key, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	var key entities.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, key *entities.APIKey) error {
+	return r.db.WithContext(ctx).Save(key).Error
+}
+
+// RecordUsage adds to the daily counters in one statement; usages must not
+// repeat a key, day, method and route.
+func (r *apiKeyRepository) RecordUsage(ctx context.Context, usages []*entities.APIKeyUsage, usedAt time.Time) error {
+	if len(usages) == 0 {
+		return nil
+	}
+
+	keyIDs := make([]uint, 0, len(usages))
+	seen := make(map[uint]bool, len(usages))
+	for _, usage := range usages {
+		if !seen[usage.APIKeyID] {
+			seen[usage.APIKeyID] = true
+			keyIDs = append(keyIDs, usage.APIKeyID)
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("APIKey").Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "api_key_id"}, {Name: "day"}, {Name: "method"}, {Name: "route"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"requests": gorm.Expr("api_key_usages.requests + EXCLUDED.requests"),
+			}),
+		}).Create(&usages).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.APIKey{}).
+			Where("id IN ?", keyIDs).
+			UpdateColumn("last_used_at", usedAt).Error
+	})
+}
+
+// UsageReport sums the usage on the UTC days the filter's period touches by
+// key and endpoint, most requested first.
+func (r *apiKeyRepository) UsageReport(ctx context.Context, filter entities.APIKeyUsageFilter) ([]entities.APIKeyUsageReport, error) {
+	firstDay := filter.From.UTC().Format("2006-01-02")
+	lastDay := filter.To.Add(-time.Nanosecond).UTC().Format("2006-01-02")
+
+	query := r.db.WithContext(ctx).Table("api_key_usages").
+		Select(`api_key_usages.api_key_id, api_keys.name AS api_key_name,
+			api_key_usages.method, api_key_usages.route,
+			SUM(api_key_usages.requests) AS requests,
+			MAX(api_key_usages.day) AS last_day`).
+		Joins("JOIN api_keys ON api_keys.id = api_key_usages.api_key_id").
+		Where("api_key_usages.day BETWEEN ? AND ?", firstDay, lastDay).
+		Group("api_key_usages.api_key_id, api_keys.name, api_key_usages.method, api_key_usages.route").
+		Order("requests DESC, api_key_usages.api_key_id ASC, api_key_usages.route ASC").
+		Limit(filter.Limit)
+
+	if filter.APIKeyID != 0 {
+		query = query.Where("api_key_usages.api_key_id = ?", filter.APIKeyID)
+	}
+
+	reports := []entities.APIKeyUsageReport{}
+	if err := query.Scan(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
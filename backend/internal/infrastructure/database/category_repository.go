@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
@@ -51,7 +52,7 @@ func (r *categoryRepository) GetAll(ctx context.Context, filter entities.Categor
 	var categories []*entities.Category
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&entities.Category{})
+	query := applyExpand(r.db.WithContext(ctx).Model(&entities.Category{}), filter.Expand, nil, categoryPreloads)
 
 	// Apply filters
 	if filter.Active != nil {
@@ -63,9 +64,15 @@ func (r *categoryRepository) GetAll(ctx context.Context, filter entities.Categor
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
 	}
 
+	if filter.Cursor != nil {
+		return r.getAllAfterCursor(ctx, query, filter)
+	}
+
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, nil, err
+	if filter.IncludeCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Apply pagination
@@ -77,15 +84,7 @@ func (r *categoryRepository) GetAll(ctx context.Context, filter entities.Categor
 	}
 
 	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "categories", filter.Sort, "display_order ASC, created_at DESC")
 
 	if err := query.Find(&categories).Error; err != nil {
 		return nil, nil, err
@@ -93,16 +92,41 @@ func (r *categoryRepository) GetAll(ctx context.Context, filter entities.Categor
 
 	var pagination *entities.Pagination
 	if filter.IncludeCount {
-		page := (filter.Offset / filter.Limit) + 1
-		if filter.Limit == 0 {
-			page = 1
-		}
-		pagination = entities.NewPagination(page, filter.Limit, total)
+		pagination = offsetPagination(filter.Offset, filter.Limit, total)
 	}
 
 	return categories, pagination, nil
 }
 
+// getAllAfterCursor returns the page of query after filter.Cursor.
+func (r *categoryRepository) getAllAfterCursor(ctx context.Context, query *gorm.DB, filter entities.CategoryFilter) ([]*entities.Category, *entities.Pagination, error) {
+	var total int64
+	if filter.IncludeCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var categories []*entities.Category
+	limit := cursorLimit(filter.Limit)
+	if err := applyCursor(query, "categories", filter.Cursor, limit).Find(&categories).Error; err != nil {
+		return nil, nil, err
+	}
+
+	fetched := len(categories)
+	if fetched > limit {
+		categories = categories[:limit]
+	}
+
+	var last entities.Cursor
+	if len(categories) > 0 {
+		tail := categories[len(categories)-1]
+		last = entities.Cursor{DisplayOrder: tail.DisplayOrder, ID: tail.ID}
+	}
+
+	return categories, cursorPagination(limit, fetched, total, last), nil
+}
+
 func (r *categoryRepository) Update(ctx context.Context, category *entities.Category) error {
 	return r.db.WithContext(ctx).Save(category).Error
 }
@@ -114,7 +138,7 @@ func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
 func (r *categoryRepository) GetWithSubCategories(ctx context.Context, id uint) (*entities.Category, error) {
 	var category entities.Category
 	err := r.db.WithContext(ctx).
-		Preload("SubCategories", "active = ?", true).
+		Preload("SubCategories", activeSubCategoriesTreeOrder).
 		First(&category, id).Error
 	
 	if err != nil {
@@ -126,11 +150,18 @@ func (r *categoryRepository) GetWithSubCategories(ctx context.Context, id uint)
 	return &category, nil
 }
 
+// activeSubCategoriesTreeOrder preloads the active subcategories of every
+// depth as one flat list, parents first and then in display order, as
+// entities.SortSubCategoryTree orders them.
+func activeSubCategoriesTreeOrder(db *gorm.DB) *gorm.DB {
+	return orderByTreePosition(db.Where("active = ?", true))
+}
+
 func (r *categoryRepository) GetAllWithSubCategories(ctx context.Context, filter entities.CategoryFilter) ([]*entities.Category, error) {
 	var categories []*entities.Category
 
 	query := r.db.WithContext(ctx).
-		Preload("SubCategories", "active = ?", true)
+		Preload("SubCategories", activeSubCategoriesTreeOrder)
 
 	// Apply filters
 	if filter.Active != nil {
@@ -143,15 +174,7 @@ func (r *categoryRepository) GetAllWithSubCategories(ctx context.Context, filter
 	}
 
 	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "categories", filter.Sort, "display_order ASC, created_at DESC")
 
 	if err := query.Find(&categories).Error; err != nil {
 		return nil, err
@@ -188,4 +211,141 @@ func (r *categoryRepository) ToggleActive(ctx context.Context, id uint) error {
 		Model(&entities.Category{}).
 		Where("id = ?", id).
 		Update("active", gorm.Expr("NOT active")).Error
-}
\ No newline at end of file
+}
+
+func (r *categoryRepository) Reorder(ctx context.Context, ids []uint) error {
+	return reorderRows(ctx, r.db, &entities.Category{}, func(db *gorm.DB) *gorm.DB {
+		return db
+	}, ids)
+}
+
+func (r *categoryRepository) CompactDisplayOrder(ctx context.Context) (int64, error) {
+	return compactDisplayOrder(ctx, r.db, "categories", "")
+}
+
+func (r *categoryRepository) RegenerateFallbackSlugs(ctx context.Context) (int64, error) {
+	return regenerateFallbackSlugs(ctx, r.db, "category",
+		func(c *entities.Category) string { return c.Name },
+		func(c *entities.Category) { c.Slug = "" })
+}
+
+func (r *categoryRepository) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Category, error) {
+	var clone *entities.Category
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source entities.Category
+		if err := tx.
+			Preload("SubCategories", orderByTreePosition).
+			First(&source, id).Error; err != nil {
+			return err
+		}
+		if err := loadPlacedItems(tx, subCategoryPointers(source.SubCategories)); err != nil {
+			return err
+		}
+
+		name, err := uniqueCategoryName(tx, entities.CloneName(source.Name, opts.Suffix(), 100))
+		if err != nil {
+			return err
+		}
+
+		displayOrder, err := nextDisplayOrder(tx, &entities.Category{}, func(db *gorm.DB) *gorm.DB {
+			return db
+		})
+		if err != nil {
+			return err
+		}
+
+		clone = &entities.Category{
+			Name:         name,
+			Description:  source.Description,
+			DisplayOrder: displayOrder,
+			Active:       source.Active,
+		}
+		if err := tx.Create(clone).Error; err != nil {
+			return err
+		}
+
+		clones, err := cloneSubCategoryTree(tx, source.SubCategories, clone.ID, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		clone.SubCategories = make([]entities.SubCategory, 0, len(source.SubCategories))
+		for i := range source.SubCategories {
+			clone.SubCategories = append(clone.SubCategories, *clones[source.SubCategories[i].ID])
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+func (r *categoryRepository) BulkApply(ctx context.Context, ids []uint, op entities.BulkCategoryOperation, dryRun bool) (*entities.BulkResult, error) {
+	result := &entities.BulkResult{
+		DryRun:  dryRun,
+		Changes: []entities.BulkChange{},
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var categories []*entities.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id ASC").
+			Find(&categories).Error; err != nil {
+			return err
+		}
+		result.Matched = len(categories)
+
+		affectedIDs := make([]uint, 0, len(categories))
+		for _, category := range categories {
+			change := entities.BulkChange{ID: category.ID, Name: category.Name}
+
+			switch op.Type {
+			case entities.BulkOpDelete:
+				change.Deleted = true
+			case entities.BulkOpSetActive:
+				if op.Active == nil || *op.Active == category.Active {
+					continue
+				}
+				change.Changes = map[string]entities.BulkFieldChange{
+					"active": {From: category.Active, To: *op.Active},
+				}
+			default:
+				continue
+			}
+
+			result.Changes = append(result.Changes, change)
+			affectedIDs = append(affectedIDs, category.ID)
+		}
+		result.Affected = len(affectedIDs)
+
+		if dryRun || result.Affected == 0 {
+			return nil
+		}
+
+		if op.Type == entities.BulkOpDelete {
+			return tx.Delete(&entities.Category{}, affectedIDs).Error
+		}
+
+		return tx.Model(&entities.Category{}).
+			Where("id IN ?", affectedIDs).
+			Update("active", *op.Active).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Suggest returns active categories whose name, or a word of it, starts with
+// prefix.
+func (r *categoryRepository) Suggest(ctx context.Context, prefix string, limit int) ([]entities.Suggestion, error) {
+	return suggestNames(r.db.WithContext(ctx).Model(&entities.Category{}).Where("categories.active = ?", true), "categories", prefix, limit)
+}
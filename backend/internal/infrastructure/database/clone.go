@@ -0,0 +1,158 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+// copyItem returns an unsaved duplicate of item placed under subCategoryID.
+func copyItem(item *entities.Item, subCategoryID uint) entities.Item {
+	var dietaryInfo entities.DietaryInfo
+	if item.DietaryInfo != nil {
+		dietaryInfo = make(entities.DietaryInfo, len(item.DietaryInfo))
+		for key, value := range item.DietaryInfo {
+			dietaryInfo[key] = value
+		}
+	}
+
+	return entities.Item{
+		Name:          item.Name,
+		Description:   item.Description,
+		Price:         item.Price,
+		Currency:      item.Currency,
+		DietaryInfo:   dietaryInfo,
+		ImageURL:      item.ImageURL,
+		SubCategoryID: subCategoryID,
+		Available:     item.Available,
+		DisplayOrder:  item.DisplayOrder,
+	}
+}
+
+// copySubCategory returns an unsaved duplicate of subcategory placed under
+// categoryID, with duplicates of the items whose primary subcategory it is.
+// Items placed in it from elsewhere are shared, not duplicated; see
+// copyPlacements. The slug is regenerated on create.
+func copySubCategory(subcategory *entities.SubCategory, categoryID uint) entities.SubCategory {
+	clone := entities.SubCategory{
+		Name:         subcategory.Name,
+		Description:  subcategory.Description,
+		CategoryID:   categoryID,
+		DisplayOrder: subcategory.DisplayOrder,
+		Active:       subcategory.Active,
+		Items:        make([]entities.Item, 0, len(subcategory.Items)),
+	}
+
+	for i := range subcategory.Items {
+		if subcategory.Items[i].SubCategoryID == subcategory.ID {
+			clone.Items = append(clone.Items, copyItem(&subcategory.Items[i], 0))
+		}
+	}
+
+	return clone
+}
+
+// subCategoryPointers returns pointers to the elements of subcategories.
+func subCategoryPointers(subcategories []entities.SubCategory) []*entities.SubCategory {
+	pointers := make([]*entities.SubCategory, len(subcategories))
+	for i := range subcategories {
+		pointers[i] = &subcategories[i]
+	}
+	return pointers
+}
+
+// nextDisplayOrder returns the position after the last row selected by scope.
+func nextDisplayOrder(tx *gorm.DB, model interface{}, scope func(*gorm.DB) *gorm.DB) (int, error) {
+	var maxOrder int
+	err := scope(tx.Model(model)).
+		Select("COALESCE(MAX(display_order), 0)").
+		Scan(&maxOrder).Error
+	return maxOrder + 1, err
+}
+
+// uniqueCategoryName returns name, or name with a counter appended, such that
+// no category (including soft-deleted ones, which keep their unique index
+// entries) already uses it.
+func uniqueCategoryName(tx *gorm.DB, name string) (string, error) {
+	candidate := name
+	for attempt := 2; ; attempt++ {
+		var count int64
+		if err := tx.Unscoped().Model(&entities.Category{}).
+			Where("name = ?", candidate).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+
+		if count == 0 {
+			return candidate, nil
+		}
+
+		candidate = entities.CloneName(name, fmt.Sprintf(" %d", attempt), 100)
+	}
+}
+
+// cloneSubCategoryTree inserts copies of nodes, which must be ordered parents
+// first and have their Items loaded, under categoryID. Nodes whose parent is
+// not among them are attached to parentID. prepare, if set, may adjust each
+// copy before it is inserted. The copies are returned keyed by source ID.
+func cloneSubCategoryTree(tx *gorm.DB, nodes []entities.SubCategory, categoryID uint, parentID *uint, prepare func(source, clone *entities.SubCategory)) (map[uint]*entities.SubCategory, error) {
+	clones := make(map[uint]*entities.SubCategory, len(nodes))
+
+	for i := range nodes {
+		source := &nodes[i]
+
+		clone := copySubCategory(source, categoryID)
+		clone.ParentID = parentID
+		if source.ParentID != nil {
+			if parent, ok := clones[*source.ParentID]; ok {
+				clone.ParentID = &parent.ID
+			}
+		}
+
+		if prepare != nil {
+			prepare(source, &clone)
+		}
+
+		if err := tx.Create(&clone).Error; err != nil {
+			return nil, err
+		}
+
+		// One at a time, so that each new slug sees the ones before it
+		for j := range clone.Items {
+			clone.Items[j].SubCategoryID = clone.ID
+			if err := tx.Create(&clone.Items[j]).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		if err := copyPlacements(tx, source.ID, clone.ID); err != nil {
+			return nil, err
+		}
+
+		clones[source.ID] = &clone
+	}
+
+	return clones, nil
+}
+
+// siblingScope selects the subcategories sharing a parent: either parentID or,
+// when nil, the category itself.
+func siblingScope(categoryID uint, parentID *uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if parentID != nil {
+			return db.Where("parent_id = ?", *parentID)
+		}
+		return db.Where("category_id = ? AND parent_id IS NULL", categoryID)
+	}
+}
+
+func orderByDisplayOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("display_order ASC, id ASC")
+}
+
+// orderByTreePosition lists subcategories parents first.
+func orderByTreePosition(db *gorm.DB) *gorm.DB {
+	return db.Order("depth ASC, display_order ASC, id ASC")
+}
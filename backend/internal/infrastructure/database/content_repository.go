@@ -62,15 +62,7 @@ func (r *contentRepository) GetAll(ctx context.Context, filter entities.ContentS
 	}
 
 	// Apply ordering
-	orderBy := "section_name ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "content_sections", filter.Sort, "section_name ASC, created_at DESC")
 
 	if err := query.Find(&contents).Error; err != nil {
 		return nil, err
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+// preloader loads one relation along with a query.
+type preloader func(query *gorm.DB) *gorm.DB
+
+var (
+	itemPreloads = map[string]preloader{
+		"sub_category": func(query *gorm.DB) *gorm.DB {
+			return query.Preload("SubCategory")
+		},
+		"sub_category.category": func(query *gorm.DB) *gorm.DB {
+			return query.Preload("SubCategory.Category")
+		},
+		"placements": func(query *gorm.DB) *gorm.DB {
+			return query.Preload("Placements", orderByDisplayOrder)
+		},
+	}
+	categoryPreloads = map[string]preloader{
+		"sub_categories": func(query *gorm.DB) *gorm.DB {
+			return query.Preload("SubCategories", orderByTreePosition)
+		},
+	}
+	// Subcategory items are loaded through their placements after the query;
+	// see subCategoryRepository.expandItems
+	subCategoryPreloads = map[string]preloader{
+		"category": func(query *gorm.DB) *gorm.DB {
+			return query.Preload("Category")
+		},
+	}
+
+	defaultItemExpand        = entities.Expand{"sub_category", "sub_category.category"}
+	defaultSubCategoryExpand = entities.Expand{"category"}
+)
+
+// applyExpand preloads the relations in expand, or those in defaults when
+// expand is nil.
+func applyExpand(query *gorm.DB, expand, defaults entities.Expand, preloads map[string]preloader) *gorm.DB {
+	if expand == nil {
+		expand = defaults
+	}
+	for _, relation := range expand {
+		if preload, ok := preloads[relation]; ok {
+			query = preload(query)
+		}
+	}
+	return query
+}
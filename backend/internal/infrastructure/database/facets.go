@@ -0,0 +1,270 @@
+package database
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/pkg/utils"
+)
+
+var matchAll = clause.Expr{SQL: "TRUE"}
+
+// facetConditions holds the SQL condition for each facet's selections,
+// TRUE where nothing is selected.
+type facetConditions struct {
+	category    clause.Expr
+	subCategory clause.Expr
+	dietary     clause.Expr
+	tags        clause.Expr
+	price       clause.Expr
+}
+
+func newFacetConditions(filter entities.ItemFilter) facetConditions {
+	conditions := facetConditions{
+		category:    matchAll,
+		subCategory: matchAll,
+		dietary:     dietaryInfoCondition(filter.DietaryLabels),
+		tags:        dietaryInfoCondition(filter.Tags),
+		price:       priceBucketCondition(filter.PriceBuckets),
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		conditions.category = clause.Expr{
+			SQL: `items.id IN (SELECT item_placements.item_id FROM item_placements
+				JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id
+				WHERE sub_categories.category_id IN ? AND sub_categories.deleted_at IS NULL)`,
+			Vars: []interface{}{filter.CategoryIDs},
+		}
+	}
+
+	if len(filter.SubCategoryIDs) > 0 {
+		conditions.subCategory = clause.Expr{
+			SQL:  "items.id IN (SELECT item_id FROM item_placements WHERE sub_category_id IN ?)",
+			Vars: []interface{}{filter.SubCategoryIDs},
+		}
+	}
+
+	return conditions
+}
+
+// dietaryInfoCondition requires every key to be true in dietary_info.
+func dietaryInfoCondition(keys []string) clause.Expr {
+	if len(keys) == 0 {
+		return matchAll
+	}
+
+	conditions := make([]string, len(keys))
+	vars := make([]interface{}, len(keys))
+	for i, key := range keys {
+		conditions[i] = "items.dietary_info ->> ? = 'true'"
+		vars[i] = key
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " AND ") + ")", Vars: vars}
+}
+
+// priceBucketCondition matches prices in any of the buckets. Unknown keys are
+// ignored.
+func priceBucketCondition(keys []string) clause.Expr {
+	var conditions []string
+	var vars []interface{}
+	for _, key := range keys {
+		bucket, ok := entities.PriceBucketByKey(key)
+		if !ok {
+			continue
+		}
+		condition, bucketVars := priceBucketSQL("items.price", bucket)
+		conditions = append(conditions, condition)
+		vars = append(vars, bucketVars...)
+	}
+
+	if len(conditions) == 0 {
+		return matchAll
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " OR ") + ")", Vars: vars}
+}
+
+func priceBucketSQL(column string, bucket entities.PriceBucket) (string, []interface{}) {
+	if bucket.Max == 0 {
+		return "(" + column + " >= ?)", []interface{}{bucket.Min}
+	}
+	return "(" + column + " >= ? AND " + column + " < ?)", []interface{}{bucket.Min, bucket.Max}
+}
+
+// applyFacetSelections narrows a search query to the selected facet values.
+func applyFacetSelections(query *gorm.DB, filter entities.ItemFilter) *gorm.DB {
+	conditions := newFacetConditions(filter)
+	for _, condition := range []clause.Expr{conditions.category, conditions.subCategory, conditions.dietary, conditions.tags, conditions.price} {
+		if condition.SQL != matchAll.SQL {
+			query = query.Where(condition)
+		}
+	}
+	return query
+}
+
+// searchFacets counts the facet values of the items matched by query, before
+// facet selections are applied, in a single statement.
+func searchFacets(query *gorm.DB, filter entities.ItemFilter) (*entities.SearchFacets, error) {
+	conditions := newFacetConditions(filter)
+
+	matched := query.Session(&gorm.Session{}).Select(`items.id, items.price,
+		CASE WHEN jsonb_typeof(items.dietary_info) = 'object' THEN items.dietary_info ELSE '{}'::jsonb END AS dietary_info,
+		(?) AS in_category, (?) AS in_sub_category, (?) AS has_dietary, (?) AS has_tags, (?) AS in_price`,
+		conditions.category, conditions.subCategory, conditions.dietary, conditions.tags, conditions.price)
+
+	var bucketCases []string
+	var bucketVars []interface{}
+	for _, bucket := range entities.PriceBuckets {
+		condition, vars := priceBucketSQL("matched.price", bucket)
+		bucketCases = append(bucketCases, "WHEN "+condition+" THEN ?")
+		bucketVars = append(bucketVars, vars...)
+		bucketVars = append(bucketVars, bucket.Key)
+	}
+
+	vars := []interface{}{matched, entities.DietaryLabels, entities.DietaryLabels}
+	vars = append(vars, bucketVars...)
+
+	var rows []struct {
+		Facet string
+		Value string
+		Label string
+		Count int64
+	}
+	err := query.Session(&gorm.Session{NewDB: true}).Raw(`
+		WITH matched AS (?)
+		SELECT 'category' AS facet, categories.id::text AS value, categories.name AS label, COUNT(DISTINCT matched.id) AS count
+		FROM matched
+		JOIN item_placements ON item_placements.item_id = matched.id
+		JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id AND sub_categories.deleted_at IS NULL
+		JOIN categories ON categories.id = sub_categories.category_id AND categories.deleted_at IS NULL
+		WHERE matched.in_sub_category AND matched.has_dietary AND matched.has_tags AND matched.in_price
+		GROUP BY categories.id, categories.name
+		UNION ALL
+		SELECT 'sub_category', sub_categories.id::text, sub_categories.name, COUNT(DISTINCT matched.id)
+		FROM matched
+		JOIN item_placements ON item_placements.item_id = matched.id
+		JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id AND sub_categories.deleted_at IS NULL
+		WHERE matched.in_category AND matched.has_dietary AND matched.has_tags AND matched.in_price
+		GROUP BY sub_categories.id, sub_categories.name
+		UNION ALL
+		SELECT 'dietary', label.key, label.key, COUNT(*)
+		FROM matched CROSS JOIN jsonb_each_text(matched.dietary_info) AS label
+		WHERE label.value = 'true' AND label.key IN ?
+			AND matched.in_category AND matched.in_sub_category AND matched.has_dietary AND matched.has_tags AND matched.in_price
+		GROUP BY label.key
+		UNION ALL
+		SELECT 'tag', label.key, label.key, COUNT(*)
+		FROM matched CROSS JOIN jsonb_each_text(matched.dietary_info) AS label
+		WHERE label.value = 'true' AND label.key NOT IN ?
+			AND matched.in_category AND matched.in_sub_category AND matched.has_dietary AND matched.has_tags AND matched.in_price
+		GROUP BY label.key
+		UNION ALL
+		SELECT 'price', buckets.key, buckets.key, COUNT(*)
+		FROM (
+			SELECT CASE `+strings.Join(bucketCases, " ")+` END AS key
+			FROM matched
+			WHERE matched.in_category AND matched.in_sub_category AND matched.has_dietary AND matched.has_tags
+		) AS buckets
+		WHERE buckets.key IS NOT NULL
+		GROUP BY buckets.key`, vars...).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	facets := emptySearchFacets()
+
+	selectedCategories := uintSet(filter.CategoryIDs)
+	selectedSubCategories := uintSet(filter.SubCategoryIDs)
+	counts := make(map[string]int64)
+
+	for _, row := range rows {
+		value := entities.FacetValue{Value: row.Value, Label: row.Label, Count: row.Count}
+		switch row.Facet {
+		case "category":
+			value.Selected = selectedCategories[row.Value]
+			facets.Categories = append(facets.Categories, value)
+		case "sub_category":
+			value.Selected = selectedSubCategories[row.Value]
+			facets.SubCategories = append(facets.SubCategories, value)
+		case "tag":
+			value.Label = facetLabel(row.Value)
+			value.Selected = utils.Contains(filter.Tags, row.Value)
+			facets.Tags = append(facets.Tags, value)
+		default:
+			counts[row.Facet+":"+row.Value] = row.Count
+		}
+	}
+
+	sortFacetValues(facets.Categories)
+	sortFacetValues(facets.SubCategories)
+	sortFacetValues(facets.Tags)
+
+	// Dietary labels and price buckets keep their defined order
+	for _, label := range entities.DietaryLabels {
+		if count, ok := counts["dietary:"+label]; ok {
+			facets.DietaryLabels = append(facets.DietaryLabels, entities.FacetValue{
+				Value:    label,
+				Label:    facetLabel(label),
+				Count:    count,
+				Selected: utils.Contains(filter.DietaryLabels, label),
+			})
+		}
+	}
+	for _, bucket := range entities.PriceBuckets {
+		if count, ok := counts["price:"+bucket.Key]; ok {
+			facets.PriceBuckets = append(facets.PriceBuckets, entities.FacetValue{
+				Value:    bucket.Key,
+				Label:    bucket.Label,
+				Count:    count,
+				Selected: utils.Contains(filter.PriceBuckets, bucket.Key),
+			})
+		}
+	}
+
+	return facets, nil
+}
+
+func emptySearchFacets() *entities.SearchFacets {
+	return &entities.SearchFacets{
+		Categories:    []entities.FacetValue{},
+		SubCategories: []entities.FacetValue{},
+		DietaryLabels: []entities.FacetValue{},
+		Tags:          []entities.FacetValue{},
+		PriceBuckets:  []entities.FacetValue{},
+	}
+}
+
+// sortFacetValues puts the values with the most results first.
+func sortFacetValues(values []entities.FacetValue) {
+	sort.SliceStable(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Label < values[j].Label
+	})
+}
+
+// facetLabel turns a dietary_info key such as gluten_free into "Gluten Free".
+func facetLabel(key string) string {
+	words := strings.Fields(strings.ReplaceAll(key, "_", " "))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func uintSet(ids []uint) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[strconv.FormatUint(uint64(id), 10)] = true
+	}
+	return set
+}
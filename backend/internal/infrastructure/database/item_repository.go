@@ -3,10 +3,10 @@ package database
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
@@ -29,6 +29,7 @@ func (r *itemRepository) GetByID(ctx context.Context, id uint) (*entities.Item,
 	err := r.db.WithContext(ctx).
 		Preload("SubCategory").
 		Preload("SubCategory.Category").
+		Preload("Placements", orderByDisplayOrder).
 		First(&item, id).Error
 	
 	if err != nil {
@@ -40,22 +41,37 @@ func (r *itemRepository) GetByID(ctx context.Context, id uint) (*entities.Item,
 	return &item, nil
 }
 
+func (r *itemRepository) GetBySlug(ctx context.Context, slug string) (*entities.Item, error) {
+	var item entities.Item
+	err := r.db.WithContext(ctx).
+		Preload("SubCategory").
+		Preload("SubCategory.Category").
+		Preload("Placements", orderByDisplayOrder).
+		Where("slug = ?", slug).
+		First(&item).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
 func (r *itemRepository) GetAll(ctx context.Context, filter entities.ItemFilter) ([]*entities.Item, *entities.Pagination, error) {
 	var items []*entities.Item
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&entities.Item{}).
-		Preload("SubCategory").
-		Preload("SubCategory.Category")
+	query := applyExpand(r.db.WithContext(ctx).Model(&entities.Item{}), filter.Expand, defaultItemExpand, itemPreloads)
 
 	// Apply filters
 	if filter.SubCategoryID != nil {
-		query = query.Where("sub_category_id = ?", *filter.SubCategoryID)
+		query = placedInSubCategory(query, *filter.SubCategoryID)
 	}
 
 	if filter.CategoryID != nil {
-		query = query.Joins("JOIN sub_categories ON items.sub_category_id = sub_categories.id").
-			Where("sub_categories.category_id = ?", *filter.CategoryID)
+		query = placedInCategory(query, *filter.CategoryID)
 	}
 
 	if filter.Available != nil {
@@ -75,9 +91,15 @@ func (r *itemRepository) GetAll(ctx context.Context, filter entities.ItemFilter)
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
 	}
 
+	if filter.Cursor != nil {
+		return r.getAllAfterCursor(ctx, query, filter)
+	}
+
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, nil, err
+	if filter.IncludeCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Apply pagination
@@ -89,15 +111,7 @@ func (r *itemRepository) GetAll(ctx context.Context, filter entities.ItemFilter)
 	}
 
 	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "items", filter.Sort, "display_order ASC, created_at DESC")
 
 	if err := query.Find(&items).Error; err != nil {
 		return nil, nil, err
@@ -105,51 +119,67 @@ func (r *itemRepository) GetAll(ctx context.Context, filter entities.ItemFilter)
 
 	var pagination *entities.Pagination
 	if filter.IncludeCount {
-		page := (filter.Offset / filter.Limit) + 1
-		if filter.Limit == 0 {
-			page = 1
-		}
-		pagination = entities.NewPagination(page, filter.Limit, total)
+		pagination = offsetPagination(filter.Offset, filter.Limit, total)
 	}
 
 	return items, pagination, nil
 }
 
+// getAllAfterCursor returns the page of query after filter.Cursor.
+func (r *itemRepository) getAllAfterCursor(ctx context.Context, query *gorm.DB, filter entities.ItemFilter) ([]*entities.Item, *entities.Pagination, error) {
+	var total int64
+	if filter.IncludeCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var items []*entities.Item
+	limit := cursorLimit(filter.Limit)
+	if err := applyCursor(query, "items", filter.Cursor, limit).Find(&items).Error; err != nil {
+		return nil, nil, err
+	}
+
+	fetched := len(items)
+	if fetched > limit {
+		items = items[:limit]
+	}
+
+	var last entities.Cursor
+	if len(items) > 0 {
+		tail := items[len(items)-1]
+		last = entities.Cursor{DisplayOrder: tail.DisplayOrder, ID: tail.ID}
+	}
+
+	return items, cursorPagination(limit, fetched, total, last), nil
+}
+
 func (r *itemRepository) GetBySubCategoryID(ctx context.Context, subCategoryID uint, filter entities.ItemFilter) ([]*entities.Item, error) {
 	var items []*entities.Item
 
 	query := r.db.WithContext(ctx).
-		Where("sub_category_id = ?", subCategoryID).
-		Preload("SubCategory").
-		Preload("SubCategory.Category")
+		Joins("JOIN item_placements ON item_placements.item_id = items.id AND item_placements.sub_category_id = ?", subCategoryID)
+	query = applyExpand(query, filter.Expand, defaultItemExpand, itemPreloads)
 
 	if filter.Available != nil {
-		query = query.Where("available = ?", *filter.Available)
+		query = query.Where("items.available = ?", *filter.Available)
 	}
 
 	if filter.MinPrice != nil {
-		query = query.Where("price >= ?", *filter.MinPrice)
+		query = query.Where("items.price >= ?", *filter.MinPrice)
 	}
 
 	if filter.MaxPrice != nil {
-		query = query.Where("price <= ?", *filter.MaxPrice)
+		query = query.Where("items.price <= ?", *filter.MaxPrice)
 	}
 
 	if filter.Search != "" {
 		search := "%" + strings.ToLower(filter.Search) + "%"
-		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
+		query = query.Where("LOWER(items.name) LIKE ? OR LOWER(items.description) LIKE ?", search, search)
 	}
 
-	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	// Apply ordering, by position within this subcategory by default
+	query = applySort(query, "items", filter.Sort, "item_placements.display_order ASC, items.created_at DESC")
 
 	if err := query.Find(&items).Error; err != nil {
 		return nil, err
@@ -161,11 +191,7 @@ func (r *itemRepository) GetBySubCategoryID(ctx context.Context, subCategoryID u
 func (r *itemRepository) GetByCategoryID(ctx context.Context, categoryID uint, filter entities.ItemFilter) ([]*entities.Item, error) {
 	var items []*entities.Item
 
-	query := r.db.WithContext(ctx).
-		Joins("JOIN sub_categories ON items.sub_category_id = sub_categories.id").
-		Where("sub_categories.category_id = ?", categoryID).
-		Preload("SubCategory").
-		Preload("SubCategory.Category")
+	query := applyExpand(placedInCategory(r.db.WithContext(ctx), categoryID), filter.Expand, defaultItemExpand, itemPreloads)
 
 	if filter.Available != nil {
 		query = query.Where("items.available = ?", *filter.Available)
@@ -185,15 +211,7 @@ func (r *itemRepository) GetByCategoryID(ctx context.Context, categoryID uint, f
 	}
 
 	// Apply ordering
-	orderBy := "items.display_order ASC, items.created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = "items." + filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "items", filter.Sort, "items.display_order ASC, items.created_at DESC")
 
 	if err := query.Find(&items).Error; err != nil {
 		return nil, err
@@ -202,79 +220,238 @@ func (r *itemRepository) GetByCategoryID(ctx context.Context, categoryID uint, f
 	return items, nil
 }
 
+// GetBySubCategoryIDs loads the items placed in each of the subcategories,
+// keyed by subcategory and in their position there. It takes two queries plus
+// one per expanded relation however many subcategories there are. An item
+// placed in several of them is shared between their lists.
+func (r *itemRepository) GetBySubCategoryIDs(ctx context.Context, subCategoryIDs []uint, filter entities.ItemFilter) (map[uint][]*entities.Item, error) {
+	grouped := make(map[uint][]*entities.Item, len(subCategoryIDs))
+	if len(subCategoryIDs) == 0 {
+		return grouped, nil
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&entities.ItemPlacement{}).
+		Select("item_placements.item_id, item_placements.sub_category_id").
+		Joins("JOIN items ON items.id = item_placements.item_id AND items.deleted_at IS NULL").
+		Where("item_placements.sub_category_id IN ?", subCategoryIDs)
+
+	if filter.Available != nil {
+		query = query.Where("items.available = ?", *filter.Available)
+	}
+
+	if filter.MinPrice != nil {
+		query = query.Where("items.price >= ?", *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		query = query.Where("items.price <= ?", *filter.MaxPrice)
+	}
+
+	if filter.Search != "" {
+		search := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(items.name) LIKE ? OR LOWER(items.description) LIKE ?", search, search)
+	}
+
+	// Apply ordering, by position within each subcategory by default
+	query = applySort(query.Order("item_placements.sub_category_id ASC"), "items", filter.Sort, "item_placements.display_order ASC, items.created_at DESC")
+
+	var placements []entities.ItemPlacement
+	if err := query.Find(&placements).Error; err != nil {
+		return nil, err
+	}
+
+	if len(placements) == 0 {
+		return grouped, nil
+	}
+
+	ids := make([]uint, 0, len(placements))
+	seen := make(map[uint]bool, len(placements))
+	for _, placement := range placements {
+		if !seen[placement.ItemID] {
+			seen[placement.ItemID] = true
+			ids = append(ids, placement.ItemID)
+		}
+	}
+
+	var items []*entities.Item
+	if err := applyExpand(r.db.WithContext(ctx), filter.Expand, defaultItemExpand, itemPreloads).
+		Where("id IN ?", ids).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*entities.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	for _, placement := range placements {
+		if item, ok := byID[placement.ItemID]; ok {
+			grouped[placement.SubCategoryID] = append(grouped[placement.SubCategoryID], item)
+		}
+	}
+
+	return grouped, nil
+}
+
 func (r *itemRepository) Update(ctx context.Context, item *entities.Item) error {
-	return r.db.WithContext(ctx).Save(item).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entities.Item
+		if err := tx.Select("id", "sub_category_id").First(&current, item.ID).Error; err != nil {
+			return err
+		}
+
+		// Skip associations so a preloaded SubCategory can't overwrite a new SubCategoryID
+		if err := tx.Omit(clause.Associations).Save(item).Error; err != nil {
+			return err
+		}
+
+		if current.SubCategoryID != item.SubCategoryID {
+			return moveHomePlacement(tx, item.ID, current.SubCategoryID, item.SubCategoryID, item.DisplayOrder)
+		}
+		return nil
+	})
 }
 
 func (r *itemRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&entities.Item{}, id).Error
 }
 
-func (r *itemRepository) Search(ctx context.Context, query string, filter entities.ItemFilter) ([]*entities.Item, *entities.Pagination, error) {
-	var items []*entities.Item
-	var total int64
+func (r *itemRepository) Search(ctx context.Context, query string, filter entities.ItemFilter) (*entities.ItemSearchResult, error) {
+	tsQuery := prefixTSQuery(query, filter.Dictionary)
+	if tsQuery == "" {
+		return emptySearchResult(filter), nil
+	}
 
-	search := "%" + strings.ToLower(query) + "%"
-	
-	dbQuery := r.db.WithContext(ctx).Model(&entities.Item{}).
-		Preload("SubCategory").
-		Preload("SubCategory.Category").
-		Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
+	dbQuery := applySearchFilters(r.db.WithContext(ctx).Model(&entities.Item{}).
+		Where("items.search_vector @@ to_tsquery('english', ?)", tsQuery), filter)
 
-	// Apply additional filters
-	if filter.SubCategoryID != nil {
-		dbQuery = dbQuery.Where("sub_category_id = ?", *filter.SubCategoryID)
+	result, err := searchPage(dbQuery, filter, clause.Expr{
+		SQL:  "ts_rank(items.search_vector, to_tsquery('english', ?))",
+		Vars: []interface{}{tsQuery},
+	})
+	if err != nil || len(result.Items) == 0 {
+		return result, err
 	}
 
-	if filter.CategoryID != nil {
-		dbQuery = dbQuery.Joins("JOIN sub_categories ON items.sub_category_id = sub_categories.id").
-			Where("sub_categories.category_id = ?", *filter.CategoryID)
+	// Headlines are costly, so only build them for the rows on the page
+	var headlines []struct {
+		ID                  uint
+		NameHeadline        string
+		DescriptionHeadline string
 	}
-
-	if filter.Available != nil {
-		dbQuery = dbQuery.Where("available = ?", *filter.Available)
+	err = r.db.WithContext(ctx).Model(&entities.Item{}).
+		Select(`items.id,
+			ts_headline('english', items.name, to_tsquery('english', ?), ?) AS name_headline,
+			ts_headline('english', COALESCE(items.description, ''), to_tsquery('english', ?), ?) AS description_headline`,
+			tsQuery, nameHeadlineOptions, tsQuery, descriptionHeadlineOptions).
+		Where("items.id IN ?", searchResultIDs(result)).
+		Scan(&headlines).Error
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.MinPrice != nil {
-		dbQuery = dbQuery.Where("price >= ?", *filter.MinPrice)
+	byID := make(map[uint]int, len(result.Highlights))
+	for i, highlight := range result.Highlights {
+		byID[highlight.ItemID] = i
 	}
-
-	if filter.MaxPrice != nil {
-		dbQuery = dbQuery.Where("price <= ?", *filter.MaxPrice)
+	for _, headline := range headlines {
+		i, ok := byID[headline.ID]
+		if !ok {
+			continue
+		}
+		result.Highlights[i].Name = renderHighlight(headline.NameHeadline)
+		if result.Items[i].Description != "" {
+			result.Highlights[i].Description = renderHighlight(headline.DescriptionHeadline)
+		}
 	}
 
-	// Count total records
-	if err := dbQuery.Count(&total).Error; err != nil {
-		return nil, nil, err
-	}
+	return result, nil
+}
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		dbQuery = dbQuery.Limit(filter.Limit)
+// SearchSimilar matches item names and descriptions by trigram word
+// similarity, so misspelled queries still find items.
+func (r *itemRepository) SearchSimilar(ctx context.Context, query string, threshold float64, filter entities.ItemFilter) (*entities.ItemSearchResult, error) {
+	text := normalizeSearchText(query)
+	if text == "" {
+		return emptySearchResult(filter), nil
 	}
-	if filter.Offset > 0 {
-		dbQuery = dbQuery.Offset(filter.Offset)
+
+	var result *entities.ItemSearchResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// <% only uses the trigram indexes with the threshold set as a setting
+		if err := setSimilarityThreshold(tx, "pg_trgm.word_similarity_threshold", threshold); err != nil {
+			return err
+		}
+
+		dbQuery := applySearchFilters(tx.Model(&entities.Item{}).
+			Where("(? <% LOWER(items.name) OR ? <% LOWER(items.description))", text, text), filter)
+
+		// Name matches outrank description matches
+		var err error
+		result, err = searchPage(dbQuery, filter, clause.Expr{
+			SQL:  "GREATEST(word_similarity(?, LOWER(items.name)), word_similarity(?, LOWER(COALESCE(items.description, ''))) / 2)",
+			Vars: []interface{}{text, text},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Apply ordering with relevance (exact match first, then partial matches)
-	orderBy := fmt.Sprintf("CASE WHEN LOWER(name) = LOWER('%s') THEN 1 WHEN LOWER(name) LIKE '%s' THEN 2 ELSE 3 END, display_order ASC", 
-		strings.ToLower(query), search)
-	dbQuery = dbQuery.Order(orderBy)
+	return result, nil
+}
 
-	if err := dbQuery.Find(&items).Error; err != nil {
-		return nil, nil, err
+// SuggestQuery corrects each word of query to the closest word used in menu
+// names, returning "" when there is nothing to correct.
+func (r *itemRepository) SuggestQuery(ctx context.Context, query string, threshold float64) (string, error) {
+	terms := searchTermRegex.FindAllString(strings.ToLower(query), maxSearchTerms)
+	if len(terms) == 0 {
+		return "", nil
 	}
 
-	var pagination *entities.Pagination
-	if filter.IncludeCount {
-		page := (filter.Offset / filter.Limit) + 1
-		if filter.Limit == 0 {
-			page = 1
+	suggested := make([]string, len(terms))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// % only uses the trigram index with the threshold set as a setting
+		if err := setSimilarityThreshold(tx, "pg_trgm.similarity_threshold", threshold); err != nil {
+			return err
+		}
+
+		// search_words holds the words of the menu's own names, kept current
+		// by triggers; see migration 000012
+		for i, term := range terms {
+			var closest []string
+			if err := tx.Table("search_words").
+				Where("word % ?", term).
+				Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "similarity(word, ?) DESC, word ASC", Vars: []interface{}{term}}}).
+				Limit(1).
+				Pluck("word", &closest).Error; err != nil {
+				return err
+			}
+
+			suggested[i] = term
+			if len(closest) > 0 {
+				suggested[i] = closest[0]
+			}
 		}
-		pagination = entities.NewPagination(page, filter.Limit, total)
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
-	return items, pagination, nil
+	suggestion := strings.Join(suggested, " ")
+	if suggestion == strings.Join(terms, " ") {
+		return "", nil
+	}
+	return suggestion, nil
+}
+
+// Suggest returns available items whose name, or a word of it, starts with
+// prefix.
+func (r *itemRepository) Suggest(ctx context.Context, prefix string, limit int) ([]entities.Suggestion, error) {
+	return suggestNames(r.db.WithContext(ctx).Model(&entities.Item{}).Where("items.available = ?", true), "items", prefix, limit)
 }
 
 func (r *itemRepository) Count(ctx context.Context, filter entities.ItemFilter) (int64, error) {
@@ -282,12 +459,11 @@ func (r *itemRepository) Count(ctx context.Context, filter entities.ItemFilter)
 	query := r.db.WithContext(ctx).Model(&entities.Item{})
 
 	if filter.SubCategoryID != nil {
-		query = query.Where("sub_category_id = ?", *filter.SubCategoryID)
+		query = placedInSubCategory(query, *filter.SubCategoryID)
 	}
 
 	if filter.CategoryID != nil {
-		query = query.Joins("JOIN sub_categories ON items.sub_category_id = sub_categories.id").
-			Where("sub_categories.category_id = ?", *filter.CategoryID)
+		query = placedInCategory(query, *filter.CategoryID)
 	}
 
 	if filter.Available != nil {
@@ -350,4 +526,260 @@ func (r *itemRepository) GetFeatured(ctx context.Context, limit int) ([]*entitie
 	}
 
 	return items, nil
-}
\ No newline at end of file
+}
+
+func (r *itemRepository) Reorder(ctx context.Context, subCategoryID uint, ids []uint) error {
+	return reorderPlacements(ctx, r.db, subCategoryID, ids)
+}
+
+func (r *itemRepository) CompactDisplayOrder(ctx context.Context) (int64, error) {
+	affected, err := compactDisplayOrder(ctx, r.db, "items", "sub_category_id")
+	if err != nil {
+		return affected, err
+	}
+
+	placements, err := compactPlacementOrder(ctx, r.db)
+	return affected + placements, err
+}
+
+func (r *itemRepository) RegenerateFallbackSlugs(ctx context.Context) (int64, error) {
+	return regenerateFallbackSlugs(ctx, r.db, "item",
+		func(i *entities.Item) string { return i.Name },
+		func(i *entities.Item) { i.Slug = "" })
+}
+
+func (r *itemRepository) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.Item, error) {
+	var clone entities.Item
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source entities.Item
+		if err := tx.First(&source, id).Error; err != nil {
+			return err
+		}
+
+		subCategoryID := source.SubCategoryID
+		if opts.TargetParentID != nil {
+			subCategoryID = *opts.TargetParentID
+		}
+
+		displayOrder, err := nextDisplayOrder(tx, &entities.ItemPlacement{}, func(db *gorm.DB) *gorm.DB {
+			return db.Where("sub_category_id = ?", subCategoryID)
+		})
+		if err != nil {
+			return err
+		}
+
+		clone = copyItem(&source, subCategoryID)
+		clone.Name = entities.CloneName(source.Name, opts.Suffix(), 150)
+		clone.DisplayOrder = displayOrder
+
+		return tx.Create(&clone).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &clone, nil
+}
+
+func (r *itemRepository) BulkApply(ctx context.Context, selector entities.BulkItemSelector, op entities.BulkItemOperation, dryRun bool) (*entities.BulkResult, error) {
+	result := &entities.BulkResult{
+		DryRun:  dryRun,
+		Changes: []entities.BulkChange{},
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var items []*entities.Item
+
+		// Lock the selected rows so the preview matches what gets written
+		query := applyBulkItemSelector(tx.Model(&entities.Item{}), selector).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Order("items.id ASC")
+
+		if err := query.Find(&items).Error; err != nil {
+			return err
+		}
+		result.Matched = len(items)
+
+		for _, item := range items {
+			change := entities.BulkChange{ID: item.ID, Name: item.Name}
+			if op.Type == entities.BulkOpDelete {
+				change.Deleted = true
+			} else {
+				change.Changes = op.Apply(item)
+				if len(change.Changes) == 0 {
+					continue
+				}
+			}
+			result.Changes = append(result.Changes, change)
+		}
+		result.Affected = len(result.Changes)
+
+		if dryRun || result.Affected == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(result.Changes))
+		for _, change := range result.Changes {
+			ids = append(ids, change.ID)
+		}
+
+		if op.Type == entities.BulkOpDelete {
+			return tx.Delete(&entities.Item{}, ids).Error
+		}
+
+		if op.Type == entities.BulkOpMoveSubCategory {
+			// Drop the old home placements while the items still point at them
+			if err := tx.Where("item_id IN ? AND sub_category_id = (SELECT items.sub_category_id FROM items WHERE items.id = item_placements.item_id)", ids).
+				Delete(&entities.ItemPlacement{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entities.Item{}).
+			Where("id IN ?", ids).
+			Updates(bulkItemUpdates(op, result.Changes)).Error; err != nil {
+			return err
+		}
+
+		if op.Type == entities.BulkOpMoveSubCategory {
+			return placeItemsLast(tx, ids, *op.SubCategoryID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// bulkItemUpdates returns the column updates writing changes, as computed by
+// op.Apply, in a single statement. New prices differ from item to item, so
+// they are picked by ID.
+func bulkItemUpdates(op entities.BulkItemOperation, changes []entities.BulkChange) map[string]interface{} {
+	switch op.Type {
+	case entities.BulkOpPriceChange:
+		var sql strings.Builder
+		vars := make([]interface{}, 0, 2*len(changes))
+		sql.WriteString("CASE id")
+		for _, change := range changes {
+			sql.WriteString(" WHEN ? THEN ?::numeric")
+			vars = append(vars, change.ID, change.Changes["price"].To)
+		}
+		sql.WriteString(" END")
+		return map[string]interface{}{"price": gorm.Expr(sql.String(), vars...)}
+	case entities.BulkOpSetAvailability:
+		return map[string]interface{}{"available": *op.Available}
+	case entities.BulkOpMoveSubCategory:
+		return map[string]interface{}{"sub_category_id": *op.SubCategoryID}
+	case entities.BulkOpSetCurrency:
+		return map[string]interface{}{"currency": op.Currency}
+	}
+	return nil
+}
+
+// applyBulkItemSelector limits an items query to the selected items. Items are
+// selected by every subcategory they are placed in, not only their primary one.
+func applyBulkItemSelector(query *gorm.DB, selector entities.BulkItemSelector) *gorm.DB {
+	if len(selector.IDs) > 0 {
+		query = query.Where("items.id IN ?", selector.IDs)
+	}
+
+	if len(selector.SubCategoryIDs) > 0 {
+		query = placedInAnySubCategory(query, selector.SubCategoryIDs)
+	}
+
+	if len(selector.CategoryIDs) > 0 {
+		query = placedInAnyCategory(query, selector.CategoryIDs)
+	}
+
+	for _, tag := range selector.Tags {
+		query = query.Where("items.dietary_info ->> ? = 'true'", tag)
+	}
+
+	if filter := selector.Filter; filter != nil {
+		if filter.SubCategoryID != nil {
+			query = placedInAnySubCategory(query, []uint{*filter.SubCategoryID})
+		}
+
+		if filter.CategoryID != nil {
+			query = placedInAnyCategory(query, []uint{*filter.CategoryID})
+		}
+
+		if filter.Available != nil {
+			query = query.Where("items.available = ?", *filter.Available)
+		}
+
+		if filter.MinPrice != nil {
+			query = query.Where("items.price >= ?", *filter.MinPrice)
+		}
+
+		if filter.MaxPrice != nil {
+			query = query.Where("items.price <= ?", *filter.MaxPrice)
+		}
+
+		if filter.Search != "" {
+			search := "%" + strings.ToLower(filter.Search) + "%"
+			query = query.Where("LOWER(items.name) LIKE ? OR LOWER(items.description) LIKE ?", search, search)
+		}
+	}
+
+	return query
+}
+
+
+func (r *itemRepository) GetPlacements(ctx context.Context, itemID uint) ([]entities.ItemPlacement, error) {
+	var placements []entities.ItemPlacement
+	err := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Preload("SubCategory").
+		Order("created_at ASC, id ASC").
+		Find(&placements).Error
+	return placements, err
+}
+
+func (r *itemRepository) AddPlacement(ctx context.Context, placement *entities.ItemPlacement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the subcategory so it can't be deleted before the placement is
+		// written, and report a missing one rather than failing the foreign key
+		var subCategory entities.SubCategory
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Select("id").
+			First(&subCategory, placement.SubCategoryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repositories.ErrSubCategoryNotFound
+			}
+			return err
+		}
+
+		if placement.DisplayOrder == 0 {
+			displayOrder, err := nextDisplayOrder(tx, &entities.ItemPlacement{}, func(db *gorm.DB) *gorm.DB {
+				return db.Where("sub_category_id = ?", placement.SubCategoryID)
+			})
+			if err != nil {
+				return err
+			}
+			placement.DisplayOrder = displayOrder
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(placement)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrDuplicatePlacement
+		}
+		return nil
+	})
+}
+
+func (r *itemRepository) RemovePlacement(ctx context.Context, itemID, subCategoryID uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("item_id = ? AND sub_category_id = ?", itemID, subCategoryID).
+		Delete(&entities.ItemPlacement{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,227 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+// newMockDB returns a gorm connection whose statements are checked against
+// the expectations set on the mock.
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+// itemRows is an item with ID 7 whose primary subcategory is 1. The tests
+// place it in subcategory 2 as well.
+func itemRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "name", "price", "currency", "sub_category_id", "available"}).
+		AddRow(7, "Soup", 12.5, "AED", 1, true)
+}
+
+func TestBulkApplySelectsItemsByPlacement(t *testing.T) {
+	unavailable := false
+	op := entities.BulkItemOperation{Type: entities.BulkOpSetAvailability, Available: &unavailable}
+
+	tests := []struct {
+		name     string
+		selector entities.BulkItemSelector
+		where    string
+		arg      uint
+	}{
+		{
+			name:     "sub_category_ids",
+			selector: entities.BulkItemSelector{SubCategoryIDs: []uint{2}},
+			where:    `EXISTS (SELECT 1 FROM "item_placements" WHERE item_placements.item_id = items.id AND item_placements.sub_category_id IN ($1))`,
+			arg:      2,
+		},
+		{
+			name:     "filter.sub_category_id",
+			selector: entities.BulkItemSelector{Filter: &entities.ItemFilter{SubCategoryID: uintPointer(2)}},
+			where:    `EXISTS (SELECT 1 FROM "item_placements" WHERE item_placements.item_id = items.id AND item_placements.sub_category_id IN ($1))`,
+			arg:      2,
+		},
+		{
+			name:     "category_ids",
+			selector: entities.BulkItemSelector{CategoryIDs: []uint{3}},
+			where:    `EXISTS (SELECT 1 FROM "item_placements" JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id WHERE item_placements.item_id = items.id AND sub_categories.category_id IN ($1) AND sub_categories.deleted_at IS NULL)`,
+			arg:      3,
+		},
+		{
+			name:     "filter.category_id",
+			selector: entities.BulkItemSelector{Filter: &entities.ItemFilter{CategoryID: uintPointer(3)}},
+			where:    `EXISTS (SELECT 1 FROM "item_placements" JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id WHERE item_placements.item_id = items.id AND sub_categories.category_id IN ($1) AND sub_categories.deleted_at IS NULL)`,
+			arg:      3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+
+			// The item is only placed in the selected subcategory, so it
+			// must be matched through item_placements and not through
+			// items.sub_category_id
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "items" WHERE ` + tt.where + ` AND "items"."deleted_at" IS NULL ORDER BY items.id ASC FOR UPDATE`)).
+				WithArgs(tt.arg).
+				WillReturnRows(itemRows())
+			mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "available"=$1,"updated_at"=$2 WHERE id IN ($3) AND "items"."deleted_at" IS NULL`)).
+				WithArgs(false, sqlmock.AnyArg(), 7).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			result, err := NewItemRepository(db).BulkApply(context.Background(), tt.selector, op, false)
+			if err != nil {
+				t.Fatalf("BulkApply: %v", err)
+			}
+			if result.Matched != 1 || result.Affected != 1 {
+				t.Errorf("matched %d and affected %d items, want 1 each", result.Matched, result.Affected)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBulkApplyWritesPricesInOneStatement(t *testing.T) {
+	db, mock := newMockDB(t)
+	op := entities.BulkItemOperation{Type: entities.BulkOpPriceChange, PriceMode: entities.PriceChangePercentage, Amount: 10}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "items" WHERE items.id IN ($1,$2,$3)`)).
+		WithArgs(7, 8, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "currency", "sub_category_id", "available"}).
+			AddRow(7, "Soup", 10, "AED", 1, true).
+			AddRow(8, "Water", 0, "AED", 1, true).
+			AddRow(9, "Salad", 20, "AED", 1, true))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "price"=CASE id WHEN $1 THEN $2::numeric WHEN $3 THEN $4::numeric END,"updated_at"=$5 WHERE id IN ($6,$7) AND "items"."deleted_at" IS NULL`)).
+		WithArgs(7, 11.0, 9, 22.0, sqlmock.AnyArg(), 7, 9).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := NewItemRepository(db).BulkApply(context.Background(), entities.BulkItemSelector{IDs: []uint{7, 8, 9}}, op, false)
+	if err != nil {
+		t.Fatalf("BulkApply: %v", err)
+	}
+	if result.Matched != 3 || result.Affected != 2 {
+		t.Errorf("matched %d and affected %d items, want 3 and 2", result.Matched, result.Affected)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBulkApplyMovesHomePlacements(t *testing.T) {
+	db, mock := newMockDB(t)
+	op := entities.BulkItemOperation{Type: entities.BulkOpMoveSubCategory, SubCategoryID: uintPointer(4)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "items" WHERE items.id IN ($1,$2)`)).
+		WithArgs(7, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "currency", "sub_category_id", "available"}).
+			AddRow(7, "Soup", 10, "AED", 1, true).
+			AddRow(8, "Salad", 20, "AED", 2, true))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "item_placements" WHERE item_id IN ($1,$2) AND sub_category_id = (SELECT items.sub_category_id FROM items WHERE items.id = item_placements.item_id)`)).
+		WithArgs(7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "sub_category_id"=$1,"updated_at"=$2 WHERE id IN ($3,$4) AND "items"."deleted_at" IS NULL`)).
+		WithArgs(4, sqlmock.AnyArg(), 7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(display_order), 0) FROM "item_placements" WHERE sub_category_id = $1`)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(5))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO item_placements`)).
+		WithArgs(4, 6, 7, 8).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	result, err := NewItemRepository(db).BulkApply(context.Background(), entities.BulkItemSelector{IDs: []uint{7, 8}}, op, false)
+	if err != nil {
+		t.Fatalf("BulkApply: %v", err)
+	}
+	if result.Affected != 2 {
+		t.Errorf("affected %d items, want 2", result.Affected)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func uintPointer(value uint) *uint {
+	return &value
+}
+
+func TestSuggestQueryLooksUpSearchWords(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewItemRepository(db)
+
+	lookup := regexp.QuoteMeta(`SELECT "word" FROM "search_words" WHERE word % $1 ORDER BY similarity(word, $2) DESC, word ASC LIMIT 1`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config($1, $2, true)")).
+		WithArgs("pg_trgm.similarity_threshold", "0.3").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(lookup).
+		WithArgs("chiken", "chiken").
+		WillReturnRows(sqlmock.NewRows([]string{"word"}).AddRow("chicken"))
+	mock.ExpectQuery(lookup).
+		WithArgs("zzz", "zzz").
+		WillReturnRows(sqlmock.NewRows([]string{"word"}))
+	mock.ExpectCommit()
+
+	suggestion, err := repo.SuggestQuery(context.Background(), "Chiken zzz", 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggestion != "chicken zzz" {
+		t.Errorf("suggestion = %q, want %q", suggestion, "chicken zzz")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSuggestOrdersNamesStartingWithPrefixFirst(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewItemRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT items.id, items.name AS text, items.slug FROM "items" `+
+		`WHERE items.available = $1 AND ((LOWER(items.name) LIKE $2 OR LOWER(items.name) LIKE $3)) AND "items"."deleted_at" IS NULL `+
+		`ORDER BY LOWER(items.name) LIKE $4 DESC, LENGTH(items.name) ASC, items.name ASC LIMIT 5`)).
+		WithArgs(true, "sou%", "% sou%", "sou%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "text", "slug"}).AddRow(7, "Soup", "soup"))
+
+	suggestions, err := repo.Suggest(context.Background(), "Sou", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Text != "Soup" {
+		t.Errorf("suggestions = %+v, want Soup", suggestions)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
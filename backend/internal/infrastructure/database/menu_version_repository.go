@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+// menuVersionSQL takes the latest write to the menu tables, counting soft
+// deletes as writes, and their row count, which drops on hard deletes.
+const menuVersionSQL = `
+SELECT GREATEST(c.modified, s.modified, i.modified, p.modified) AS last_modified,
+	c.row_count + s.row_count + i.row_count + p.row_count AS row_count
+FROM (SELECT MAX(GREATEST(updated_at, deleted_at)) AS modified, COUNT(*) AS row_count FROM categories) c,
+	(SELECT MAX(GREATEST(updated_at, deleted_at)) AS modified, COUNT(*) AS row_count FROM sub_categories) s,
+	(SELECT MAX(GREATEST(updated_at, deleted_at)) AS modified, COUNT(*) AS row_count FROM items) i,
+	(SELECT MAX(updated_at) AS modified, COUNT(*) AS row_count FROM item_placements) p`
+
+type menuVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewMenuVersionRepository(db *gorm.DB) repositories.MenuVersionRepository {
+	return &menuVersionRepository{db: db}
+}
+
+func (r *menuVersionRepository) GetVersion(ctx context.Context) (*entities.MenuVersion, error) {
+	var row struct {
+		LastModified *time.Time
+		RowCount     int64
+	}
+	if err := r.db.WithContext(ctx).Raw(menuVersionSQL).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+
+	version := &entities.MenuVersion{Rows: row.RowCount}
+	if row.LastModified != nil {
+		version.LastModified = row.LastModified.UTC()
+	}
+	return version, nil
+}
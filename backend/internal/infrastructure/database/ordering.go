@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+// reorderRows assigns display_order 1..n following ids, after checking that
+// ids lists every row selected by scope exactly once.
+func reorderRows(ctx context.Context, db *gorm.DB, model interface{}, scope func(*gorm.DB) *gorm.DB, ids []uint) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []uint
+		if err := scope(tx.Model(model)).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Pluck("id", &current).Error; err != nil {
+			return err
+		}
+
+		if !sameIDSet(current, ids) {
+			return repositories.ErrOrderMismatch
+		}
+
+		for i, id := range ids {
+			if err := tx.Model(model).
+				Where("id = ?", id).
+				Update("display_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// compactDisplayOrder rewrites display_order as consecutive positions starting
+// at 1 within each parent, keeping the order rows are currently listed in.
+func compactDisplayOrder(ctx context.Context, db *gorm.DB, table, parentColumn string) (int64, error) {
+	partition := ""
+	if parentColumn != "" {
+		partition = "PARTITION BY " + parentColumn + " "
+	}
+
+	sql := fmt.Sprintf(`UPDATE %[1]s SET display_order = ranked.position
+FROM (
+	SELECT id, ROW_NUMBER() OVER (%[2]sORDER BY display_order ASC, created_at DESC, id ASC) AS position
+	FROM %[1]s
+	WHERE deleted_at IS NULL
+) AS ranked
+WHERE %[1]s.id = ranked.id AND %[1]s.display_order <> ranked.position`, table, partition)
+
+	result := db.WithContext(ctx).Exec(sql)
+	return result.RowsAffected, result.Error
+}
+
+func sameIDSet(current, ids []uint) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+
+	remaining := make(map[uint]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+
+	return true
+}
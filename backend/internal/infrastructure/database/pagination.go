@@ -0,0 +1,60 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+const (
+	defaultCursorLimit = 10
+	maxCursorLimit     = 100
+)
+
+// offsetPagination describes the page at offset, treating a zero limit as a
+// single page.
+func offsetPagination(offset, limit int, total int64) *entities.Pagination {
+	page := 1
+	if limit > 0 {
+		page = (offset / limit) + 1
+	}
+	return entities.NewPagination(page, limit, total)
+}
+
+func cursorLimit(limit int) int {
+	if limit < 1 {
+		return defaultCursorLimit
+	}
+	if limit > maxCursorLimit {
+		return maxCursorLimit
+	}
+	return limit
+}
+
+// applyCursor orders query by (display_order, id) and starts it after the
+// cursor. One row more than limit is fetched to tell whether a next page
+// exists; see cursorPagination.
+func applyCursor(query *gorm.DB, table string, cursor *entities.Cursor, limit int) *gorm.DB {
+	if cursor.ID > 0 {
+		query = query.Where(fmt.Sprintf("(%[1]s.display_order, %[1]s.id) > (?, ?)", table), cursor.DisplayOrder, cursor.ID)
+	}
+	return query.
+		Order(fmt.Sprintf("%[1]s.display_order ASC, %[1]s.id ASC", table)).
+		Limit(limit + 1)
+}
+
+// cursorPagination describes a cursor page of fetched rows, where last is the
+// cursor of the last row kept on the page.
+func cursorPagination(limit, fetched int, total int64, last entities.Cursor) *entities.Pagination {
+	pagination := &entities.Pagination{
+		Limit:   limit,
+		Total:   total,
+		HasNext: fetched > limit,
+	}
+	if pagination.HasNext {
+		pagination.NextCursor = last.Encode()
+	}
+	return pagination
+}
@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+// placedInSubCategory limits an items query to items listed in subCategoryID.
+func placedInSubCategory(query *gorm.DB, subCategoryID uint) *gorm.DB {
+	return query.Where("items.id IN (?)",
+		query.Session(&gorm.Session{NewDB: true}).
+			Model(&entities.ItemPlacement{}).
+			Select("item_id").
+			Where("sub_category_id = ?", subCategoryID))
+}
+
+// placedInCategory limits an items query to items listed in any active
+// subcategory of categoryID. Items placed twice in the category appear once.
+func placedInCategory(query *gorm.DB, categoryID uint) *gorm.DB {
+	return query.Where("items.id IN (?)",
+		query.Session(&gorm.Session{NewDB: true}).
+			Table("item_placements").
+			Select("item_placements.item_id").
+			Joins("JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id").
+			Where("sub_categories.category_id = ? AND sub_categories.deleted_at IS NULL", categoryID))
+}
+
+// loadPlacedItems sets the Items of each subcategory to the items placed in
+// it, in their position there. scopes narrow the items, which the query joins
+// as items. An item placed in several of the subcategories is loaded once and
+// copied into each list.
+func loadPlacedItems(db *gorm.DB, subCategories []*entities.SubCategory, scopes ...func(*gorm.DB) *gorm.DB) error {
+	if len(subCategories) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(subCategories))
+	for i, subCategory := range subCategories {
+		ids[i] = subCategory.ID
+	}
+
+	var placements []entities.ItemPlacement
+	if err := db.Model(&entities.ItemPlacement{}).
+		Select("item_placements.item_id, item_placements.sub_category_id").
+		Joins("JOIN items ON items.id = item_placements.item_id AND items.deleted_at IS NULL").
+		Where("item_placements.sub_category_id IN ?", ids).
+		Scopes(scopes...).
+		Order("item_placements.display_order ASC, item_placements.item_id ASC").
+		Find(&placements).Error; err != nil {
+		return err
+	}
+
+	itemIDs := make([]uint, 0, len(placements))
+	seen := make(map[uint]bool, len(placements))
+	for _, placement := range placements {
+		if !seen[placement.ItemID] {
+			seen[placement.ItemID] = true
+			itemIDs = append(itemIDs, placement.ItemID)
+		}
+	}
+
+	byID := make(map[uint]*entities.Item, len(itemIDs))
+	if len(itemIDs) > 0 {
+		var items []*entities.Item
+		if err := db.Where("id IN ?", itemIDs).Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+	}
+
+	grouped := make(map[uint][]entities.Item, len(subCategories))
+	for _, placement := range placements {
+		if item, ok := byID[placement.ItemID]; ok {
+			grouped[placement.SubCategoryID] = append(grouped[placement.SubCategoryID], *item)
+		}
+	}
+
+	for _, subCategory := range subCategories {
+		subCategory.Items = grouped[subCategory.ID]
+	}
+	return nil
+}
+
+// availableItems limits loadPlacedItems to available items.
+func availableItems(db *gorm.DB) *gorm.DB {
+	return db.Where("items.available = ?", true)
+}
+
+// placedInAnySubCategory limits an items query to items listed in any of
+// subCategoryIDs.
+func placedInAnySubCategory(query *gorm.DB, subCategoryIDs []uint) *gorm.DB {
+	return query.Where("EXISTS (?)",
+		query.Session(&gorm.Session{NewDB: true}).
+			Table("item_placements").
+			Select("1").
+			Where("item_placements.item_id = items.id AND item_placements.sub_category_id IN ?", subCategoryIDs))
+}
+
+// placedInAnyCategory limits an items query to items listed in a subcategory
+// of any of categoryIDs.
+func placedInAnyCategory(query *gorm.DB, categoryIDs []uint) *gorm.DB {
+	return query.Where("EXISTS (?)",
+		query.Session(&gorm.Session{NewDB: true}).
+			Table("item_placements").
+			Select("1").
+			Joins("JOIN sub_categories ON sub_categories.id = item_placements.sub_category_id").
+			Where("item_placements.item_id = items.id AND sub_categories.category_id IN ? AND sub_categories.deleted_at IS NULL", categoryIDs))
+}
+
+// moveHomePlacement follows an item's primary subcategory change, keeping any
+// placement it already had in the new subcategory.
+func moveHomePlacement(tx *gorm.DB, itemID, from, to uint, displayOrder int) error {
+	if err := tx.Where("item_id = ? AND sub_category_id = ?", itemID, from).
+		Delete(&entities.ItemPlacement{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.ItemPlacement{
+		ItemID:        itemID,
+		SubCategoryID: to,
+		DisplayOrder:  displayOrder,
+	}).Error
+}
+
+// placeItemsLast places the items at the end of subCategoryID, in ID order.
+// Items already placed there keep their position.
+func placeItemsLast(tx *gorm.DB, itemIDs []uint, subCategoryID uint) error {
+	displayOrder, err := nextDisplayOrder(tx, &entities.ItemPlacement{}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("sub_category_id = ?", subCategoryID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`INSERT INTO item_placements (item_id, sub_category_id, display_order, created_at, updated_at)
+SELECT id, ?, ? + ROW_NUMBER() OVER (ORDER BY id) - 1, NOW(), NOW()
+FROM items
+WHERE id IN ?
+ON CONFLICT (item_id, sub_category_id) DO NOTHING`, subCategoryID, displayOrder, itemIDs).Error
+}
+
+// copyPlacements lists the items placed in one subcategory, other than those
+// whose primary subcategory it is, in another.
+func copyPlacements(tx *gorm.DB, fromSubCategoryID, toSubCategoryID uint) error {
+	return tx.Exec(`INSERT INTO item_placements (item_id, sub_category_id, display_order, created_at, updated_at)
+SELECT item_placements.item_id, ?, item_placements.display_order, NOW(), NOW()
+FROM item_placements
+JOIN items ON items.id = item_placements.item_id
+WHERE item_placements.sub_category_id = ? AND items.sub_category_id <> ? AND items.deleted_at IS NULL
+ON CONFLICT (item_id, sub_category_id) DO NOTHING`, toSubCategoryID, fromSubCategoryID, fromSubCategoryID).Error
+}
+
+// reorderPlacements assigns placement positions 1..n within subCategoryID
+// following itemIDs. Items whose primary subcategory it is also get their
+// display_order updated.
+func reorderPlacements(ctx context.Context, db *gorm.DB, subCategoryID uint, itemIDs []uint) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []uint
+		if err := tx.Model(&entities.ItemPlacement{}).
+			Joins("JOIN items ON items.id = item_placements.item_id AND items.deleted_at IS NULL").
+			Where("item_placements.sub_category_id = ?", subCategoryID).
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "item_placements"}}).
+			Pluck("item_placements.item_id", &current).Error; err != nil {
+			return err
+		}
+
+		if !sameIDSet(current, itemIDs) {
+			return repositories.ErrOrderMismatch
+		}
+
+		for i, itemID := range itemIDs {
+			if err := tx.Model(&entities.ItemPlacement{}).
+				Where("item_id = ? AND sub_category_id = ?", itemID, subCategoryID).
+				Update("display_order", i+1).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&entities.Item{}).
+				Where("id = ? AND sub_category_id = ?", itemID, subCategoryID).
+				Update("display_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// compactPlacementOrder is compactDisplayOrder for item_placements, which
+// has no soft deletes of its own and skips placements of deleted items.
+func compactPlacementOrder(ctx context.Context, db *gorm.DB) (int64, error) {
+	result := db.WithContext(ctx).Exec(`UPDATE item_placements SET display_order = ranked.position
+FROM (
+	SELECT item_placements.id, ROW_NUMBER() OVER (PARTITION BY item_placements.sub_category_id ORDER BY item_placements.display_order ASC, items.created_at DESC, item_placements.id ASC) AS position
+	FROM item_placements
+	JOIN items ON items.id = item_placements.item_id
+	WHERE items.deleted_at IS NULL
+) AS ranked
+WHERE item_placements.id = ranked.id AND item_placements.display_order <> ranked.position`)
+	return result.RowsAffected, result.Error
+}
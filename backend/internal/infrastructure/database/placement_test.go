@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+func TestLoadPlacedItemsSharesItemsPlacedTwice(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	// Item 7 has subcategory 1 as its primary one and is also placed first in
+	// subcategory 2, ahead of item 8
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT item_placements.item_id, item_placements.sub_category_id FROM "item_placements" JOIN items ON items.id = item_placements.item_id AND items.deleted_at IS NULL WHERE item_placements.sub_category_id IN ($1,$2,$3) AND items.available = $4 ORDER BY item_placements.display_order ASC, item_placements.item_id ASC`)).
+		WithArgs(1, 2, 3, true).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "sub_category_id"}).
+			AddRow(7, 1).
+			AddRow(7, 2).
+			AddRow(8, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "items" WHERE id IN ($1,$2) AND "items"."deleted_at" IS NULL`)).
+		WithArgs(7, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "sub_category_id"}).
+			AddRow(7, "Soup", 1).
+			AddRow(8, "Salad", 2))
+
+	subCategories := []*entities.SubCategory{{ID: 1}, {ID: 2}, {ID: 3}}
+	if err := loadPlacedItems(db, subCategories, availableItems); err != nil {
+		t.Fatalf("loadPlacedItems: %v", err)
+	}
+
+	want := map[uint][]uint{1: {7}, 2: {7, 8}, 3: nil}
+	for _, subCategory := range subCategories {
+		var got []uint
+		for _, item := range subCategory.Items {
+			got = append(got, item.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want[subCategory.ID]) {
+			t.Errorf("subcategory %d has items %v, want %v", subCategory.ID, got, want[subCategory.ID])
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
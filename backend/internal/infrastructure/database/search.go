@@ -0,0 +1,280 @@
+package database
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/pkg/utils"
+)
+
+// maxSearchTerms caps how many words of a query take part in matching.
+const maxSearchTerms = 10
+
+// Sentinels ts_headline wraps matches in. The text is HTML-escaped before they
+// are swapped for <mark> tags, so item content can't inject markup.
+const (
+	highlightStart = "[[[hl]]]"
+	highlightStop  = "[[[/hl]]]"
+)
+
+var searchTermRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// Every word of the name is kept; descriptions are cut to the best fragments
+var (
+	nameHeadlineOptions        = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+	descriptionHeadlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`
+)
+
+// prefixTSQuery turns free text into a to_tsquery expression matching items
+// that contain every word, each as a prefix so partial input matches. The
+// dictionary's stop words are dropped and words or phrases with synonyms match
+// any of them. Only letters and digits survive, so the result never contains
+// tsquery operators of its own. It returns "" when the text has no words.
+func prefixTSQuery(text string, dictionary *entities.SearchDictionary) string {
+	words := searchTermRegex.FindAllString(strings.ToLower(text), maxSearchTerms)
+	if dictionary == nil {
+		return joinPrefixTerms(words, nil)
+	}
+
+	// A query made only of stop words is searched as it is
+	kept := words[:0:0]
+	for _, word := range words {
+		if !utils.Contains(dictionary.StopWords, word) {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) > 0 {
+		words = kept
+	}
+
+	return joinPrefixTerms(words, dictionary.Synonyms)
+}
+
+// joinPrefixTerms ANDs the words together, replacing the longest run of words
+// found in a synonym group with an alternation of the whole group.
+func joinPrefixTerms(words []string, synonyms [][]string) string {
+	groups := make([][][]string, len(synonyms))
+	for i, group := range synonyms {
+		for _, term := range group {
+			if termWords := searchTermRegex.FindAllString(strings.ToLower(term), -1); len(termWords) > 0 {
+				groups[i] = append(groups[i], termWords)
+			}
+		}
+	}
+
+	var terms []string
+	for i := 0; i < len(words); {
+		group, length := longestSynonymMatch(words[i:], groups)
+		if group == nil {
+			terms = append(terms, words[i]+":*")
+			i++
+			continue
+		}
+
+		alternatives := make([]string, len(group))
+		for j, termWords := range group {
+			alternatives[j] = "(" + strings.Join(termWords, " <-> ") + ")"
+		}
+		terms = append(terms, "("+strings.Join(alternatives, " | ")+")")
+		i += length
+	}
+	return strings.Join(terms, " & ")
+}
+
+// longestSynonymMatch finds the synonym group with the longest term that
+// words starts with.
+func longestSynonymMatch(words []string, groups [][][]string) ([][]string, int) {
+	var match [][]string
+	length := 0
+	for _, group := range groups {
+		for _, termWords := range group {
+			if len(termWords) <= length || len(termWords) > len(words) {
+				continue
+			}
+			if strings.Join(words[:len(termWords)], " ") == strings.Join(termWords, " ") {
+				match, length = group, len(termWords)
+			}
+		}
+	}
+	return match, length
+}
+
+// renderHighlight escapes a ts_headline result and turns its sentinels into
+// <mark> tags.
+func renderHighlight(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
+
+// normalizeSearchText lowercases text and reduces it to its words.
+func normalizeSearchText(text string) string {
+	return strings.Join(searchTermRegex.FindAllString(strings.ToLower(text), maxSearchTerms), " ")
+}
+
+// setSimilarityThreshold sets a pg_trgm threshold for the rest of tx.
+func setSimilarityThreshold(tx *gorm.DB, setting string, threshold float64) error {
+	return tx.Exec("SELECT set_config(?, ?, true)", setting, strconv.FormatFloat(threshold, 'f', -1, 64)).Error
+}
+
+func emptySearchResult(filter entities.ItemFilter) *entities.ItemSearchResult {
+	result := &entities.ItemSearchResult{
+		Items:      []*entities.Item{},
+		Highlights: []entities.SearchHighlight{},
+	}
+	if filter.IncludeCount {
+		result.Pagination = offsetPagination(filter.Offset, filter.Limit, 0)
+	}
+	if filter.IncludeFacets {
+		result.Facets = emptySearchFacets()
+	}
+	return result
+}
+
+// applySearchFilters narrows a search query by the filter's placement,
+// availability and price.
+func applySearchFilters(query *gorm.DB, filter entities.ItemFilter) *gorm.DB {
+	if filter.SubCategoryID != nil {
+		query = placedInSubCategory(query, *filter.SubCategoryID)
+	}
+
+	if filter.CategoryID != nil {
+		query = placedInCategory(query, *filter.CategoryID)
+	}
+
+	if filter.Available != nil {
+		query = query.Where("items.available = ?", *filter.Available)
+	}
+
+	if filter.MinPrice != nil {
+		query = query.Where("items.price >= ?", *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		query = query.Where("items.price <= ?", *filter.MaxPrice)
+	}
+
+	return query
+}
+
+// searchPage counts the items matched by query and the facet selections and
+// loads the requested page ordered by rank, highest first. Highlights hold the
+// escaped item names.
+func searchPage(matches *gorm.DB, filter entities.ItemFilter, rank clause.Expr) (*entities.ItemSearchResult, error) {
+	result := emptySearchResult(filter)
+
+	if filter.IncludeFacets {
+		facets, err := searchFacets(matches, filter)
+		if err != nil {
+			return nil, err
+		}
+		result.Facets = facets
+	}
+
+	query := applyFacetSelections(matches.Session(&gorm.Session{}), filter)
+
+	if filter.IncludeCount {
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, err
+		}
+		result.Pagination = offsetPagination(filter.Offset, filter.Limit, total)
+	}
+
+	pageQuery := query.Session(&gorm.Session{}).
+		Select("items.id, ? AS rank", rank).
+		Order("rank DESC, items.display_order ASC, items.id ASC")
+	if filter.Limit > 0 {
+		pageQuery = pageQuery.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		pageQuery = pageQuery.Offset(filter.Offset)
+	}
+
+	var ranked []struct {
+		ID   uint
+		Rank float64
+	}
+	if err := pageQuery.Scan(&ranked).Error; err != nil {
+		return nil, err
+	}
+
+	if len(ranked) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, len(ranked))
+	for i, row := range ranked {
+		ids[i] = row.ID
+	}
+
+	var items []*entities.Item
+	if err := query.Session(&gorm.Session{NewDB: true}).
+		Preload("SubCategory").
+		Preload("SubCategory.Category").
+		Where("id IN ?", ids).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*entities.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	for _, row := range ranked {
+		item, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		result.Items = append(result.Items, item)
+		result.Highlights = append(result.Highlights, entities.SearchHighlight{
+			ItemID: row.ID,
+			Rank:   row.Rank,
+			Name:   html.EscapeString(item.Name),
+		})
+	}
+
+	return result, nil
+}
+
+func searchResultIDs(result *entities.ItemSearchResult) []uint {
+	ids := make([]uint, len(result.Items))
+	for i, item := range result.Items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// namePrefixPatterns returns LIKE patterns matching lowercase names that start
+// with prefix or have a word that does.
+func namePrefixPatterns(prefix string) (startsWith, wordStartsWith string) {
+	escaped := likeEscaper.Replace(strings.ToLower(prefix))
+	return escaped + "%", "% " + escaped + "%"
+}
+
+// suggestNames looks up the names in table matching prefix, names
+// starting with it first, then shorter names.
+func suggestNames(query *gorm.DB, table, prefix string, limit int) ([]entities.Suggestion, error) {
+	startsWith, wordStartsWith := namePrefixPatterns(prefix)
+
+	var suggestions []entities.Suggestion
+	err := query.
+		Select(table+".id, "+table+".name AS text, "+table+".slug").
+		Where("(LOWER("+table+".name) LIKE ? OR LOWER("+table+".name) LIKE ?)", startsWith, wordStartsWith).
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "LOWER(" + table + ".name) LIKE ? DESC, LENGTH(" + table + ".name) ASC, " + table + ".name ASC",
+			Vars: []interface{}{startsWith},
+		}}).
+		Limit(limit).
+		Scan(&suggestions).Error
+	return suggestions, err
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+type searchDictionaryRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchDictionaryRepository(db *gorm.DB) repositories.SearchDictionaryRepository {
+	return &searchDictionaryRepository{db: db}
+}
+
+func (r *searchDictionaryRepository) GetDictionary(ctx context.Context, locale string) (*entities.SearchDictionary, error) {
+	synonyms, err := r.GetSynonyms(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	stopWords, err := r.GetStopWords(ctx, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionary := &entities.SearchDictionary{
+		Locale:    locale,
+		Synonyms:  make([][]string, len(synonyms)),
+		StopWords: make([]string, len(stopWords)),
+	}
+	for i, synonym := range synonyms {
+		dictionary.Synonyms[i] = synonym.Terms
+	}
+	for i, stopWord := range stopWords {
+		dictionary.StopWords[i] = stopWord.Word
+	}
+
+	return dictionary, nil
+}
+
+// GetSynonyms lists the synonym groups of locale, or of every locale when
+// locale is empty.
+func (r *searchDictionaryRepository) GetSynonyms(ctx context.Context, locale string) ([]*entities.SearchSynonym, error) {
+	var synonyms []*entities.SearchSynonym
+	query := r.db.WithContext(ctx).Order("locale ASC, id ASC")
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+
+	if err := query.Find(&synonyms).Error; err != nil {
+		return nil, err
+	}
+	return synonyms, nil
+}
+
+func (r *searchDictionaryRepository) GetSynonymByID(ctx context.Context, id uint) (*entities.SearchSynonym, error) {
+	var synonym entities.SearchSynonym
+	err := r.db.WithContext(ctx).First(&synonym, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &synonym, nil
+}
+
+func (r *searchDictionaryRepository) CreateSynonym(ctx context.Context, synonym *entities.SearchSynonym) error {
+	return r.db.WithContext(ctx).Create(synonym).Error
+}
+
+func (r *searchDictionaryRepository) UpdateSynonym(ctx context.Context, synonym *entities.SearchSynonym) error {
+	return r.db.WithContext(ctx).Save(synonym).Error
+}
+
+func (r *searchDictionaryRepository) DeleteSynonym(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entities.SearchSynonym{}, id).Error
+}
+
+// GetStopWords lists the stop words of locale, or of every locale when locale
+// is empty.
+func (r *searchDictionaryRepository) GetStopWords(ctx context.Context, locale string) ([]*entities.SearchStopWord, error) {
+	var stopWords []*entities.SearchStopWord
+	query := r.db.WithContext(ctx).Order("locale ASC, word ASC")
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+
+	if err := query.Find(&stopWords).Error; err != nil {
+		return nil, err
+	}
+	return stopWords, nil
+}
+
+func (r *searchDictionaryRepository) GetStopWordByID(ctx context.Context, id uint) (*entities.SearchStopWord, error) {
+	var stopWord entities.SearchStopWord
+	err := r.db.WithContext(ctx).First(&stopWord, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stopWord, nil
+}
+
+// CreateStopWord adds a stop word, returning false when the locale already
+// has it.
+func (r *searchDictionaryRepository) CreateStopWord(ctx context.Context, stopWord *entities.SearchStopWord) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(stopWord)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *searchDictionaryRepository) DeleteStopWord(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entities.SearchStopWord{}, id).Error
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+type searchQueryRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchQueryRepository(db *gorm.DB) repositories.SearchQueryRepository {
+	return &searchQueryRepository{db: db}
+}
+
+// Record logs a search and counts it towards its query's popularity.
+func (r *searchQueryRepository) Record(ctx context.Context, event *entities.SearchEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+
+		now := event.CreatedAt
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "query"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"search_count":      gorm.Expr("search_queries.search_count + 1"),
+				"last_result_count": event.ResultCount,
+				"last_searched_at":  now,
+				"updated_at":        now,
+			}),
+		}).Create(&entities.SearchQuery{
+			Query:           event.Query,
+			SearchCount:     1,
+			LastResultCount: event.ResultCount,
+			LastSearchedAt:  &now,
+		}).Error
+	})
+}
+
+// RecordClick logs a result being opened, returning false when the search
+// doesn't exist.
+func (r *searchQueryRepository) RecordClick(ctx context.Context, click *entities.SearchClick) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.SearchEvent{}).
+		Where("id = ?", click.SearchEventID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	return true, r.db.WithContext(ctx).Create(click).Error
+}
+
+// Suggest returns the most searched queries starting with prefix.
+func (r *searchQueryRepository) Suggest(ctx context.Context, prefix string, limit int) ([]entities.Suggestion, error) {
+	startsWith, _ := namePrefixPatterns(prefix)
+
+	var suggestions []entities.Suggestion
+	err := r.db.WithContext(ctx).Model(&entities.SearchQuery{}).
+		Select("query AS text").
+		Where("query LIKE ? AND last_result_count > 0", startsWith).
+		Order("search_count DESC, query ASC").
+		Limit(limit).
+		Scan(&suggestions).Error
+	return suggestions, err
+}
+
+func (r *searchQueryRepository) TopQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error) {
+	return r.queryReport(ctx, filter, false)
+}
+
+func (r *searchQueryRepository) ZeroResultQueries(ctx context.Context, filter entities.SearchReportFilter) ([]entities.SearchQueryReport, error) {
+	return r.queryReport(ctx, filter, true)
+}
+
+// queryReport groups the searches in the filter's period by query, most
+// searched first.
+func (r *searchQueryRepository) queryReport(ctx context.Context, filter entities.SearchReportFilter, zeroResultsOnly bool) ([]entities.SearchQueryReport, error) {
+	query := r.db.WithContext(ctx).Table("search_events").
+		Select(`search_events.query,
+			COUNT(*) AS searches,
+			COUNT(*) FILTER (WHERE search_events.result_count = 0) AS zero_result_searches,
+			AVG(search_events.result_count) AS avg_results,
+			COALESCE(SUM(clicks.clicks), 0) AS clicks,
+			COUNT(clicks.search_event_id)::float / COUNT(*) AS click_through_rate,
+			MAX(search_events.created_at) AS last_searched_at`).
+		Joins(`LEFT JOIN (
+			SELECT search_event_id, COUNT(*) AS clicks FROM search_clicks GROUP BY search_event_id
+		) AS clicks ON clicks.search_event_id = search_events.id`).
+		Where("search_events.created_at >= ? AND search_events.created_at < ?", filter.From, filter.To).
+		Group("search_events.query").
+		Order("searches DESC, search_events.query ASC").
+		Limit(filter.Limit)
+
+	if zeroResultsOnly {
+		query = query.Where("search_events.result_count = 0")
+	}
+
+	reports := []entities.SearchQueryReport{}
+	if err := query.Scan(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (r *searchQueryRepository) ClickThrough(ctx context.Context, filter entities.SearchReportFilter) (*entities.ClickThroughReport, error) {
+	report := &entities.ClickThroughReport{Items: []entities.ItemClickReport{}}
+
+	err := r.db.WithContext(ctx).Table("search_events").
+		Select(`COUNT(*) AS searches,
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM search_clicks WHERE search_clicks.search_event_id = search_events.id
+			)) AS searches_with_clicks`).
+		Where("search_events.created_at >= ? AND search_events.created_at < ?", filter.From, filter.To).
+		Scan(report).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Searches > 0 {
+		report.ClickThroughRate = float64(report.SearchesWithClicks) / float64(report.Searches)
+	}
+
+	err = r.db.WithContext(ctx).Table("search_clicks").
+		Select(`search_clicks.item_id, items.name AS item_name,
+			COUNT(*) AS clicks,
+			COUNT(DISTINCT search_clicks.search_event_id) AS searches,
+			AVG(search_clicks.position) AS avg_position`).
+		Joins("JOIN search_events ON search_events.id = search_clicks.search_event_id").
+		Joins("JOIN items ON items.id = search_clicks.item_id").
+		Where("search_events.created_at >= ? AND search_events.created_at < ?", filter.From, filter.To).
+		Group("search_clicks.item_id, items.name").
+		Order("clicks DESC, items.name ASC").
+		Limit(filter.Limit).
+		Scan(&report.Items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/repositories"
+)
+
+type slugRedirectRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugRedirectRepository(db *gorm.DB) repositories.SlugRedirectRepository {
+	return &slugRedirectRepository{db: db}
+}
+
+func (r *slugRedirectRepository) GetRedirect(ctx context.Context, entityType entities.SlugEntityType, scopeID uint, slug string) (*entities.SlugRedirect, error) {
+	var redirect entities.SlugRedirect
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND scope_id = ? AND slug = ?", entityType, scopeID, slug).
+		First(&redirect).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
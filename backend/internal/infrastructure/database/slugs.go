@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/pkg/utils"
+)
+
+// regenerateFallbackSlugs re-derives the slugs migration 000004 could only
+// number, such as item-12, for rows whose name utils.GenerateSlug can spell.
+// The migration's backfill only knew ASCII letters, so names in other scripts
+// got numbered slugs while new rows get transliterated ones. Saving a row with
+// an empty slug lets its BeforeUpdate hook derive the slug from the name and
+// keep the numbered one as a redirect.
+func regenerateFallbackSlugs[T any](ctx context.Context, db *gorm.DB, fallback string, name func(*T) string, clearSlug func(*T)) (int64, error) {
+	var rows []*T
+	if err := db.WithContext(ctx).
+		Where("slug = CONCAT(CAST(? AS TEXT), id)", fallback+"-").
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	for _, row := range rows {
+		if utils.GenerateSlug(name(row)) == "" {
+			continue
+		}
+
+		clearSlug(row)
+		if err := db.WithContext(ctx).Save(row).Error; err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+// applySort orders query by the columns of table named in sort, or by
+// fallback when sort is empty. Column names are quoted as identifiers, and
+// ties are broken by id so pages stay stable.
+func applySort(query *gorm.DB, table string, sort entities.Sort, fallback string) *gorm.DB {
+	if len(sort) == 0 {
+		return query.Order(fallback)
+	}
+
+	columns := make([]clause.OrderByColumn, 0, len(sort)+1)
+	for _, field := range sort {
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: table, Name: field.Field},
+			Desc:   field.Desc,
+		})
+	}
+	columns = append(columns, clause.OrderByColumn{Column: clause.Column{Table: table, Name: "id"}})
+
+	return query.Clauses(clause.OrderBy{Columns: columns})
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+func TestApplySort(t *testing.T) {
+	db, _ := newMockDB(t)
+
+	tests := []struct {
+		name string
+		sort entities.Sort
+		want string
+	}{
+		{"fallback", nil, `SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL ORDER BY display_order ASC, created_at DESC`},
+		{"fields", entities.Sort{{Field: "name"}, {Field: "created_at", Desc: true}},
+			`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL ORDER BY "categories"."name","categories"."created_at" DESC,"categories"."id"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var categories []*entities.Category
+				return applySort(tx.Model(&entities.Category{}), "categories", tt.sort, "display_order ASC, created_at DESC").Find(&categories)
+			})
+			if sql != tt.want {
+				t.Errorf("sql = %s\nwant  %s", sql, tt.want)
+			}
+		})
+	}
+}
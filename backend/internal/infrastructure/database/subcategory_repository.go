@@ -3,9 +3,11 @@ package database
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/repositories"
@@ -47,17 +49,35 @@ func (r *subCategoryRepository) GetBySlug(ctx context.Context, slug string) (*en
 	return &subcategory, nil
 }
 
+func (r *subCategoryRepository) GetBySlugInCategory(ctx context.Context, categoryID uint, slug string) (*entities.SubCategory, error) {
+	var subcategory entities.SubCategory
+	err := r.db.WithContext(ctx).
+		Preload("Category").
+		Where("category_id = ? AND slug = ?", categoryID, slug).
+		Order("depth ASC, id ASC").
+		First(&subcategory).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subcategory, nil
+}
+
 func (r *subCategoryRepository) GetAll(ctx context.Context, filter entities.SubCategoryFilter) ([]*entities.SubCategory, *entities.Pagination, error) {
 	var subcategories []*entities.SubCategory
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&entities.SubCategory{}).Preload("Category")
+	query := applyExpand(r.db.WithContext(ctx).Model(&entities.SubCategory{}), filter.Expand, defaultSubCategoryExpand, subCategoryPreloads)
 
 	// Apply filters
 	if filter.CategoryID != nil {
 		query = query.Where("category_id = ?", *filter.CategoryID)
 	}
 
+	query = applySubCategoryParentFilter(query, filter)
+
 	if filter.Active != nil {
 		query = query.Where("active = ?", *filter.Active)
 	}
@@ -67,9 +87,15 @@ func (r *subCategoryRepository) GetAll(ctx context.Context, filter entities.SubC
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
 	}
 
+	if filter.Cursor != nil {
+		return r.getAllAfterCursor(ctx, query, filter)
+	}
+
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, nil, err
+	if filter.IncludeCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Apply pagination
@@ -81,36 +107,70 @@ func (r *subCategoryRepository) GetAll(ctx context.Context, filter entities.SubC
 	}
 
 	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "sub_categories", filter.Sort, "display_order ASC, created_at DESC")
 
 	if err := query.Find(&subcategories).Error; err != nil {
 		return nil, nil, err
 	}
 
+	if err := r.expandItems(ctx, subcategories, filter.Expand); err != nil {
+		return nil, nil, err
+	}
+
 	var pagination *entities.Pagination
 	if filter.IncludeCount {
-		page := (filter.Offset / filter.Limit) + 1
-		if filter.Limit == 0 {
-			page = 1
-		}
-		pagination = entities.NewPagination(page, filter.Limit, total)
+		pagination = offsetPagination(filter.Offset, filter.Limit, total)
 	}
 
 	return subcategories, pagination, nil
 }
 
+// getAllAfterCursor returns the page of query after filter.Cursor.
+func (r *subCategoryRepository) getAllAfterCursor(ctx context.Context, query *gorm.DB, filter entities.SubCategoryFilter) ([]*entities.SubCategory, *entities.Pagination, error) {
+	var total int64
+	if filter.IncludeCount {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var subcategories []*entities.SubCategory
+	limit := cursorLimit(filter.Limit)
+	if err := applyCursor(query, "sub_categories", filter.Cursor, limit).Find(&subcategories).Error; err != nil {
+		return nil, nil, err
+	}
+
+	fetched := len(subcategories)
+	if fetched > limit {
+		subcategories = subcategories[:limit]
+	}
+
+	if err := r.expandItems(ctx, subcategories, filter.Expand); err != nil {
+		return nil, nil, err
+	}
+
+	var last entities.Cursor
+	if len(subcategories) > 0 {
+		tail := subcategories[len(subcategories)-1]
+		last = entities.Cursor{DisplayOrder: tail.DisplayOrder, ID: tail.ID}
+	}
+
+	return subcategories, cursorPagination(limit, fetched, total, last), nil
+}
+
+// expandItems loads the items placed in each subcategory when expand asks
+// for them. Unlike the other relations they can't be preloaded.
+func (r *subCategoryRepository) expandItems(ctx context.Context, subcategories []*entities.SubCategory, expand entities.Expand) error {
+	if !expand.Has("items") {
+		return nil
+	}
+	return loadPlacedItems(r.db.WithContext(ctx), subcategories)
+}
+
 func (r *subCategoryRepository) GetByCategoryID(ctx context.Context, categoryID uint, filter entities.SubCategoryFilter) ([]*entities.SubCategory, error) {
 	var subcategories []*entities.SubCategory
 
-	query := r.db.WithContext(ctx).Where("category_id = ?", categoryID)
+	query := applySubCategoryParentFilter(r.db.WithContext(ctx).Where("category_id = ?", categoryID), filter)
 
 	if filter.Active != nil {
 		query = query.Where("active = ?", *filter.Active)
@@ -122,15 +182,7 @@ func (r *subCategoryRepository) GetByCategoryID(ctx context.Context, categoryID
 	}
 
 	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "sub_categories", filter.Sort, "display_order ASC, created_at DESC")
 
 	if err := query.Find(&subcategories).Error; err != nil {
 		return nil, err
@@ -143,15 +195,28 @@ func (r *subCategoryRepository) Update(ctx context.Context, subcategory *entitie
 	return r.db.WithContext(ctx).Save(subcategory).Error
 }
 
+// Delete removes the subcategory together with everything nested below it.
 func (r *subCategoryRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entities.SubCategory{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var node entities.SubCategory
+		if err := tx.Select("id", "path").First(&node, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if node.Path == "" {
+			return tx.Delete(&entities.SubCategory{}, id).Error
+		}
+		return tx.Where("path LIKE ?", node.Path+"%").Delete(&entities.SubCategory{}).Error
+	})
 }
 
 func (r *subCategoryRepository) GetWithItems(ctx context.Context, id uint) (*entities.SubCategory, error) {
 	var subcategory entities.SubCategory
 	err := r.db.WithContext(ctx).
 		Preload("Category").
-		Preload("Items", "available = ?", true).
 		First(&subcategory, id).Error
 	
 	if err != nil {
@@ -160,6 +225,10 @@ func (r *subCategoryRepository) GetWithItems(ctx context.Context, id uint) (*ent
 		}
 		return nil, err
 	}
+
+	if err := loadPlacedItems(r.db.WithContext(ctx), []*entities.SubCategory{&subcategory}, availableItems); err != nil {
+		return nil, err
+	}
 	return &subcategory, nil
 }
 
@@ -167,14 +236,15 @@ func (r *subCategoryRepository) GetAllWithItems(ctx context.Context, filter enti
 	var subcategories []*entities.SubCategory
 
 	query := r.db.WithContext(ctx).
-		Preload("Category").
-		Preload("Items", "available = ?", true)
+		Preload("Category")
 
 	// Apply filters
 	if filter.CategoryID != nil {
 		query = query.Where("category_id = ?", *filter.CategoryID)
 	}
 
+	query = applySubCategoryParentFilter(query, filter)
+
 	if filter.Active != nil {
 		query = query.Where("active = ?", *filter.Active)
 	}
@@ -185,20 +255,15 @@ func (r *subCategoryRepository) GetAllWithItems(ctx context.Context, filter enti
 	}
 
 	// Apply ordering
-	orderBy := "display_order ASC, created_at DESC"
-	if filter.OrderBy != "" {
-		direction := "ASC"
-		if filter.OrderDir != "" && strings.ToUpper(filter.OrderDir) == "DESC" {
-			direction = "DESC"
-		}
-		orderBy = filter.OrderBy + " " + direction
-	}
-	query = query.Order(orderBy)
+	query = applySort(query, "sub_categories", filter.Sort, "display_order ASC, created_at DESC")
 
 	if err := query.Find(&subcategories).Error; err != nil {
 		return nil, err
 	}
 
+	if err := loadPlacedItems(r.db.WithContext(ctx), subcategories, availableItems); err != nil {
+		return nil, err
+	}
 	return subcategories, nil
 }
 
@@ -210,6 +275,8 @@ func (r *subCategoryRepository) Count(ctx context.Context, filter entities.SubCa
 		query = query.Where("category_id = ?", *filter.CategoryID)
 	}
 
+	query = applySubCategoryParentFilter(query, filter)
+
 	if filter.Active != nil {
 		query = query.Where("active = ?", *filter.Active)
 	}
@@ -234,4 +301,196 @@ func (r *subCategoryRepository) ToggleActive(ctx context.Context, id uint) error
 		Model(&entities.SubCategory{}).
 		Where("id = ?", id).
 		Update("active", gorm.Expr("NOT active")).Error
-}
\ No newline at end of file
+}
+
+func (r *subCategoryRepository) Reorder(ctx context.Context, categoryID uint, parentID *uint, ids []uint) error {
+	return reorderRows(ctx, r.db, &entities.SubCategory{}, siblingScope(categoryID, parentID), ids)
+}
+
+func (r *subCategoryRepository) CompactDisplayOrder(ctx context.Context) (int64, error) {
+	return compactDisplayOrder(ctx, r.db, "sub_categories", "category_id, parent_id")
+}
+
+func (r *subCategoryRepository) RegenerateFallbackSlugs(ctx context.Context) (int64, error) {
+	return regenerateFallbackSlugs(ctx, r.db, "sub-category",
+		func(sc *entities.SubCategory) string { return sc.Name },
+		func(sc *entities.SubCategory) { sc.Slug = "" })
+}
+
+func (r *subCategoryRepository) Clone(ctx context.Context, id uint, opts entities.CloneOptions) (*entities.SubCategory, error) {
+	var clone entities.SubCategory
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source entities.SubCategory
+		if err := tx.First(&source, id).Error; err != nil {
+			return err
+		}
+
+		var nodes []entities.SubCategory
+		if err := orderByTreePosition(tx).
+			Where("path LIKE ?", source.Path+"%").
+			Find(&nodes).Error; err != nil {
+			return err
+		}
+		if err := loadPlacedItems(tx, subCategoryPointers(nodes)); err != nil {
+			return err
+		}
+
+		// Clones stay next to the source unless moved to another category
+		categoryID, parentID := source.CategoryID, source.ParentID
+		if opts.TargetParentID != nil && *opts.TargetParentID != source.CategoryID {
+			categoryID, parentID = *opts.TargetParentID, nil
+		}
+
+		displayOrder, err := nextDisplayOrder(tx, &entities.SubCategory{}, siblingScope(categoryID, parentID))
+		if err != nil {
+			return err
+		}
+
+		clones, err := cloneSubCategoryTree(tx, nodes, categoryID, parentID, func(from, to *entities.SubCategory) {
+			if from.ID == source.ID {
+				to.Name = entities.CloneName(source.Name, opts.Suffix(), 100)
+				to.DisplayOrder = displayOrder
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		clone = *clones[source.ID]
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &clone, nil
+}
+
+func (r *subCategoryRepository) GetSubtree(ctx context.Context, id uint) ([]*entities.SubCategory, error) {
+	var node entities.SubCategory
+	if err := r.db.WithContext(ctx).Select("id", "path").First(&node, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var subcategories []*entities.SubCategory
+	err := orderByTreePosition(r.db.WithContext(ctx)).
+		Where("path LIKE ?", node.Path+"%").
+		Find(&subcategories).Error
+	return subcategories, err
+}
+
+func (r *subCategoryRepository) GetAncestors(ctx context.Context, id uint) ([]*entities.SubCategory, error) {
+	var node entities.SubCategory
+	if err := r.db.WithContext(ctx).Select("id", "path").First(&node, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ancestors []*entities.SubCategory
+	err := r.db.WithContext(ctx).
+		Where("id IN ?", node.AncestorIDs()).
+		Order("depth ASC").
+		Find(&ancestors).Error
+	return ancestors, err
+}
+
+// Move re-parents the subcategory below parentID or, when nil, directly below
+// categoryID, appending it to its new siblings. Nested subcategories follow it
+// to the new category.
+func (r *subCategoryRepository) Move(ctx context.Context, id, categoryID uint, parentID *uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var node entities.SubCategory
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&node, id).Error; err != nil {
+			return err
+		}
+
+		path := fmt.Sprintf("/%d/", node.ID)
+		depth := 0
+		if parentID != nil {
+			var parent entities.SubCategory
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, *parentID).Error; err != nil {
+				return err
+			}
+
+			// A node can't be moved below itself or its own descendants
+			if strings.HasPrefix(parent.Path, node.Path) {
+				return repositories.ErrInvalidMove
+			}
+
+			categoryID = parent.CategoryID
+			path = fmt.Sprintf("%s%d/", parent.Path, node.ID)
+			depth = parent.Depth + 1
+		}
+
+		if node.CategoryID == categoryID && node.Path == path {
+			return nil
+		}
+
+		displayOrder, err := nextDisplayOrder(tx, &entities.SubCategory{}, siblingScope(categoryID, parentID))
+		if err != nil {
+			return err
+		}
+
+		// Rewrite the path prefix and depth of the whole subtree
+		if err := tx.Exec(`UPDATE sub_categories
+SET path = ? || SUBSTRING(path FROM ?), depth = depth + ?
+WHERE path LIKE ?`, path, len(node.Path)+1, depth-node.Depth, node.Path+"%").Error; err != nil {
+			return err
+		}
+
+		// Saving each node lets its slug hook keep slugs unique in the new
+		// category and redirect links that used the old one
+		if categoryID != node.CategoryID {
+			var subtree []entities.SubCategory
+			if err := tx.Where("path LIKE ?", path+"%").Find(&subtree).Error; err != nil {
+				return err
+			}
+
+			for i := range subtree {
+				subtree[i].CategoryID = categoryID
+				if err := tx.Omit(clause.Associations).Save(&subtree[i]).Error; err != nil {
+					return err
+				}
+			}
+
+			// Item search vectors weigh in the name of the item's category,
+			// which the triggers on items don't see change. Facets join
+			// through the subcategories, so they follow on their own.
+			if err := tx.Exec(`UPDATE items
+SET search_vector = item_search_vector(items.name, items.description, items.sub_category_id)
+WHERE items.sub_category_id IN (SELECT id FROM sub_categories WHERE path LIKE ?)`, path+"%").Error; err != nil {
+				return err
+			}
+		}
+
+		parent := gorm.Expr("NULL")
+		if parentID != nil {
+			parent = gorm.Expr("?", *parentID)
+		}
+		return tx.Model(&entities.SubCategory{}).
+			Where("id = ?", node.ID).
+			Updates(map[string]interface{}{
+				"parent_id":     parent,
+				"display_order": displayOrder,
+			}).Error
+	})
+}
+
+func applySubCategoryParentFilter(query *gorm.DB, filter entities.SubCategoryFilter) *gorm.DB {
+	if filter.ParentID != nil {
+		return query.Where("parent_id = ?", *filter.ParentID)
+	}
+	if filter.TopLevel {
+		return query.Where("parent_id IS NULL")
+	}
+	return query
+}
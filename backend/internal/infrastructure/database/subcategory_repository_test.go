@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMoveToAnotherCategoryRefreshesItemSearchVectors(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewSubCategoryRepository(db)
+
+	subCategoryColumns := []string{"id", "name", "slug", "category_id", "parent_id", "path", "depth", "display_order", "active"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "sub_categories" WHERE "sub_categories"."id" = \$1 .* FOR UPDATE`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(subCategoryColumns).AddRow(3, "Soups", "soups", 1, nil, "/3/", 0, 1, true))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(display_order), 0) FROM "sub_categories" WHERE (category_id = $1 AND parent_id IS NULL)`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE sub_categories`)).
+		WithArgs("/3/", 4, 0, "/3/%").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "sub_categories" WHERE path LIKE $1`)).
+		WithArgs("/3/%").
+		WillReturnRows(sqlmock.NewRows(subCategoryColumns).
+			AddRow(3, "Soups", "soups", 1, nil, "/3/", 0, 1, true))
+
+	// Saving the node moves its slug into the new category
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT name, slug, category_id AS scope_id FROM "sub_categories" WHERE id = $1 LIMIT 1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "slug", "scope_id"}).AddRow("Soups", "soups", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "sub_categories" WHERE (slug = $1 OR slug LIKE $2) AND id <> $3 AND category_id = $4`)).
+		WithArgs("soups", "soups-%", 3, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slug_redirects"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "sub_categories" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE items
+SET search_vector = item_search_vector(items.name, items.description, items.sub_category_id)
+WHERE items.sub_category_id IN (SELECT id FROM sub_categories WHERE path LIKE $1)`)).
+		WithArgs("/3/%").
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "sub_categories" SET "display_order"=$1,"parent_id"=NULL`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.Move(context.Background(), 3, 2, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -180,6 +180,35 @@ func (c *Client) SetNX(ctx context.Context, key string, value interface{}, expir
 	return result, nil
 }
 
+// deleteIfEqualsScript deletes KEYS[1] only while it holds ARGV[1], so that
+// a lock is only released by the holder that took it.
+var deleteIfEqualsScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func (c *Client) DeleteIfEquals(ctx context.Context, key string, value interface{}) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.LogError(ctx, err, "Failed to marshal value for Redis DeleteIfEquals", map[string]interface{}{
+			"key": key,
+		})
+		return false, appErrors.WrapInternalError(err, "Failed to serialize data")
+	}
+
+	deleted, err := deleteIfEqualsScript.Run(ctx, c.rdb, []string{key}, data).Int()
+	if err != nil {
+		c.logger.LogError(ctx, err, "Failed to delete key from Redis", map[string]interface{}{
+			"key": key,
+		})
+		return false, appErrors.WrapInternalError(err, "Failed to delete from cache")
+	}
+
+	return deleted == 1, nil
+}
+
 func (c *Client) GetTTL(ctx context.Context, key string) (time.Duration, error) {
 	ttl, err := c.rdb.TTL(ctx, key).Result()
 	if err != nil {
@@ -192,26 +221,46 @@ func (c *Client) GetTTL(ctx context.Context, key string) (time.Duration, error)
 	return ttl, nil
 }
 
+// scanBatchSize is how many keys each SCAN step asks for and each DEL removes
+const scanBatchSize = 500
+
+// FlushPattern deletes the keys matching pattern. It walks the keyspace with
+// SCAN, so Redis keeps serving other clients meanwhile; prefer tags, which
+// don't walk the keyspace at all, for anything written through SetWithTags.
 func (c *Client) FlushPattern(ctx context.Context, pattern string) error {
-	keys, err := c.rdb.Keys(ctx, pattern).Result()
-	if err != nil {
-		c.logger.LogError(ctx, err, "Failed to get keys by pattern from Redis", map[string]interface{}{
-			"pattern": pattern,
-		})
-		return appErrors.WrapInternalError(err, "Failed to find keys by pattern")
-	}
+	iter := c.rdb.Scan(ctx, 0, pattern, scanBatchSize).Iterator()
 
-	if len(keys) > 0 {
-		if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+	batch := make([]string, 0, scanBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := c.rdb.Del(ctx, batch...).Err(); err != nil {
 			c.logger.LogError(ctx, err, "Failed to delete keys by pattern from Redis", map[string]interface{}{
 				"pattern": pattern,
-				"keys":    keys,
 			})
 			return appErrors.WrapInternalError(err, "Failed to delete keys by pattern")
 		}
+		batch = batch[:0]
+		return nil
 	}
 
-	return nil
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == scanBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		c.logger.LogError(ctx, err, "Failed to scan keys by pattern in Redis", map[string]interface{}{
+			"pattern": pattern,
+		})
+		return appErrors.WrapInternalError(err, "Failed to find keys by pattern")
+	}
+
+	return flush()
 }
 
 func (c *Client) Close() error {
@@ -247,8 +296,8 @@ func GenerateCacheKey(prefix string, parts ...string) string {
 }
 
 // Common cache operations
-func (c *Client) CacheMenuData(ctx context.Context, key string, data interface{}, expiration time.Duration) error {
-	return c.Set(ctx, GenerateCacheKey("menu", key), data, expiration)
+func (c *Client) CacheMenuData(ctx context.Context, key string, data interface{}, expiration time.Duration, tags ...string) error {
+	return c.SetWithTags(ctx, GenerateCacheKey("menu", key), data, expiration, append([]string{MenuTag}, tags...)...)
 }
 
 func (c *Client) GetMenuData(ctx context.Context, key string, dest interface{}) error {
@@ -256,17 +305,21 @@ func (c *Client) GetMenuData(ctx context.Context, key string, dest interface{})
 }
 
 func (c *Client) InvalidateMenuCache(ctx context.Context) error {
-	return c.FlushPattern(ctx, "menu:*")
+	return c.InvalidateTags(ctx, MenuTag)
 }
 
-func (c *Client) CacheItemData(ctx context.Context, key string, data interface{}, expiration time.Duration) error {
-	return c.Set(ctx, GenerateCacheKey("item", key), data, expiration)
+func (c *Client) CacheItemData(ctx context.Context, itemID, key string, data interface{}, expiration time.Duration) error {
+	return c.SetWithTags(ctx, GenerateCacheKey("item", itemID, key), data, expiration, ItemTag(itemID))
 }
 
-func (c *Client) GetItemData(ctx context.Context, key string, dest interface{}) error {
-	return c.Get(ctx, GenerateCacheKey("item", key), dest)
+func (c *Client) GetItemData(ctx context.Context, itemID, key string, dest interface{}) error {
+	return c.Get(ctx, GenerateCacheKey("item", itemID, key), dest)
 }
 
 func (c *Client) InvalidateItemCache(ctx context.Context, itemID string) error {
-	return c.FlushPattern(ctx, fmt.Sprintf("item:*%s*", itemID))
-}
\ No newline at end of file
+	return c.InvalidateTags(ctx, ItemTag(itemID))
+}
+
+func (c *Client) InvalidateCategoryCache(ctx context.Context, categoryID string) error {
+	return c.InvalidateTags(ctx, CategoryTag(categoryID))
+}
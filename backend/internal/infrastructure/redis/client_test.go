@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteIfEquals(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	if ok, err := client.SetNX(ctx, "lock", "mine", time.Minute); err != nil || !ok {
+		t.Fatalf("SetNX = %v, %v; want true", ok, err)
+	}
+
+	if ok, err := client.DeleteIfEquals(ctx, "lock", "theirs"); err != nil || ok {
+		t.Errorf("DeleteIfEquals with another value = %v, %v; want false", ok, err)
+	}
+	if !server.Exists("lock") {
+		t.Fatal("key deleted for another value")
+	}
+
+	if ok, err := client.DeleteIfEquals(ctx, "lock", "mine"); err != nil || !ok {
+		t.Errorf("DeleteIfEquals with the stored value = %v, %v; want true", ok, err)
+	}
+	if server.Exists("lock") {
+		t.Error("key kept")
+	}
+
+	if ok, _ := client.DeleteIfEquals(ctx, "lock", "mine"); ok {
+		t.Error("DeleteIfEquals deleted a missing key")
+	}
+}
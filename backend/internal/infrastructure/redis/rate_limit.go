@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	appErrors "restaurant-menu-api/pkg/errors"
+)
+
+// TokenBucketResult is the outcome of taking a token from a bucket.
+type TokenBucketResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until a token is available again, when none was
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+}
+
+// tokenBucketScript takes one token from the bucket at KEYS[1], a hash of its
+// tokens and when they were counted, after adding those refilled since. It
+// uses the Redis clock, so every server sees the same time, and expires the
+// bucket once it would be full again, which is when it is no different from
+// a missing one.
+//
+// ARGV[1] is the bucket's capacity and ARGV[2] the tokens it regains per
+// millisecond. It returns whether a token was taken, the whole tokens left
+// and the milliseconds until the next token and until the bucket is full.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.max(reset, 1))
+
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// TakeToken takes a token from the bucket under key, which holds up to
+// capacity tokens and regains one every refillEvery.
+func (c *Client) TakeToken(ctx context.Context, key string, capacity int, refillEvery time.Duration) (*TokenBucketResult, error) {
+	rate := 1 / (float64(refillEvery) / float64(time.Millisecond))
+
+	values, err := tokenBucketScript.Run(ctx, c.rdb, []string{key}, capacity, strconv.FormatFloat(rate, 'g', -1, 64)).Int64Slice()
+	if err != nil {
+		c.logger.LogError(ctx, err, "Failed to take rate limit token in Redis", map[string]interface{}{
+			"key": key,
+		})
+		return nil, appErrors.WrapInternalError(err, "Failed to check rate limit")
+	}
+
+	return &TokenBucketResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	appErrors "restaurant-menu-api/pkg/errors"
+)
+
+// MenuTag marks every cached menu read.
+const MenuTag = "menu"
+
+// ItemTag marks cache entries built from one item.
+func ItemTag(itemID string) string {
+	return GenerateCacheKey("item", itemID)
+}
+
+// CategoryTag marks cache entries built from one category.
+func CategoryTag(categoryID string) string {
+	return GenerateCacheKey("category", categoryID)
+}
+
+// tagSetKey is the set listing the cache keys carrying tag.
+func tagSetKey(tag string) string {
+	return GenerateCacheKey("tag", tag)
+}
+
+// setWithTagsScript stores a value and adds its key to the set of each tag.
+// A tag set lives as long as its longest-lived key, so it never loses a key
+// early and goes away once all of them have expired. A set that just got its
+// first key has no expiry yet; one kept on purpose for a key without expiry
+// has other keys as well.
+//
+// KEYS[1] is the cache key and KEYS[2..] the tag sets; ARGV[1] is the value
+// and ARGV[2] its lifetime in milliseconds, 0 for none. All of them are passed
+// as KEYS, but on Redis Cluster they would have to share a hash slot; the
+// client connects to a single node.
+var setWithTagsScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+
+for i = 2, #KEYS do
+	redis.call('SADD', KEYS[i], KEYS[1])
+	if ttl > 0 then
+		local current = redis.call('PTTL', KEYS[i])
+		if (current >= 0 and current < ttl) or (current == -1 and redis.call('SCARD', KEYS[i]) == 1) then
+			redis.call('PEXPIRE', KEYS[i], ttl)
+		end
+	else
+		redis.call('PERSIST', KEYS[i])
+	end
+end
+return 1
+`)
+
+// SetWithTags caches value under key like Set and records the key under each
+// tag, for InvalidateTags to remove.
+func (c *Client) SetWithTags(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.LogError(ctx, err, "Failed to marshal value for Redis", map[string]interface{}{
+			"key": key,
+		})
+		return appErrors.WrapInternalError(err, "Failed to serialize data")
+	}
+
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, key)
+	for _, tag := range tags {
+		keys = append(keys, tagSetKey(tag))
+	}
+
+	if err := setWithTagsScript.Run(ctx, c.rdb, keys, data, expiration.Milliseconds()).Err(); err != nil {
+		c.logger.LogError(ctx, err, "Failed to set tagged value in Redis", map[string]interface{}{
+			"key":  key,
+			"tags": tags,
+		})
+		return appErrors.WrapInternalError(err, "Failed to cache data")
+	}
+
+	return nil
+}
+
+// InvalidateTags deletes every cached key carrying any of tags. Each tag set
+// is read and its keys deleted in batches of scanBatchSize, one key per DEL,
+// so that no command touches keys it wasn't given and the batches also work
+// against Redis Cluster. Only the keys read are removed from the set, so a key
+// tagged meanwhile stays listed for the next invalidation.
+func (c *Client) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		set := tagSetKey(tag)
+
+		members, err := c.rdb.SMembers(ctx, set).Result()
+		if err != nil {
+			c.logger.LogError(ctx, err, "Failed to read cache tag in Redis", map[string]interface{}{
+				"tag": tag,
+			})
+			return appErrors.WrapInternalError(err, "Failed to invalidate cache")
+		}
+
+		for start := 0; start < len(members); start += scanBatchSize {
+			batch := members[start:min(start+scanBatchSize, len(members))]
+
+			pipe := c.rdb.Pipeline()
+			listed := make([]interface{}, len(batch))
+			for i, key := range batch {
+				pipe.Del(ctx, key)
+				listed[i] = key
+			}
+			// The set is deleted by Redis once its last key is removed
+			pipe.SRem(ctx, set, listed...)
+
+			if _, err := pipe.Exec(ctx); err != nil {
+				c.logger.LogError(ctx, err, "Failed to invalidate cache tag in Redis", map[string]interface{}{
+					"tag":  tag,
+					"keys": len(batch),
+				})
+				return appErrors.WrapInternalError(err, "Failed to invalidate cache")
+			}
+		}
+	}
+
+	return nil
+}
+
+// pruneTagScript removes from a tag set those of the given keys that no
+// longer exist, returning how many it removed. Checking and removing in one
+// script keeps a key stored again meanwhile from being dropped from its tag.
+//
+// KEYS[1] is the tag set and KEYS[2..] the cache keys to check; as with
+// setWithTagsScript, they would have to share a hash slot on Redis Cluster.
+var pruneTagScript = redis.NewScript(`
+local removed = 0
+for i = 2, #KEYS do
+	if redis.call('EXISTS', KEYS[i]) == 0 then
+		removed = removed + redis.call('SREM', KEYS[1], KEYS[i])
+	end
+end
+return removed
+`)
+
+// PruneTags drops keys that have expired from the tag sets, walking them with
+// SCAN and SSCAN. Tag sets expire along with their keys, so this only matters
+// for sets a steady stream of new keys keeps alive; the server runs it every
+// CACHE_TAG_PRUNE_INTERVAL.
+func (c *Client) PruneTags(ctx context.Context) (int64, error) {
+	var pruned int64
+
+	sets := c.rdb.Scan(ctx, 0, tagSetKey("*"), scanBatchSize).Iterator()
+	for sets.Next(ctx) {
+		set := sets.Val()
+
+		// Gather the members before removing any, so the removals don't
+		// disturb the walk
+		var keys []string
+		members := c.rdb.SScan(ctx, set, 0, "", scanBatchSize).Iterator()
+		for members.Next(ctx) {
+			keys = append(keys, members.Val())
+		}
+		if err := members.Err(); err != nil {
+			c.logger.LogError(ctx, err, "Failed to scan cache tag in Redis", map[string]interface{}{
+				"tag_set": set,
+			})
+			return pruned, appErrors.WrapInternalError(err, "Failed to prune cache tags")
+		}
+
+		for start := 0; start < len(keys); start += scanBatchSize {
+			end := start + scanBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+
+			batch := append([]string{set}, keys[start:end]...)
+			removed, err := pruneTagScript.Run(ctx, c.rdb, batch).Int64()
+			if err != nil {
+				c.logger.LogError(ctx, err, "Failed to prune cache tag in Redis", map[string]interface{}{
+					"tag_set": set,
+				})
+				return pruned, appErrors.WrapInternalError(err, "Failed to prune cache tags")
+			}
+			pruned += removed
+		}
+	}
+	if err := sets.Err(); err != nil {
+		c.logger.LogError(ctx, err, "Failed to scan cache tags in Redis", nil)
+		return pruned, appErrors.WrapInternalError(err, "Failed to prune cache tags")
+	}
+
+	return pruned, nil
+}
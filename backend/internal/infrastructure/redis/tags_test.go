@@ -0,0 +1,183 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"restaurant-menu-api/pkg/logger"
+)
+
+func newTestClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return &Client{rdb: rdb, logger: log}, server
+}
+
+func TestSetWithTags(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	if err := client.SetWithTags(ctx, "menu:complete", map[string]int{"items": 3}, time.Minute, MenuTag, CategoryTag("1")); err != nil {
+		t.Fatalf("SetWithTags: %v", err)
+	}
+
+	var got map[string]int
+	if err := client.Get(ctx, "menu:complete", &got); err != nil || got["items"] != 3 {
+		t.Fatalf("Get = %v, %v; want items=3", got, err)
+	}
+
+	for _, tag := range []string{MenuTag, CategoryTag("1")} {
+		members, err := server.SMembers(tagSetKey(tag))
+		if err != nil || len(members) != 1 || members[0] != "menu:complete" {
+			t.Errorf("tag %s lists %v, %v; want menu:complete", tag, members, err)
+		}
+	}
+	if ttl := server.TTL("menu:complete"); ttl != time.Minute {
+		t.Errorf("key TTL = %v, want 1m", ttl)
+	}
+}
+
+func TestInvalidateTags(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	client.SetWithTags(ctx, "menu:complete", "value", time.Minute, MenuTag)
+	client.SetWithTags(ctx, "menu:category:1", "value", time.Minute, MenuTag, CategoryTag("1"))
+	client.SetWithTags(ctx, "menu:category:2", "value", time.Minute, CategoryTag("2"))
+	client.Set(ctx, "untagged", "value", time.Minute)
+
+	if err := client.InvalidateTags(ctx, CategoryTag("1")); err != nil {
+		t.Fatalf("InvalidateTags: %v", err)
+	}
+	if server.Exists("menu:category:1") || !server.Exists("menu:complete") {
+		t.Error("invalidating category 1 dropped the wrong keys")
+	}
+	if server.Exists(tagSetKey(CategoryTag("1"))) {
+		t.Error("emptied tag set kept")
+	}
+
+	if err := client.InvalidateTags(ctx, MenuTag); err != nil {
+		t.Fatalf("InvalidateTags: %v", err)
+	}
+	for key, want := range map[string]bool{"menu:complete": false, "menu:category:2": true, "untagged": true} {
+		if got := server.Exists(key); got != want {
+			t.Errorf("%s exists = %v, want %v", key, got, want)
+		}
+	}
+
+	// Invalidating a tag nothing carries is not an error
+	if err := client.InvalidateTags(ctx, ItemTag("unknown")); err != nil {
+		t.Errorf("InvalidateTags of an unused tag: %v", err)
+	}
+}
+
+func TestInvalidateTagsInBatches(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	keys := scanBatchSize*2 + 7
+	for i := 0; i < keys; i++ {
+		client.SetWithTags(ctx, fmt.Sprintf("menu:%d", i), i, time.Minute, MenuTag)
+	}
+
+	if err := client.InvalidateTags(ctx, MenuTag); err != nil {
+		t.Fatalf("InvalidateTags: %v", err)
+	}
+	if remaining := len(server.Keys()); remaining != 0 {
+		t.Errorf("%d keys left after invalidating %d", remaining, keys)
+	}
+}
+
+func TestTagSetExpiry(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	client.SetWithTags(ctx, "short", "value", time.Minute, MenuTag)
+	client.SetWithTags(ctx, "long", "value", time.Hour, MenuTag)
+	client.SetWithTags(ctx, "shorter", "value", time.Second, MenuTag)
+
+	// The set lives as long as its longest-lived key
+	if ttl := server.TTL(tagSetKey(MenuTag)); ttl != time.Hour {
+		t.Errorf("tag set TTL = %v, want 1h", ttl)
+	}
+
+	server.FastForward(time.Hour + time.Second)
+	if server.Exists(tagSetKey(MenuTag)) {
+		t.Error("tag set outlived all its keys")
+	}
+
+	// A key without expiry keeps its tag set for good
+	client.SetWithTags(ctx, "expiring", "value", time.Minute, ItemTag("1"))
+	client.SetWithTags(ctx, "forever", "value", 0, ItemTag("1"))
+	if ttl := server.TTL(tagSetKey(ItemTag("1"))); ttl != 0 {
+		t.Errorf("tag set of a key without expiry has TTL %v", ttl)
+	}
+}
+
+func TestPruneTags(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	client.SetWithTags(ctx, "short", "value", time.Minute, MenuTag)
+	client.SetWithTags(ctx, "long", "value", time.Hour, MenuTag)
+	server.FastForward(2 * time.Minute)
+
+	pruned, err := client.PruneTags(ctx)
+	if err != nil {
+		t.Fatalf("PruneTags: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned %d keys, want 1", pruned)
+	}
+
+	members, _ := server.SMembers(tagSetKey(MenuTag))
+	if len(members) != 1 || members[0] != "long" {
+		t.Errorf("tag set lists %v, want [long]", members)
+	}
+}
+
+func TestPruneTagsInBatches(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	keys := 2*scanBatchSize + 10
+	for i := 0; i < keys; i++ {
+		expiration := time.Hour
+		if i%2 == 0 {
+			expiration = time.Minute
+		}
+		client.SetWithTags(ctx, fmt.Sprintf("menu:%d", i), "value", expiration, MenuTag)
+	}
+	server.FastForward(2 * time.Minute)
+
+	pruned, err := client.PruneTags(ctx)
+	if err != nil {
+		t.Fatalf("PruneTags: %v", err)
+	}
+	if pruned != int64(keys/2) {
+		t.Errorf("pruned %d keys, want %d", pruned, keys/2)
+	}
+
+	members, _ := server.SMembers(tagSetKey(MenuTag))
+	if len(members) != keys/2 {
+		t.Errorf("tag set lists %d keys, want %d", len(members), keys/2)
+	}
+	for _, member := range members {
+		if !server.Exists(member) {
+			t.Errorf("tag set still lists expired key %s", member)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -26,6 +28,7 @@ type ServerConfig struct {
 	DB          *database.Database
 	S3Client    *aws.S3Client
 	RedisClient *redis.Client
+	Cache       services.Cache
 	Logger      *logger.Logger
 }
 
@@ -35,7 +38,17 @@ type Server struct {
 	db          *database.Database
 	s3Client    *aws.S3Client
 	redisClient *redis.Client
+	cache       services.Cache
 	logger      *logger.Logger
+
+	apiKeyService services.APIKeyService
+	// memoryRateLimitStore is set when buckets are kept in memory, to be
+	// closed on Shutdown
+	memoryRateLimitStore *middleware.MemoryRateLimitStore
+
+	// stop ends the background jobs on Shutdown
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewServer(cfg *ServerConfig) *Server {
@@ -51,16 +64,31 @@ func NewServer(cfg *ServerConfig) *Server {
 		db:          cfg.DB,
 		s3Client:    cfg.S3Client,
 		redisClient: cfg.RedisClient,
+		cache:       cfg.Cache,
 		logger:      cfg.Logger,
+		stop:        make(chan struct{}),
 	}
 
 	server.setupRouter()
+
+	if server.redisClient != nil {
+		go server.pruneCacheTagsEvery(server.config.Cache.TagPruneInterval)
+	}
+
 	return server
 }
 
 func (s *Server) setupRouter() {
 	s.router = gin.New()
 
+	// Believe forwarded client addresses only from the configured proxies
+	if err := s.router.SetTrustedProxies(s.config.Server.TrustedProxies); err != nil {
+		s.logger.WithError(err).Fatal("Invalid trusted proxies")
+	}
+
+	// API keys are checked by the middleware as well as managed by the routes
+	s.apiKeyService = services.NewAPIKeyService(databaseRepo.NewAPIKeyRepository(s.db.DB), s.cache, s.logger)
+
 	// Setup middleware
 	s.setupMiddleware()
 
@@ -76,8 +104,8 @@ func (s *Server) setupMiddleware() {
 	s.router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000", "http://localhost:3002", "http://127.0.0.1:3002", "http://localhost:5174", "http://127.0.0.1:5174", "http://localhost:5173", "http://127.0.0.1:5173"}, // Add your frontend URLs
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "If-None-Match", "If-Modified-Since", middleware.APIKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "ETag", "Last-Modified", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "X-Quota-Limit", "X-Quota-Remaining"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -86,15 +114,21 @@ func (s *Server) setupMiddleware() {
 	s.router.Use(middleware.RequestLogger(s.logger))
 	s.router.Use(middleware.RequestID())
 	s.router.Use(middleware.TimestampMiddleware())
+	s.router.Use(middleware.Compression(s.config.Compression.MinSize))
+
+	// API keys - identify keyed clients before their limits are applied
+	s.router.Use(middleware.APIKeyAuth(s.apiKeyService, s.logger))
 
-	// Rate limiting - use Redis-based if available, fallback to simple limiter
+	// Rate limiting - keep buckets in Redis if available, in memory otherwise
+	var rateLimitStore middleware.RateLimitStore
 	if s.redisClient != nil {
-		advancedRateLimiter := middleware.NewAdvancedRateLimiter(s.redisClient, s.logger)
-		s.router.Use(advancedRateLimiter.Middleware())
+		rateLimitStore = middleware.NewRedisRateLimitStore(s.redisClient)
 	} else {
-		simpleRateLimiter := middleware.NewSimpleRateLimiter(100, time.Minute)
-		s.router.Use(simpleRateLimiter.Middleware())
+		s.memoryRateLimitStore = middleware.NewMemoryRateLimitStore(s.config.RateLimit.MemoryMaxClients, s.config.RateLimit.MemorySweepInterval)
+		rateLimitStore = s.memoryRateLimitStore
 	}
+	rateLimiter := middleware.NewRateLimiter(rateLimitStore, s.config.RateLimit, s.logger)
+	s.router.Use(rateLimiter.Middleware())
 
 	s.router.Use(middleware.Timeout(30 * time.Second))
 }
@@ -106,24 +140,48 @@ func (s *Server) setupRoutes() {
 	itemRepo := databaseRepo.NewItemRepository(s.db.DB)
 	restaurantRepo := databaseRepo.NewRestaurantRepository(s.db.DB)
 	contentRepo := databaseRepo.NewContentRepository(s.db.DB)
+	slugRedirectRepo := databaseRepo.NewSlugRedirectRepository(s.db.DB)
+	searchQueryRepo := databaseRepo.NewSearchQueryRepository(s.db.DB)
+	searchDictionaryRepo := databaseRepo.NewSearchDictionaryRepository(s.db.DB)
+	menuVersionRepo := databaseRepo.NewMenuVersionRepository(s.db.DB)
 
 	// Initialize services
-	categoryService := services.NewCategoryService(categoryRepo, s.logger)
-	subCategoryService := services.NewSubCategoryService(subCategoryRepo, s.logger)
-	itemService := services.NewItemService(itemRepo, s.logger)
-	restaurantService := services.NewRestaurantService(restaurantRepo, s.logger)
+	menuService := services.NewMenuService(categoryRepo, subCategoryRepo, itemRepo, slugRedirectRepo, searchQueryRepo, searchDictionaryRepo, s.cache, s.config.Search.SimilarityThreshold, s.logger)
+
+	// Cache built menus, dropping them whenever menu data is written
+	cachedMenuService := services.NewCachedMenuService(menuService, s.cache, services.MenuCacheConfig{
+		MenuTTL:         s.config.Cache.MenuTTL,
+		CategoryMenuTTL: s.config.Cache.CategoryMenuTTL,
+		FeaturedTTL:     s.config.Cache.FeaturedTTL,
+		LockTimeout:     s.config.Cache.LockTimeout,
+	}, s.logger)
+	menuService = cachedMenuService
+
+	// Answer public reads from their last good copy while the database is down
+	snapshots := services.NewSnapshotStore(s.cache, s.config.Snapshot.Dir, s.config.Snapshot.SaveInterval, s.logger)
+	menuService = services.NewStaleMenuService(menuService, snapshots, s.logger)
+
+	categoryService := services.NewMenuInvalidatingCategoryService(services.NewCategoryService(categoryRepo, s.logger), cachedMenuService)
+	subCategoryService := services.NewMenuInvalidatingSubCategoryService(services.NewSubCategoryService(subCategoryRepo, s.logger), cachedMenuService)
+	itemService := services.NewMenuInvalidatingItemService(services.NewItemService(itemRepo, s.logger), cachedMenuService)
+	restaurantService := services.NewStaleRestaurantService(services.NewMenuInvalidatingRestaurantService(services.NewRestaurantService(restaurantRepo, s.logger), cachedMenuService), snapshots, s.logger)
 	contentService := services.NewContentService(contentRepo, s.logger)
-	menuService := services.NewMenuService(categoryRepo, subCategoryRepo, itemRepo, s.logger)
+	searchAnalyticsService := services.NewSearchAnalyticsService(searchQueryRepo, itemRepo, s.logger)
+	searchDictionaryService := services.NewSearchDictionaryService(searchDictionaryRepo, s.cache, s.logger)
+	menuVersionService := services.NewMenuVersionService(menuVersionRepo, s.cache, s.config.Cache.MenuTTL, s.logger)
 
 	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(s.db, s.logger)
-	categoryHandler := handlers.NewCategoryHandler(categoryService, s.logger)
-	subCategoryHandler := handlers.NewSubCategoryHandler(subCategoryService, categoryService, s.logger)
-	itemHandler := handlers.NewItemHandler(itemService, subCategoryService, s.logger)
+	healthHandler := handlers.NewHealthHandler(s.db, cachedMenuService, snapshots, s.logger)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, menuVersionService, s.logger)
+	subCategoryHandler := handlers.NewSubCategoryHandler(subCategoryService, categoryService, menuVersionService, s.logger)
+	itemHandler := handlers.NewItemHandler(itemService, subCategoryService, menuVersionService, s.logger)
 	restaurantHandler := handlers.NewRestaurantHandler(restaurantService, s.logger)
 	contentHandler := handlers.NewContentHandler(contentService, s.logger)
-	menuHandler := handlers.NewMenuHandler(menuService, s.logger)
+	menuHandler := handlers.NewMenuHandler(menuService, menuVersionService, s.logger)
+	searchAnalyticsHandler := handlers.NewSearchAnalyticsHandler(searchAnalyticsService, s.logger)
+	searchDictionaryHandler := handlers.NewSearchDictionaryHandler(searchDictionaryService, s.logger)
 	uploadHandler := handlers.NewUploadHandler(s.s3Client, s.logger)
+	apiKeyHandler := handlers.NewAPIKeyHandler(s.apiKeyService, s.logger)
 
 	// Health check routes (ROOT level - industry standard)
 	s.router.GET("/health", healthHandler.Health)
@@ -146,6 +204,54 @@ func (s *Server) setupRoutes() {
 		menu := v1.Group("/menu")
 		{
 			menu.GET("", menuHandler.GetCompleteMenu)
+			menu.GET("/search", menuHandler.Search)
+			menu.POST("/search/:searchId/clicks", searchAnalyticsHandler.RecordClick)
+			menu.GET("/suggest", menuHandler.Suggest)
+			menu.GET("/tree", menuHandler.GetMenuTree)
+			menu.GET("/tree/:categoryId", menuHandler.GetCategoryTree)
+			menu.GET("/:categorySlug", menuHandler.GetBySlug)
+			menu.GET("/:categorySlug/:subSlug", menuHandler.GetBySlug)
+			menu.GET("/:categorySlug/:subSlug/:itemSlug", menuHandler.GetBySlug)
+		}
+
+		// Issuing keys, reading analytics and editing the search dictionary
+		// need an admin key; the first one is created with the maintenance
+		// create-admin-key command
+		requireAdminKey := middleware.RequireAdminAPIKey()
+
+		// Search analytics endpoints
+		searchAnalytics := v1.Group("/analytics/search", requireAdminKey)
+		{
+			searchAnalytics.GET("/top-queries", searchAnalyticsHandler.TopQueries)
+			searchAnalytics.GET("/zero-results", searchAnalyticsHandler.ZeroResultQueries)
+			searchAnalytics.GET("/click-through", searchAnalyticsHandler.ClickThrough)
+		}
+
+		// API key endpoints
+		apiKeys := v1.Group("/api-keys", requireAdminKey)
+		{
+			apiKeys.GET("", apiKeyHandler.GetAll)
+			apiKeys.POST("", apiKeyHandler.Create)
+			apiKeys.GET("/:id", apiKeyHandler.GetByID)
+			apiKeys.PUT("/:id", apiKeyHandler.Update)
+			apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+		}
+		v1.GET("/analytics/api-keys/usage", requireAdminKey, apiKeyHandler.Usage)
+
+		// Search dictionary endpoints; changing them changes every guest's
+		// search, so writes need an admin key
+		search := v1.Group("/search")
+		{
+			search.GET("/synonyms", searchDictionaryHandler.GetSynonyms)
+			search.GET("/stop-words", searchDictionaryHandler.GetStopWords)
+		}
+		searchAdmin := v1.Group("/search", requireAdminKey)
+		{
+			searchAdmin.POST("/synonyms", searchDictionaryHandler.CreateSynonym)
+			searchAdmin.PUT("/synonyms/:id", searchDictionaryHandler.UpdateSynonym)
+			searchAdmin.DELETE("/synonyms/:id", searchDictionaryHandler.DeleteSynonym)
+			searchAdmin.POST("/stop-words", searchDictionaryHandler.CreateStopWord)
+			searchAdmin.DELETE("/stop-words/:id", searchDictionaryHandler.DeleteStopWord)
 		}
 
 		// Category endpoints
@@ -153,11 +259,14 @@ func (s *Server) setupRoutes() {
 		{
 			categories.GET("", categoryHandler.GetAll)
 			categories.POST("", categoryHandler.Create)
+			categories.POST("/bulk", categoryHandler.Bulk)
 			categories.GET("/:id", categoryHandler.GetByID)
 			categories.PUT("/:id", categoryHandler.Update)
 			categories.DELETE("/:id", categoryHandler.Delete)
 			categories.PATCH("/:id/toggle", categoryHandler.ToggleActive)
 			categories.PATCH("/:id/order", categoryHandler.UpdateDisplayOrder)
+			categories.PUT("/reorder", categoryHandler.Reorder)
+			categories.POST("/:id/clone", categoryHandler.Clone)
 		}
 
 		// SubCategory endpoints
@@ -170,6 +279,11 @@ func (s *Server) setupRoutes() {
 			subcategories.DELETE("/:id", subCategoryHandler.Delete)
 			subcategories.PATCH("/:id/toggle", subCategoryHandler.ToggleActive)
 			subcategories.PATCH("/:id/order", subCategoryHandler.UpdateDisplayOrder)
+			subcategories.PUT("/reorder", subCategoryHandler.Reorder)
+			subcategories.POST("/:id/clone", subCategoryHandler.Clone)
+			subcategories.GET("/:id/tree", subCategoryHandler.GetSubtree)
+			subcategories.GET("/:id/breadcrumbs", subCategoryHandler.GetBreadcrumbs)
+			subcategories.PUT("/:id/move", subCategoryHandler.Move)
 		}
 
 		// Item endpoints
@@ -177,11 +291,17 @@ func (s *Server) setupRoutes() {
 		{
 			items.GET("", itemHandler.GetAll)
 			items.POST("", itemHandler.Create)
+			items.POST("/bulk", itemHandler.Bulk)
 			items.GET("/:id", itemHandler.GetByID)
 			items.PUT("/:id", itemHandler.Update)
 			items.DELETE("/:id", itemHandler.Delete)
 			items.PATCH("/:id/toggle", itemHandler.ToggleAvailable)
 			items.PATCH("/:id/order", itemHandler.UpdateDisplayOrder)
+			items.PUT("/reorder", itemHandler.Reorder)
+			items.POST("/:id/clone", itemHandler.Clone)
+			items.GET("/:id/placements", itemHandler.GetPlacements)
+			items.POST("/:id/placements", itemHandler.AddPlacement)
+			items.DELETE("/:id/placements/:subCategoryId", itemHandler.RemovePlacement)
 			items.PATCH("/:id/price", itemHandler.UpdatePrice)
 			items.GET("/search", itemHandler.Search)
 			items.GET("/featured", itemHandler.GetFeatured)
@@ -232,3 +352,40 @@ func (s *Server) Start(address string) error {
 	s.logger.WithField("address", address).Info("Starting server")
 	return s.router.Run(address)
 }
+
+// Shutdown stops the server's background jobs and writes the API key usage
+// still counted. Call it once the HTTP server has stopped taking requests and
+// before the database is closed.
+func (s *Server) Shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		s.apiKeyService.Close()
+		if s.memoryRateLimitStore != nil {
+			s.memoryRateLimitStore.Close()
+		}
+	})
+}
+
+// pruneCacheTagsEvery drops expired keys from the Redis cache tag sets until
+// Shutdown.
+func (s *Server) pruneCacheTagsEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			ctx := context.Background()
+			pruned, err := s.redisClient.PruneTags(ctx)
+			if err != nil {
+				// The client logs the failure; the next run tries again
+				continue
+			}
+			s.logger.LogDebug(ctx, "Pruned cache tags", map[string]interface{}{
+				"pruned": pruned,
+			})
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/services"
+	"restaurant-menu-api/pkg/logger"
+	"restaurant-menu-api/pkg/response"
+	"restaurant-menu-api/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	service services.APIKeyService
+	logger  *logger.Logger
+}
+
+type APIKeyRequest struct {
+	Name           string   `json:"name" binding:"required,max=100"`
+	Scope          string   `json:"scope" binding:"omitempty,oneof=menu admin"`
+	AllowedOrigins []string `json:"allowed_origins" binding:"omitempty,max=20,dive,required,max=255"`
+	RateLimit      int      `json:"rate_limit" binding:"min=0"`
+	DailyQuota     int      `json:"daily_quota" binding:"min=0"`
+}
+
+func NewAPIKeyHandler(service services.APIKeyService, logger *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetAll godoc
+// @Summary Get API keys
+// @Description Get every issued API key, including revoked ones. Secrets are never returned.
+// @Tags API Keys
+// @Produce json
+// @Success 200 {array} entities.APIKey
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/api-keys [get]
+func (h *APIKeyHandler) GetAll(c *gin.Context) {
+	keys, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, keys)
+}
+
+// GetByID godoc
+// @Summary Get an API key
+// @Tags API Keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} entities.APIKey
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/api-keys/{id} [get]
+func (h *APIKeyHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID", "ID must be a positive integer")
+		return
+	}
+
+	key, err := h.service.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, key)
+}
+
+// Create godoc
+// @Summary Issue an API key
+// @Description Issue a key for a partner or screen. The key is only returned in this response; send it in the X-API-Key header.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param apiKey body APIKeyRequest true "Name, scope (menu or admin, default menu), allowed origins, requests per minute and daily quota (0 for none)"
+// @Success 201 {object} entities.CreatedAPIKey
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	created, err := h.service.Create(ctx, req.toAPIKey())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "API key created successfully", map[string]interface{}{
+		"api_key_id": created.ID,
+		"scope":      created.Scope,
+	})
+
+	response.Created(c, created)
+}
+
+// Update godoc
+// @Summary Update an API key
+// @Description Replace the name, scope, allowed origins and limits of a key; its next request uses them
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Param apiKey body APIKeyRequest true "Name, scope, allowed origins, requests per minute and daily quota"
+// @Success 200 {object} entities.APIKey
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/api-keys/{id} [put]
+func (h *APIKeyHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID", "ID must be a positive integer")
+		return
+	}
+
+	var req APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	key, err := h.service.Update(ctx, uint(id), req.toAPIKey())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "API key updated successfully", map[string]interface{}{
+		"api_key_id": key.ID,
+		"scope":      key.Scope,
+	})
+
+	response.Success(c, key)
+}
+
+// Revoke godoc
+// @Summary Revoke an API key
+// @Description Stop accepting a key. Its usage stays in the reports.
+// @Tags API Keys
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID", "ID must be a positive integer")
+		return
+	}
+
+	if err := h.service.Revoke(ctx, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "API key revoked successfully", map[string]interface{}{
+		"api_key_id": id,
+	})
+
+	response.NoContent(c)
+}
+
+// Usage godoc
+// @Summary Get API key usage
+// @Description Get the requests made with API keys by key and endpoint, most requested first. Usage is counted per UTC day and written every 30 seconds.
+// @Tags API Keys
+// @Produce json
+// @Param api_key_id query int false "Only the usage of this key"
+// @Param from query string false "Start of the period, RFC 3339 or YYYY-MM-DD (default 30 days before to)"
+// @Param to query string false "End of the period, RFC 3339 or YYYY-MM-DD inclusive (default now)"
+// @Param limit query int false "Number of rows to return (max 500, default 50)"
+// @Success 200 {array} entities.APIKeyUsageReport
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/analytics/api-keys/usage [get]
+func (h *APIKeyHandler) Usage(c *gin.Context) {
+	filter := entities.APIKeyUsageFilter{
+		Limit: utils.ParseInt(c.Query("limit"), 0),
+	}
+
+	if value := c.Query("api_key_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid API key ID", "ID must be a positive integer")
+			return
+		}
+		filter.APIKeyID = uint(id)
+	}
+
+	var err error
+	if filter.From, err = parseReportTime(c.Query("from"), false); err != nil {
+		response.BadRequest(c, "Invalid from date", "Use RFC 3339 or YYYY-MM-DD")
+		return
+	}
+	if filter.To, err = parseReportTime(c.Query("to"), true); err != nil {
+		response.BadRequest(c, "Invalid to date", "Use RFC 3339 or YYYY-MM-DD")
+		return
+	}
+
+	reports, err := h.service.UsageReport(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, reports)
+}
+
+func (r *APIKeyRequest) toAPIKey() *entities.APIKey {
+	return &entities.APIKey{
+		Name:           r.Name,
+		Scope:          entities.APIKeyScope(r.Scope),
+		AllowedOrigins: entities.StringList(r.AllowedOrigins),
+		RateLimit:      r.RateLimit,
+		DailyQuota:     r.DailyQuota,
+	}
+}
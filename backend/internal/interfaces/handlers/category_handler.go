@@ -14,8 +14,9 @@ import (
 )
 
 type CategoryHandler struct {
-	service services.CategoryService
-	logger  *logger.Logger
+	service  services.CategoryService
+	versions services.MenuVersionService
+	logger   *logger.Logger
 }
 
 type CreateCategoryRequest struct {
@@ -36,10 +37,21 @@ type UpdateDisplayOrderRequest struct {
 	DisplayOrder int `json:"display_order" binding:"required"`
 }
 
-func NewCategoryHandler(service services.CategoryService, logger *logger.Logger) *CategoryHandler {
+type ReorderCategoriesRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+type BulkCategoryRequest struct {
+	IDs       []uint                         `json:"ids" binding:"required,min=1"`
+	Operation entities.BulkCategoryOperation `json:"operation"`
+	DryRun    bool                           `json:"dry_run"`
+}
+
+func NewCategoryHandler(service services.CategoryService, versions services.MenuVersionService, logger *logger.Logger) *CategoryHandler {
 	return &CategoryHandler{
-		service: service,
-		logger:  logger,
+		service:  service,
+		versions: versions,
+		logger:   logger,
 	}
 }
 
@@ -53,10 +65,16 @@ func NewCategoryHandler(service services.CategoryService, logger *logger.Logger)
 // @Param search query string false "Search in name and description"
 // @Param limit query int false "Number of items to return"
 // @Param offset query int false "Number of items to skip"
-// @Param order_by query string false "Field to order by"
-// @Param order_dir query string false "Order direction (ASC/DESC)"
+// @Param sort query string false "Fields to sort by, comma-separated and descending with a leading minus, e.g. -created_at,name (name, display_order, created_at, updated_at)"
+// @Param order_by query string false "Field to order by (deprecated, use sort)"
+// @Param order_dir query string false "Order direction (ASC/DESC, deprecated, use sort)"
 // @Param include_count query boolean false "Include total count"
+// @Param fields query string false "Comma-separated fields to return (id, name, description, slug, display_order, active, created_at, updated_at)"
+// @Param expand query string false "Comma-separated relations to load (sub_categories); none by default"
+// @Param cursor query string false "Keyset pagination in display order: empty for the first page, then meta.next_cursor; ignores offset and sort"
 // @Success 200 {array} entities.Category
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 400 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /v1/categories [get]
 func (h *CategoryHandler) GetAll(c *gin.Context) {
@@ -66,12 +84,31 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 	filter := entities.CategoryFilter{
 		Limit:        utils.ParseInt(c.Query("limit"), 10),
 		Offset:       utils.ParseInt(c.Query("offset"), 0),
-		OrderBy:      c.DefaultQuery("order_by", "display_order"),
-		OrderDir:     c.DefaultQuery("order_dir", "ASC"),
 		Search:       c.Query("search"),
 		IncludeCount: c.Query("include_count") == "true",
 	}
 
+	sort, err := querySort(c, entities.CategorySorts, "")
+	if err != nil {
+		response.ValidationError(c, "Invalid sort", err.Error())
+		return
+	}
+	filter.Sort = sort
+
+	selection, err := queryFieldSelection(c, entities.CategoryFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+	filter.Expand = selection.expand
+
+	cursor, err := queryCursor(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid cursor", "Use the next_cursor of the previous page")
+		return
+	}
+	filter.Cursor = cursor
+
 	if active := c.Query("active"); active != "" {
 		if active == "true" {
 			filter.Active = utils.BoolPtr(true)
@@ -80,6 +117,10 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 		}
 	}
 
+	if notModified(c, h.versions) {
+		return
+	}
+
 	categories, pagination, err := h.service.GetAll(ctx, filter)
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get categories", nil)
@@ -87,10 +128,18 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	if filter.IncludeCount && pagination != nil {
-		response.SuccessWithPagination(c, categories, pagination)
+	data, err := selection.project(categories)
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get categories"))
+		return
+	}
+
+	if filter.Cursor != nil {
+		response.SuccessWithCursor(c, data, pagination)
+	} else if filter.IncludeCount && pagination != nil {
+		response.SuccessWithPagination(c, data, pagination)
 	} else {
-		response.Success(c, categories)
+		response.Success(c, data)
 	}
 }
 
@@ -102,7 +151,10 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Category ID"
 // @Param include_subcategories query boolean false "Include subcategories in response"
+// @Param fields query string false "Comma-separated fields to return (id, name, description, slug, display_order, active, created_at, updated_at)"
+// @Param expand query string false "Comma-separated relations to load (sub_categories); usual ones by default"
 // @Success 200 {object} entities.Category
+// @Success 304 "Not modified since the ETag or date sent"
 // @Failure 400 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
@@ -116,8 +168,18 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	selection, err := queryFieldSelection(c, entities.CategoryFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+
 	// Check if we should include subcategories
-	includeSubCategories := c.Query("include_subcategories") == "true"
+	includeSubCategories := c.Query("include_subcategories") == "true" || selection.expand.Has("sub_categories")
+
+	if notModified(c, h.versions) {
+		return
+	}
 
 	var category *entities.Category
 	if includeSubCategories {
@@ -139,7 +201,13 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, category)
+	data, err := selection.project(category)
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get category"))
+		return
+	}
+
+	response.Success(c, data)
 }
 
 // CreateCategory godoc
@@ -425,3 +493,108 @@ func (h *CategoryHandler) UpdateDisplayOrder(c *gin.Context) {
 
 	response.Success(c, updatedCategory)
 }
+
+// BulkCategories godoc
+// @Summary Bulk update categories
+// @Description Activate, deactivate or delete several categories in a single transaction. Use dry_run to preview the affected rows without writing.
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param request body BulkCategoryRequest true "Category IDs, operation and dry-run flag"
+// @Success 200 {object} entities.BulkResult
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/categories/bulk [post]
+func (h *CategoryHandler) Bulk(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req BulkCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	result, err := h.service.BulkApply(ctx, req.IDs, req.Operation, req.DryRun)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to apply bulk category operation", map[string]interface{}{
+			"operation": req.Operation.Type,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ReorderCategories godoc
+// @Summary Reorder categories
+// @Description Replace the ordering of all categories with the given ID list. The list must contain every category exactly once and is applied atomically with consecutive display order values.
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param order body ReorderCategoriesRequest true "Ordered category IDs"
+// @Success 200 {array} entities.Category
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /v1/categories/reorder [put]
+func (h *CategoryHandler) Reorder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	categories, err := h.service.Reorder(ctx, req.IDs)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to reorder categories", nil)
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, categories)
+}
+
+// CloneCategory godoc
+// @Summary Clone a category
+// @Description Duplicate a category together with its subcategories and items in one transaction. The copy gets a new name (original name plus suffix) and a new slug.
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param options body CloneRequest false "Name suffix, defaults to ' (Copy)'"
+// @Success 201 {object} entities.Category
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /v1/categories/{id}/clone [post]
+func (h *CategoryHandler) Clone(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID", "ID must be a positive integer")
+		return
+	}
+
+	var req CloneRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	clone, err := h.service.Clone(ctx, uint(id), entities.CloneOptions{
+		NameSuffix: req.NameSuffix,
+	})
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to clone category", map[string]interface{}{
+			"category_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, clone)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/services"
+)
+
+// menuCacheControl lets clients keep menu reads but makes them revalidate on
+// every use, which costs a 304 when nothing changed.
+const menuCacheControl = "public, no-cache"
+
+// notModified sets the validators of the current menu version on the response
+// and reports whether the request's If-None-Match or If-Modified-Since shows
+// the client already has it, in which case it has answered 304. Reads are
+// served unconditionally when the version can't be determined.
+func notModified(c *gin.Context, versions services.MenuVersionService) bool {
+	version, err := versions.GetVersion(c.Request.Context())
+	if err != nil {
+		return false
+	}
+
+	etag := version.ETag()
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", menuCacheControl)
+	if !version.LastModified.IsZero() {
+		c.Header("Last-Modified", version.LastModified.Format(http.TimeFormat))
+	}
+
+	// If-Modified-Since only counts when no entity tags were sent
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		if err != nil || version.LastModified.IsZero() || version.LastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches compares an If-None-Match list with etag the weak way, as
+// RFC 9110 asks for GET requests.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
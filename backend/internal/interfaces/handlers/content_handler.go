@@ -53,9 +53,11 @@ func NewContentHandler(service services.ContentService, logger *logger.Logger) *
 // @Param search query string false "Search in title and content"
 // @Param limit query int false "Number of items to return"
 // @Param offset query int false "Number of items to skip"
-// @Param order_by query string false "Field to order by"
-// @Param order_dir query string false "Order direction (ASC/DESC)"
+// @Param sort query string false "Fields to sort by, comma-separated and descending with a leading minus (section_name, title, created_at, updated_at; default -created_at)"
+// @Param order_by query string false "Field to order by (deprecated, use sort)"
+// @Param order_dir query string false "Order direction (ASC/DESC, deprecated, use sort)"
 // @Success 200 {array} entities.ContentSection
+// @Failure 400 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/content [get]
 func (h *ContentHandler) GetAll(c *gin.Context) {
@@ -68,10 +70,15 @@ func (h *ContentHandler) GetAll(c *gin.Context) {
 		Search:      c.Query("search"),
 		Limit:       utils.ParseInt(c.Query("limit"), 0),
 		Offset:      utils.ParseInt(c.Query("offset"), 0),
-		OrderBy:     c.DefaultQuery("order_by", "created_at"),
-		OrderDir:    c.DefaultQuery("order_dir", "DESC"),
 	}
 
+	sort, err := querySort(c, entities.ContentSorts, "-created_at")
+	if err != nil {
+		response.ValidationError(c, "Invalid sort", err.Error())
+		return
+	}
+	filter.Sort = sort
+
 	content, err := h.service.GetAll(ctx, filter)
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get content", nil)
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/pkg/utils"
+)
+
+// fieldSelection is the ?fields= and ?expand= of a read request.
+type fieldSelection struct {
+	registry entities.FieldRegistry
+	fields   []string
+	expand   entities.Expand
+}
+
+// queryFieldSelection reads ?fields=id,name,price and
+// ?expand=sub_category,sub_category.category. Without expand the endpoint's
+// usual relations are loaded; an empty ?expand= loads none.
+func queryFieldSelection(c *gin.Context, registry entities.FieldRegistry) (*fieldSelection, error) {
+	fields, err := registry.ParseFields(c.Query("fields"))
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &fieldSelection{registry: registry, fields: fields}
+	if param, ok := c.GetQuery("expand"); ok {
+		if selection.expand, err = registry.ParseExpand(param); err != nil {
+			return nil, err
+		}
+	}
+	return selection, nil
+}
+
+// project narrows the objects at path in data to the selected fields and
+// expanded relations. Path walks object keys, and arrays met along the way are
+// projected element by element; an empty path projects data itself.
+func (s *fieldSelection) project(data interface{}, path ...string) (interface{}, error) {
+	if len(s.fields) == 0 && s.expand == nil {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	s.projectValue(value, path)
+	return value, nil
+}
+
+func (s *fieldSelection) projectValue(value interface{}, path []string) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, element := range v {
+			s.projectValue(element, path)
+		}
+	case map[string]interface{}:
+		if len(path) > 0 {
+			if child, ok := v[path[0]]; ok {
+				s.projectValue(child, path[1:])
+			}
+			return
+		}
+		s.narrow(v)
+	}
+}
+
+func (s *fieldSelection) narrow(object map[string]interface{}) {
+	if len(s.fields) > 0 {
+		for key := range object {
+			if key == "id" || s.registry.IsRelation(key) {
+				continue
+			}
+			if !utils.Contains(s.fields, key) {
+				delete(object, key)
+			}
+		}
+	}
+
+	if s.expand == nil {
+		return
+	}
+	for _, relation := range s.registry.Relations() {
+		if !s.expand.Has(relation) {
+			removeRelation(object, strings.Split(relation, "."))
+		}
+	}
+}
+
+// removeRelation deletes the relation at path below value.
+func removeRelation(value interface{}, path []string) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, element := range v {
+			removeRelation(element, path)
+		}
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		if child, ok := v[path[0]]; ok {
+			removeRelation(child, path[1:])
+		}
+	}
+}
@@ -7,13 +7,16 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"restaurant-menu-api/internal/database"
+	"restaurant-menu-api/internal/domain/services"
 	"restaurant-menu-api/pkg/logger"
 	"restaurant-menu-api/pkg/response"
 )
 
 type HealthHandler struct {
-	db     *database.Database
-	logger *logger.Logger
+	db         *database.Database
+	cacheStats services.CacheStatsReporter
+	degraded   services.DegradedReporter
+	logger     *logger.Logger
 }
 
 type HealthResponse struct {
@@ -26,10 +29,12 @@ type HealthResponse struct {
 
 var startTime = time.Now()
 
-func NewHealthHandler(db *database.Database, logger *logger.Logger) *HealthHandler {
+func NewHealthHandler(db *database.Database, cacheStats services.CacheStatsReporter, degraded services.DegradedReporter, logger *logger.Logger) *HealthHandler {
 	return &HealthHandler{
-		db:     db,
-		logger: logger,
+		db:         db,
+		cacheStats: cacheStats,
+		degraded:   degraded,
+		logger:     logger,
 	}
 }
 
@@ -52,7 +57,7 @@ func (h *HealthHandler) Health(c *gin.Context) {
 
 // Ready godoc
 // @Summary Readiness check
-// @Description Check if the application is ready to serve requests
+// @Description Check if the application is ready to serve requests. While the database is down it stays ready in degraded mode, serving the public menu from its last good snapshot.
 // @Tags Health
 // @Accept json
 // @Produce json
@@ -85,6 +90,18 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 		status = "not ready"
 	}
 
+	// Public menu reads fall back to snapshots, so the server keeps taking
+	// traffic in degraded mode while its database is down
+	ready := allHealthy
+	if h.degraded != nil {
+		degradedStatus := h.degraded.DegradedStatus()
+		checks["snapshots"] = degradedStatus
+		if !allHealthy || degradedStatus.Degraded {
+			status = "degraded"
+			ready = true
+		}
+	}
+
 	healthResponse := HealthResponse{
 		Status:    status,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -93,7 +110,7 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 		Checks:    checks,
 	}
 
-	if allHealthy {
+	if ready {
 		response.Success(c, healthResponse)
 	} else {
 		response.Error(c, nil) // This will return 500 with the health data
@@ -131,6 +148,7 @@ func (h *HealthHandler) Status(c *gin.Context) {
 	_ = ctx // Use ctx to avoid unused variable error
 
 	checks := make(map[string]interface{})
+	status := "operational"
 
 	// Database status
 	if err := h.db.HealthCheck(); err != nil {
@@ -138,6 +156,7 @@ func (h *HealthHandler) Status(c *gin.Context) {
 			"status": "unhealthy",
 			"error":  err.Error(),
 		}
+		status = "degraded"
 	} else {
 		dbStats := h.db.GetStats()
 		checks["database"] = map[string]interface{}{
@@ -146,6 +165,27 @@ func (h *HealthHandler) Status(c *gin.Context) {
 		}
 	}
 
+	// Degraded mode, while reads are answered from snapshots
+	if h.degraded != nil {
+		degradedStatus := h.degraded.DegradedStatus()
+		checks["snapshots"] = degradedStatus
+		if degradedStatus.Degraded {
+			status = "degraded"
+		}
+	}
+
+	// Menu cache status
+	if h.cacheStats != nil {
+		checks["cache"] = map[string]interface{}{
+			"status": "enabled",
+			"menu":   h.cacheStats.CacheStats(),
+		}
+	} else {
+		checks["cache"] = map[string]interface{}{
+			"status": "disabled",
+		}
+	}
+
 	// API status
 	checks["api"] = map[string]interface{}{
 		"status":     "healthy",
@@ -155,7 +195,7 @@ func (h *HealthHandler) Status(c *gin.Context) {
 	}
 
 	response.Success(c, HealthResponse{
-		Status:    "operational",
+		Status:    status,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Version:   "1.0.0",
 		Uptime:    time.Since(startTime).String(),
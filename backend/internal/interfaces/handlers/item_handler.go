@@ -16,6 +16,7 @@ import (
 type ItemHandler struct {
 	service           services.ItemService
 	subCategoryService services.SubCategoryService
+	versions          services.MenuVersionService
 	logger            *logger.Logger
 }
 
@@ -47,11 +48,28 @@ type UpdatePriceRequest struct {
 	Price float64 `json:"price" binding:"required,min=0"`
 }
 
+type ReorderItemsRequest struct {
+	SubCategoryID uint   `json:"sub_category_id" binding:"required"`
+	IDs           []uint `json:"ids" binding:"required,min=1"`
+}
+
+type BulkItemRequest struct {
+	Selector  entities.BulkItemSelector  `json:"selector"`
+	Operation entities.BulkItemOperation `json:"operation"`
+	DryRun    bool                       `json:"dry_run"`
+}
 
-func NewItemHandler(service services.ItemService, subCategoryService services.SubCategoryService, logger *logger.Logger) *ItemHandler {
+type AddItemPlacementRequest struct {
+	SubCategoryID uint `json:"sub_category_id" binding:"required"`
+	DisplayOrder  int  `json:"display_order" binding:"min=0"`
+}
+
+
+func NewItemHandler(service services.ItemService, subCategoryService services.SubCategoryService, versions services.MenuVersionService, logger *logger.Logger) *ItemHandler {
 	return &ItemHandler{
 		service:           service,
 		subCategoryService: subCategoryService,
+		versions:          versions,
 		logger:            logger,
 	}
 }
@@ -70,10 +88,16 @@ func NewItemHandler(service services.ItemService, subCategoryService services.Su
 // @Param search query string false "Search in name and description"
 // @Param limit query int false "Number of items to return"
 // @Param offset query int false "Number of items to skip"
-// @Param order_by query string false "Field to order by"
-// @Param order_dir query string false "Order direction (ASC/DESC)"
+// @Param sort query string false "Fields to sort by, comma-separated and descending with a leading minus, e.g. -price,name (name, price, available, display_order, created_at, updated_at)"
+// @Param order_by query string false "Field to order by (deprecated, use sort)"
+// @Param order_dir query string false "Order direction (ASC/DESC, deprecated, use sort)"
 // @Param include_count query boolean false "Include total count"
+// @Param fields query string false "Comma-separated fields to return (id, name, slug, description, price, currency, dietary_info, image_url, sub_category_id, available, display_order, created_at, updated_at)"
+// @Param expand query string false "Comma-separated relations to load (sub_category, sub_category.category, placements); sub_category and sub_category.category by default"
+// @Param cursor query string false "Keyset pagination in display order: empty for the first page, then meta.next_cursor; ignores offset and sort"
 // @Success 200 {array} entities.Item
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 400 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/items [get]
 func (h *ItemHandler) GetAll(c *gin.Context) {
@@ -83,12 +107,31 @@ func (h *ItemHandler) GetAll(c *gin.Context) {
 	filter := entities.ItemFilter{
 		Limit:        utils.ParseInt(c.Query("limit"), 10),
 		Offset:       utils.ParseInt(c.Query("offset"), 0),
-		OrderBy:      c.DefaultQuery("order_by", "display_order"),
-		OrderDir:     c.DefaultQuery("order_dir", "ASC"),
 		Search:       c.Query("search"),
 		IncludeCount: c.Query("include_count") == "true",
 	}
 
+	sort, err := querySort(c, entities.ItemSorts, "")
+	if err != nil {
+		response.ValidationError(c, "Invalid sort", err.Error())
+		return
+	}
+	filter.Sort = sort
+
+	selection, err := queryFieldSelection(c, entities.ItemFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+	filter.Expand = selection.expand
+
+	cursor, err := queryCursor(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid cursor", "Use the next_cursor of the previous page")
+		return
+	}
+	filter.Cursor = cursor
+
 	if subCategoryID := c.Query("sub_category_id"); subCategoryID != "" {
 		if id, err := strconv.ParseUint(subCategoryID, 10, 32); err == nil {
 			filter.SubCategoryID = utils.UintPtr(uint(id))
@@ -121,6 +164,10 @@ func (h *ItemHandler) GetAll(c *gin.Context) {
 		}
 	}
 
+	if notModified(c, h.versions) {
+		return
+	}
+
 	items, pagination, err := h.service.GetAll(ctx, filter)
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get items", nil)
@@ -128,10 +175,18 @@ func (h *ItemHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	if filter.IncludeCount && pagination != nil {
-		response.SuccessWithPagination(c, items, pagination)
+	data, err := selection.project(items)
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get items"))
+		return
+	}
+
+	if filter.Cursor != nil {
+		response.SuccessWithCursor(c, data, pagination)
+	} else if filter.IncludeCount && pagination != nil {
+		response.SuccessWithPagination(c, data, pagination)
 	} else {
-		response.Success(c, items)
+		response.Success(c, data)
 	}
 }
 
@@ -142,7 +197,10 @@ func (h *ItemHandler) GetAll(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Item ID"
+// @Param fields query string false "Comma-separated fields to return (id, name, slug, description, price, currency, dietary_info, image_url, sub_category_id, available, display_order, created_at, updated_at)"
+// @Param expand query string false "Comma-separated relations to load (sub_category, sub_category.category, placements); usual ones by default"
 // @Success 200 {object} entities.Item
+// @Success 304 "Not modified since the ETag or date sent"
 // @Failure 400 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
@@ -156,6 +214,16 @@ func (h *ItemHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	selection, err := queryFieldSelection(c, entities.ItemFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+
+	if notModified(c, h.versions) {
+		return
+	}
+
 	item, err := h.service.GetByID(ctx, uint(id))
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get item", map[string]interface{}{
@@ -170,7 +238,13 @@ func (h *ItemHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, item)
+	data, err := selection.project(item)
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get item"))
+		return
+	}
+
+	response.Success(c, data)
 }
 
 // CreateItem godoc
@@ -682,6 +756,7 @@ func (h *ItemHandler) Search(c *gin.Context) {
 // @Produce json
 // @Param limit query int false "Number of items to return (max 50, default 10)"
 // @Success 200 {array} entities.Item
+// @Success 304 "Not modified since the ETag or date sent"
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/items/featured [get]
 func (h *ItemHandler) GetFeatured(c *gin.Context) {
@@ -692,6 +767,10 @@ func (h *ItemHandler) GetFeatured(c *gin.Context) {
 		limit = 10
 	}
 
+	if notModified(c, h.versions) {
+		return
+	}
+
 	items, err := h.service.GetFeatured(ctx, limit)
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get featured items", map[string]interface{}{
@@ -702,4 +781,274 @@ func (h *ItemHandler) GetFeatured(c *gin.Context) {
 	}
 
 	response.Success(c, items)
-}
\ No newline at end of file
+}
+
+// ReorderItems godoc
+// @Summary Reorder items
+// @Description Replace the ordering of a subcategory's items with the given ID list. The list must contain every item of the subcategory exactly once and is applied atomically with consecutive display order values.
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Param order body ReorderItemsRequest true "Parent subcategory and ordered item IDs"
+// @Success 200 {array} entities.Item
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/items/reorder [put]
+func (h *ItemHandler) Reorder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req ReorderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	items, err := h.service.Reorder(ctx, req.SubCategoryID, req.IDs)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to reorder items", map[string]interface{}{
+			"sub_category_id": req.SubCategoryID,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, items)
+}
+
+// CloneItem godoc
+// @Summary Clone an item
+// @Description Duplicate an item, including dietary info and image reference, optionally into another subcategory
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param options body CloneRequest false "Name suffix and target subcategory ID"
+// @Success 201 {object} entities.Item
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/items/{id}/clone [post]
+func (h *ItemHandler) Clone(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID", "ID must be a positive integer")
+		return
+	}
+
+	var req CloneRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	// Validate target subcategory exists
+	if req.TargetParentID != nil {
+		subCategory, err := h.subCategoryService.GetByID(ctx, *req.TargetParentID)
+		if err != nil {
+			h.logger.LogError(ctx, err, "Failed to validate subcategory", map[string]interface{}{
+				"sub_category_id": *req.TargetParentID,
+			})
+			response.Error(c, appErrors.WrapInternalError(err, "Failed to validate subcategory"))
+			return
+		}
+
+		if subCategory == nil {
+			response.BadRequest(c, "Invalid subcategory ID", "SubCategory does not exist")
+			return
+		}
+	}
+
+	clone, err := h.service.Clone(ctx, uint(id), entities.CloneOptions{
+		NameSuffix:     req.NameSuffix,
+		TargetParentID: req.TargetParentID,
+	})
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to clone item", map[string]interface{}{
+			"item_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, clone)
+}
+
+// BulkItems godoc
+// @Summary Bulk update items
+// @Description Apply a price change, availability, subcategory move, currency change or deletion to all items matched by a selector in a single transaction. Use dry_run to preview the affected rows without writing.
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Param request body BulkItemRequest true "Selector, operation and dry-run flag"
+// @Success 200 {object} entities.BulkResult
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/items/bulk [post]
+func (h *ItemHandler) Bulk(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req BulkItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	// Validate target subcategory exists
+	if req.Operation.Type == entities.BulkOpMoveSubCategory && req.Operation.SubCategoryID != nil {
+		subCategory, err := h.subCategoryService.GetByID(ctx, *req.Operation.SubCategoryID)
+		if err != nil {
+			h.logger.LogError(ctx, err, "Failed to validate subcategory", map[string]interface{}{
+				"sub_category_id": *req.Operation.SubCategoryID,
+			})
+			response.Error(c, appErrors.WrapInternalError(err, "Failed to validate subcategory"))
+			return
+		}
+
+		if subCategory == nil {
+			response.BadRequest(c, "Invalid subcategory ID", "SubCategory does not exist")
+			return
+		}
+	}
+
+	result, err := h.service.BulkApply(ctx, req.Selector, req.Operation, req.DryRun)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to apply bulk item operation", map[string]interface{}{
+			"operation": req.Operation.Type,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetItemPlacements godoc
+// @Summary List item placements
+// @Description Get every subcategory an item is listed in, including its primary subcategory
+// @Tags Items
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {array} entities.ItemPlacement
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/items/{id}/placements [get]
+func (h *ItemHandler) GetPlacements(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID", "ID must be a positive integer")
+		return
+	}
+
+	placements, err := h.service.GetPlacements(ctx, uint(id))
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to get item placements", map[string]interface{}{
+			"item_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, placements)
+}
+
+// AddItemPlacement godoc
+// @Summary Place an item in a subcategory
+// @Description List an existing item in an additional subcategory. The display order defaults to the end of the subcategory.
+// @Tags Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param placement body AddItemPlacementRequest true "Subcategory and optional display order"
+// @Success 201 {object} entities.ItemPlacement
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/items/{id}/placements [post]
+func (h *ItemHandler) AddPlacement(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID", "ID must be a positive integer")
+		return
+	}
+
+	var req AddItemPlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	// Validate subcategory exists
+	subCategory, err := h.subCategoryService.GetByID(ctx, req.SubCategoryID)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to validate subcategory", map[string]interface{}{
+			"sub_category_id": req.SubCategoryID,
+		})
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to validate subcategory"))
+		return
+	}
+
+	if subCategory == nil {
+		response.BadRequest(c, "Invalid subcategory ID", "SubCategory does not exist")
+		return
+	}
+
+	placement, err := h.service.AddPlacement(ctx, uint(id), req.SubCategoryID, req.DisplayOrder)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to add item placement", map[string]interface{}{
+			"item_id":         id,
+			"sub_category_id": req.SubCategoryID,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, placement)
+}
+
+// RemoveItemPlacement godoc
+// @Summary Remove an item from a subcategory
+// @Description Stop listing an item in a secondary subcategory. An item cannot be removed from its primary subcategory.
+// @Tags Items
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param subCategoryId path int true "SubCategory ID"
+// @Success 204
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/items/{id}/placements/{subCategoryId} [delete]
+func (h *ItemHandler) RemovePlacement(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid item ID", "ID must be a positive integer")
+		return
+	}
+
+	subCategoryID, err := strconv.ParseUint(c.Param("subCategoryId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid subcategory ID", "ID must be a positive integer")
+		return
+	}
+
+	if err := h.service.RemovePlacement(ctx, uint(id), uint(subCategoryID)); err != nil {
+		h.logger.LogError(ctx, err, "Failed to remove item placement", map[string]interface{}{
+			"item_id":         id,
+			"sub_category_id": subCategoryID,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -1,22 +1,30 @@
 package handlers
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 
+	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/domain/services"
+	appErrors "restaurant-menu-api/pkg/errors"
 	"restaurant-menu-api/pkg/logger"
 	"restaurant-menu-api/pkg/response"
+	"restaurant-menu-api/pkg/utils"
 )
 
 type MenuHandler struct {
-	service services.MenuService
-	logger  *logger.Logger
+	service  services.MenuService
+	versions services.MenuVersionService
+	logger   *logger.Logger
 }
 
-func NewMenuHandler(service services.MenuService, logger *logger.Logger) *MenuHandler {
+func NewMenuHandler(service services.MenuService, versions services.MenuVersionService, logger *logger.Logger) *MenuHandler {
 	return &MenuHandler{
-		service: service,
-		logger:  logger,
+		service:  service,
+		versions: versions,
+		logger:   logger,
 	}
 }
 
@@ -26,25 +34,270 @@ func NewMenuHandler(service services.MenuService, logger *logger.Logger) *MenuHa
 // @Tags Menu
 // @Accept json
 // @Produce json
+// @Param fields query string false "Comma-separated item fields to return (id, name, slug, description, price, currency, dietary_info, image_url, sub_category_id, available, display_order, created_at, updated_at)"
+// @Param expand query string false "Comma-separated item relations to load (sub_category, sub_category.category, placements); sub_category and sub_category.category by default"
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} X-Stale "true when served from the last good snapshot while the database is unavailable"
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 400 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/menu [get]
 func (h *MenuHandler) GetCompleteMenu(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := services.WithStaleTracking(c.Request.Context())
+
+	selection, err := queryFieldSelection(c, entities.ItemFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+
+	if notModified(c, h.versions) {
+		return
+	}
 
-	menu, err := h.service.GetCompleteMenu(ctx)
+	menu, err := h.service.GetCompleteMenu(ctx, selection.expand)
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get complete menu", nil)
 		response.Error(c, err)
 		return
 	}
 
+	data, err := selection.project(menu, "categories", "sub_categories", "items")
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get menu"))
+		return
+	}
+
 	h.logger.LogInfo(ctx, "Complete menu retrieved successfully", map[string]interface{}{
 		"categories_count": len(menu.Categories),
 	})
 
+	setStaleHeaders(c, ctx)
 	response.Success(c, map[string]interface{}{
-		"menu":       menu,
+		"menu":       data,
 		"categories": len(menu.Categories),
 	})
-}
\ No newline at end of file
+}
+
+// Search searches the menu with facet counts for refining the results
+// @Summary Search the menu
+// @Description Search available menu items, falling back to close spellings, with counts per category, subcategory, dietary label, tag and price bucket. List parameters accept repeated or comma-separated values.
+// @Tags Menu
+// @Produce json
+// @Param q query string true "Search query"
+// @Param category_id query []int false "Only items in any of these categories" collectionFormat(csv)
+// @Param sub_category_id query []int false "Only items in any of these subcategories" collectionFormat(csv)
+// @Param dietary query []string false "Only items with every one of these dietary labels" collectionFormat(csv)
+// @Param tag query []string false "Only items with every one of these tags" collectionFormat(csv)
+// @Param price query []string false "Only items in any of these price buckets" collectionFormat(csv)
+// @Param min_price query number false "Minimum price filter"
+// @Param max_price query number false "Maximum price filter"
+// @Param limit query int false "Number of items to return (default 20)"
+// @Param offset query int false "Number of items to skip"
+// @Param locale query string false "Locale of the synonyms and stop words applied to the query (default en)"
+// @Success 200 {object} services.SearchResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/menu/search [get]
+func (h *MenuHandler) Search(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := c.Query("q")
+	if query == "" {
+		response.BadRequest(c, "Search query is required", "Provide 'q' parameter with search term")
+		return
+	}
+
+	filters := services.SearchFilters{
+		Available:     utils.BoolPtr(true),
+		MinPrice:      parseFloatPtr(c.Query("min_price")),
+		MaxPrice:      parseFloatPtr(c.Query("max_price")),
+		Limit:         utils.MinInt(utils.ParseInt(c.Query("limit"), 20), 50),
+		Offset:        utils.MaxInt(utils.ParseInt(c.Query("offset"), 0), 0),
+		DietaryLabels: queryList(c, "dietary"),
+		Tags:          queryList(c, "tag"),
+		PriceBuckets:  queryList(c, "price"),
+		Locale:        c.Query("locale"),
+	}
+
+	var err error
+	if filters.CategoryIDs, err = queryIDList(c, "category_id"); err != nil {
+		response.BadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+	if filters.SubCategoryIDs, err = queryIDList(c, "sub_category_id"); err != nil {
+		response.BadRequest(c, "Invalid subcategory ID", err.Error())
+		return
+	}
+
+	result, err := h.service.SearchMenuItems(ctx, query, filters)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to search menu", map[string]interface{}{
+			"search_query": query,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Suggest returns search-as-you-type suggestions
+// @Summary Suggest search terms
+// @Description Get item names, categories and popular queries starting with the typed text
+// @Tags Menu
+// @Produce json
+// @Param q query string true "Text typed so far"
+// @Param limit query int false "Suggestions per group (max 10, default 5)"
+// @Success 200 {object} services.SuggestResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/menu/suggest [get]
+func (h *MenuHandler) Suggest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := c.Query("q")
+	if query == "" {
+		response.BadRequest(c, "Search query is required", "Provide 'q' parameter with the text typed so far")
+		return
+	}
+
+	suggestions, err := h.service.Suggest(ctx, query, utils.ParseInt(c.Query("limit"), 5))
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to get search suggestions", map[string]interface{}{
+			"search_query": query,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, suggestions)
+}
+
+// parseFloatPtr parses an optional number, ignoring malformed input.
+func parseFloatPtr(s string) *float64 {
+	if s == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// GetMenuTree retrieves the menu with subcategories nested to any depth
+// @Summary Get menu tree
+// @Description Get all active categories with their subcategories nested to any depth, each with its available items
+// @Tags Menu
+// @Produce json
+// @Success 200 {object} services.MenuTreeResponse
+// @Header 200 {string} X-Stale "true when served from the last good snapshot while the database is unavailable"
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/menu/tree [get]
+func (h *MenuHandler) GetMenuTree(c *gin.Context) {
+	ctx := services.WithStaleTracking(c.Request.Context())
+
+	if notModified(c, h.versions) {
+		return
+	}
+
+	tree, err := h.service.GetMenuTree(ctx)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to get menu tree", nil)
+		response.Error(c, err)
+		return
+	}
+
+	setStaleHeaders(c, ctx)
+	response.Success(c, tree)
+}
+
+// GetCategoryTree retrieves one category's subtree
+// @Summary Get category tree
+// @Description Get a category with its active subcategories nested to any depth, each with its items
+// @Tags Menu
+// @Produce json
+// @Param categoryId path int true "Category ID"
+// @Success 200 {object} services.MenuTreeCategory
+// @Header 200 {string} X-Stale "true when served from the last good snapshot while the database is unavailable"
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/menu/tree/{categoryId} [get]
+func (h *MenuHandler) GetCategoryTree(c *gin.Context) {
+	ctx := services.WithStaleTracking(c.Request.Context())
+
+	categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID", "ID must be a positive integer")
+		return
+	}
+
+	if notModified(c, h.versions) {
+		return
+	}
+
+	tree, err := h.service.GetCategoryTree(ctx, uint(categoryID))
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to get category tree", map[string]interface{}{
+			"category_id": categoryID,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	setStaleHeaders(c, ctx)
+	response.Success(c, tree)
+}
+
+// GetBySlug resolves a public slug path to a category, subcategory or item
+// @Summary Get menu section by slug
+// @Description Get a category menu, a subcategory with its available items, or a single item by slug. Outdated slugs from before a rename or move answer with a 301 redirect to the current path.
+// @Tags Menu
+// @Produce json
+// @Param categorySlug path string true "Category slug"
+// @Param subSlug path string false "SubCategory slug"
+// @Param itemSlug path string false "Item slug"
+// @Success 200 {object} services.MenuSlugResponse
+// @Success 301
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/menu/{categorySlug}/{subSlug}/{itemSlug} [get]
+func (h *MenuHandler) GetBySlug(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	path := services.MenuSlugPath{
+		CategorySlug:    c.Param("categorySlug"),
+		SubCategorySlug: c.Param("subSlug"),
+		ItemSlug:        c.Param("itemSlug"),
+	}
+
+	if notModified(c, h.versions) {
+		return
+	}
+
+	result, err := h.service.GetMenuBySlug(ctx, path)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to resolve menu slug", map[string]interface{}{
+			"path": path.String(),
+		})
+		response.Error(c, err)
+		return
+	}
+
+	if result.Canonical != nil {
+		location := "/api/v1/menu/" + result.Canonical.String()
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/entities"
+)
+
+type CloneRequest struct {
+	NameSuffix     *string `json:"name_suffix"`
+	TargetParentID *uint   `json:"target_parent_id"`
+}
+
+// bindOptionalJSON binds the request body into obj, treating an empty body as
+// a request that relies on defaults.
+func bindOptionalJSON(c *gin.Context, obj interface{}) error {
+	if err := c.ShouldBindJSON(obj); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// queryList reads a list query parameter given repeated (?tag=a&tag=b),
+// comma-separated (?tag=a,b) or both.
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// queryIDList reads a list query parameter of IDs.
+func queryIDList(c *gin.Context, key string) ([]uint, error) {
+	values := queryList(c, key)
+	ids := make([]uint, 0, len(values))
+	for _, value := range values {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			return nil, errors.New(key + " must be a list of positive integers")
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// queryCursor reads the cursor parameter selecting keyset pagination. The
+// cursor is nil when the parameter is absent; an empty ?cursor= starts from
+// the first page.
+func queryCursor(c *gin.Context) (*entities.Cursor, error) {
+	token, ok := c.GetQuery("cursor")
+	if !ok {
+		return nil, nil
+	}
+	return entities.DecodeCursor(token)
+}
+
+// querySort reads a sort such as ?sort=-price,name against the fields the
+// registry allows. The older ?order_by=&order_dir= pair is still accepted, and
+// def applies when neither is given.
+func querySort(c *gin.Context, registry entities.SortRegistry, def string) (entities.Sort, error) {
+	param := c.Query("sort")
+	if param == "" {
+		if orderBy := c.Query("order_by"); orderBy != "" {
+			param = orderBy
+			if strings.EqualFold(c.Query("order_dir"), "DESC") {
+				param = "-" + orderBy
+			}
+		} else {
+			param = def
+		}
+	}
+	return registry.Parse(param)
+}
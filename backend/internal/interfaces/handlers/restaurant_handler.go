@@ -48,10 +48,11 @@ func NewRestaurantHandler(service services.RestaurantService, logger *logger.Log
 // @Accept json
 // @Produce json
 // @Success 200 {object} entities.RestaurantInfo
+// @Header 200 {string} X-Stale "true when served from the last good snapshot while the database is unavailable"
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/restaurants/info [get]
 func (h *RestaurantHandler) GetInfo(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := services.WithStaleTracking(c.Request.Context())
 
 	restaurant, err := h.service.GetInfo(ctx)
 	if err != nil {
@@ -60,6 +61,7 @@ func (h *RestaurantHandler) GetInfo(c *gin.Context) {
 		return
 	}
 
+	setStaleHeaders(c, ctx)
 	response.Success(c, restaurant)
 }
 
@@ -70,10 +72,11 @@ func (h *RestaurantHandler) GetInfo(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} entities.OperatingHour
+// @Header 200 {string} X-Stale "true when served from the last good snapshot while the database is unavailable"
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/restaurants/hours [get]
 func (h *RestaurantHandler) GetOperatingHours(c *gin.Context) {
-	ctx := c.Request.Context()
+	ctx := services.WithStaleTracking(c.Request.Context())
 
 	hours, err := h.service.GetOperatingHours(ctx)
 	if err != nil {
@@ -82,6 +85,7 @@ func (h *RestaurantHandler) GetOperatingHours(c *gin.Context) {
 		return
 	}
 
+	setStaleHeaders(c, ctx)
 	response.Success(c, hours)
 }
 
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/services"
+	"restaurant-menu-api/pkg/logger"
+	"restaurant-menu-api/pkg/response"
+	"restaurant-menu-api/pkg/utils"
+)
+
+type SearchAnalyticsHandler struct {
+	service services.SearchAnalyticsService
+	logger  *logger.Logger
+}
+
+type RecordSearchClickRequest struct {
+	ItemID   uint `json:"item_id" binding:"required"`
+	Position int  `json:"position" binding:"min=0"`
+}
+
+func NewSearchAnalyticsHandler(service services.SearchAnalyticsService, logger *logger.Logger) *SearchAnalyticsHandler {
+	return &SearchAnalyticsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RecordClick godoc
+// @Summary Record a search result click
+// @Description Record that a guest opened an item from the results of a menu search
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param searchId path int true "Search ID returned by the menu search"
+// @Param click body RecordSearchClickRequest true "Clicked item and its position in the results"
+// @Success 204
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/menu/search/{searchId}/clicks [post]
+func (h *SearchAnalyticsHandler) RecordClick(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	searchID, err := strconv.ParseUint(c.Param("searchId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid search ID", "ID must be a positive integer")
+		return
+	}
+
+	var req RecordSearchClickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := h.service.RecordClick(ctx, uint(searchID), req.ItemID, req.Position); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// TopQueries godoc
+// @Summary Get top search queries
+// @Description Get the most frequent menu search queries with their result and click-through counts
+// @Tags Search Analytics
+// @Produce json
+// @Param from query string false "Start of the period, RFC 3339 or YYYY-MM-DD (default 30 days before to)"
+// @Param to query string false "End of the period, RFC 3339 or YYYY-MM-DD inclusive (default now)"
+// @Param limit query int false "Number of queries to return (max 100, default 20)"
+// @Success 200 {array} entities.SearchQueryReport
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/analytics/search/top-queries [get]
+func (h *SearchAnalyticsHandler) TopQueries(c *gin.Context) {
+	filter, ok := h.parseReportFilter(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.service.TopQueries(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, reports)
+}
+
+// ZeroResultQueries godoc
+// @Summary Get zero-result search queries
+// @Description Get the most frequent menu search queries that found nothing
+// @Tags Search Analytics
+// @Produce json
+// @Param from query string false "Start of the period, RFC 3339 or YYYY-MM-DD (default 30 days before to)"
+// @Param to query string false "End of the period, RFC 3339 or YYYY-MM-DD inclusive (default now)"
+// @Param limit query int false "Number of queries to return (max 100, default 20)"
+// @Success 200 {array} entities.SearchQueryReport
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/analytics/search/zero-results [get]
+func (h *SearchAnalyticsHandler) ZeroResultQueries(c *gin.Context) {
+	filter, ok := h.parseReportFilter(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.service.ZeroResultQueries(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, reports)
+}
+
+// ClickThrough godoc
+// @Summary Get search click-through
+// @Description Get the share of menu searches that led to an item being opened, and the items opened most
+// @Tags Search Analytics
+// @Produce json
+// @Param from query string false "Start of the period, RFC 3339 or YYYY-MM-DD (default 30 days before to)"
+// @Param to query string false "End of the period, RFC 3339 or YYYY-MM-DD inclusive (default now)"
+// @Param limit query int false "Number of items to return (max 100, default 20)"
+// @Success 200 {object} entities.ClickThroughReport
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/analytics/search/click-through [get]
+func (h *SearchAnalyticsHandler) ClickThrough(c *gin.Context) {
+	filter, ok := h.parseReportFilter(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ClickThrough(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// parseReportFilter reads the report period and limit, writing a 400
+// response when a date is malformed.
+func (h *SearchAnalyticsHandler) parseReportFilter(c *gin.Context) (entities.SearchReportFilter, bool) {
+	filter := entities.SearchReportFilter{
+		Limit: utils.ParseInt(c.Query("limit"), 0),
+	}
+
+	var err error
+	if filter.From, err = parseReportTime(c.Query("from"), false); err != nil {
+		response.BadRequest(c, "Invalid from date", "Use RFC 3339 or YYYY-MM-DD")
+		return filter, false
+	}
+	if filter.To, err = parseReportTime(c.Query("to"), true); err != nil {
+		response.BadRequest(c, "Invalid to date", "Use RFC 3339 or YYYY-MM-DD")
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// parseReportTime parses an RFC 3339 time or a date. A date used as the end of
+// a period includes the whole day.
+func parseReportTime(value string, endOfPeriod bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfPeriod {
+		day = day.Add(24 * time.Hour)
+	}
+	return day, nil
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/services"
+	"restaurant-menu-api/pkg/logger"
+	"restaurant-menu-api/pkg/response"
+)
+
+type SearchDictionaryHandler struct {
+	service services.SearchDictionaryService
+	logger  *logger.Logger
+}
+
+type SearchSynonymRequest struct {
+	Locale string   `json:"locale" binding:"omitempty,max=10"`
+	Terms  []string `json:"terms" binding:"required,min=2,dive,required,max=100"`
+}
+
+type CreateSearchStopWordRequest struct {
+	Locale string `json:"locale" binding:"omitempty,max=10"`
+	Word   string `json:"word" binding:"required,max=50"`
+}
+
+func NewSearchDictionaryHandler(service services.SearchDictionaryService, logger *logger.Logger) *SearchDictionaryHandler {
+	return &SearchDictionaryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetSynonyms godoc
+// @Summary Get search synonyms
+// @Description Get the synonym groups applied to menu searches
+// @Tags Search Dictionary
+// @Produce json
+// @Param locale query string false "Only synonyms of this locale"
+// @Success 200 {array} entities.SearchSynonym
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/search/synonyms [get]
+func (h *SearchDictionaryHandler) GetSynonyms(c *gin.Context) {
+	synonyms, err := h.service.GetSynonyms(c.Request.Context(), c.Query("locale"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, synonyms)
+}
+
+// CreateSynonym godoc
+// @Summary Create a search synonym group
+// @Description Create a group of interchangeable search terms; searches take effect immediately
+// @Tags Search Dictionary
+// @Accept json
+// @Produce json
+// @Param synonym body SearchSynonymRequest true "Locale (default en) and terms"
+// @Success 201 {object} entities.SearchSynonym
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/search/synonyms [post]
+func (h *SearchDictionaryHandler) CreateSynonym(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req SearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	synonym := &entities.SearchSynonym{
+		Locale: req.Locale,
+		Terms:  req.Terms,
+	}
+	if err := h.service.CreateSynonym(ctx, synonym); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "Search synonym created successfully", map[string]interface{}{
+		"synonym_id": synonym.ID,
+		"locale":     synonym.Locale,
+	})
+
+	response.Created(c, synonym)
+}
+
+// UpdateSynonym godoc
+// @Summary Update a search synonym group
+// @Description Replace the terms of a synonym group
+// @Tags Search Dictionary
+// @Accept json
+// @Produce json
+// @Param id path int true "Synonym ID"
+// @Param synonym body SearchSynonymRequest true "Locale (unchanged when empty) and terms"
+// @Success 200 {object} entities.SearchSynonym
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/search/synonyms/{id} [put]
+func (h *SearchDictionaryHandler) UpdateSynonym(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid synonym ID", "ID must be a positive integer")
+		return
+	}
+
+	var req SearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	synonym, err := h.service.UpdateSynonym(ctx, uint(id), &entities.SearchSynonym{
+		Locale: req.Locale,
+		Terms:  req.Terms,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "Search synonym updated successfully", map[string]interface{}{
+		"synonym_id": synonym.ID,
+		"locale":     synonym.Locale,
+	})
+
+	response.Success(c, synonym)
+}
+
+// DeleteSynonym godoc
+// @Summary Delete a search synonym group
+// @Tags Search Dictionary
+// @Param id path int true "Synonym ID"
+// @Success 204
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/search/synonyms/{id} [delete]
+func (h *SearchDictionaryHandler) DeleteSynonym(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid synonym ID", "ID must be a positive integer")
+		return
+	}
+
+	if err := h.service.DeleteSynonym(ctx, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "Search synonym deleted successfully", map[string]interface{}{
+		"synonym_id": id,
+	})
+
+	response.NoContent(c)
+}
+
+// GetStopWords godoc
+// @Summary Get search stop words
+// @Description Get the words ignored in menu search queries
+// @Tags Search Dictionary
+// @Produce json
+// @Param locale query string false "Only stop words of this locale"
+// @Success 200 {array} entities.SearchStopWord
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/search/stop-words [get]
+func (h *SearchDictionaryHandler) GetStopWords(c *gin.Context) {
+	stopWords, err := h.service.GetStopWords(c.Request.Context(), c.Query("locale"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, stopWords)
+}
+
+// CreateStopWord godoc
+// @Summary Create a search stop word
+// @Description Add a word ignored in menu search queries; searches take effect immediately
+// @Tags Search Dictionary
+// @Accept json
+// @Produce json
+// @Param stopWord body CreateSearchStopWordRequest true "Locale (default en) and word"
+// @Success 201 {object} entities.SearchStopWord
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/search/stop-words [post]
+func (h *SearchDictionaryHandler) CreateStopWord(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateSearchStopWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	stopWord := &entities.SearchStopWord{
+		Locale: req.Locale,
+		Word:   req.Word,
+	}
+	if err := h.service.CreateStopWord(ctx, stopWord); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "Search stop word created successfully", map[string]interface{}{
+		"stop_word_id": stopWord.ID,
+		"locale":       stopWord.Locale,
+		"word":         stopWord.Word,
+	})
+
+	response.Created(c, stopWord)
+}
+
+// DeleteStopWord godoc
+// @Summary Delete a search stop word
+// @Tags Search Dictionary
+// @Param id path int true "Stop word ID"
+// @Success 204
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security AdminAPIKey
+// @Router /api/v1/search/stop-words/{id} [delete]
+func (h *SearchDictionaryHandler) DeleteStopWord(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid stop word ID", "ID must be a positive integer")
+		return
+	}
+
+	if err := h.service.DeleteStopWord(ctx, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.logger.LogInfo(ctx, "Search stop word deleted successfully", map[string]interface{}{
+		"stop_word_id": id,
+	})
+
+	response.NoContent(c)
+}
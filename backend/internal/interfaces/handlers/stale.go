@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/services"
+)
+
+// setStaleHeaders flags a response answered from a snapshot because the
+// database was unavailable, and keeps clients from storing it.
+func setStaleHeaders(c *gin.Context, ctx context.Context) {
+	savedAt, stale := services.ServedStale(ctx)
+	if !stale {
+		return
+	}
+
+	// The validators describe the live data, not the snapshot
+	c.Writer.Header().Del("ETag")
+	c.Writer.Header().Del("Last-Modified")
+
+	c.Header("Warning", `110 - "Response is Stale"`)
+	c.Header("X-Stale", "true")
+	c.Header("X-Stale-Since", savedAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-store")
+}
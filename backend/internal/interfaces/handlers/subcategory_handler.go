@@ -16,6 +16,7 @@ import (
 type SubCategoryHandler struct {
 	service        services.SubCategoryService
 	categoryService services.CategoryService
+	versions       services.MenuVersionService
 	logger         *logger.Logger
 }
 
@@ -23,10 +24,22 @@ type CreateSubCategoryRequest struct {
 	Name         string `json:"name" binding:"required,min=1,max=100"`
 	Description  string `json:"description"`
 	CategoryID   uint   `json:"category_id" binding:"required"`
+	ParentID     *uint  `json:"parent_id"`
 	DisplayOrder int    `json:"display_order"`
 	Active       *bool  `json:"active"`
 }
 
+type ReorderSubCategoriesRequest struct {
+	CategoryID uint   `json:"category_id" binding:"required"`
+	ParentID   *uint  `json:"parent_id"`
+	IDs        []uint `json:"ids" binding:"required,min=1"`
+}
+
+type MoveSubCategoryRequest struct {
+	CategoryID uint  `json:"category_id"`
+	ParentID   *uint `json:"parent_id"`
+}
+
 type UpdateSubCategoryRequest struct {
 	Name         string `json:"name" binding:"required,min=1,max=100"`
 	Description  string `json:"description"`
@@ -36,10 +49,11 @@ type UpdateSubCategoryRequest struct {
 }
 
 
-func NewSubCategoryHandler(service services.SubCategoryService, categoryService services.CategoryService, logger *logger.Logger) *SubCategoryHandler {
+func NewSubCategoryHandler(service services.SubCategoryService, categoryService services.CategoryService, versions services.MenuVersionService, logger *logger.Logger) *SubCategoryHandler {
 	return &SubCategoryHandler{
 		service:        service,
 		categoryService: categoryService,
+		versions:       versions,
 		logger:         logger,
 	}
 }
@@ -51,14 +65,22 @@ func NewSubCategoryHandler(service services.SubCategoryService, categoryService
 // @Accept json
 // @Produce json
 // @Param category_id query int false "Filter by category ID"
+// @Param parent_id query int false "Filter by parent subcategory ID"
+// @Param top_level query boolean false "Only subcategories directly below their category"
 // @Param active query boolean false "Filter by active status"
 // @Param search query string false "Search in name and description"
 // @Param limit query int false "Number of items to return"
 // @Param offset query int false "Number of items to skip"
-// @Param order_by query string false "Field to order by"
-// @Param order_dir query string false "Order direction (ASC/DESC)"
+// @Param sort query string false "Fields to sort by, comma-separated and descending with a leading minus, e.g. -created_at,name (name, display_order, created_at, updated_at)"
+// @Param order_by query string false "Field to order by (deprecated, use sort)"
+// @Param order_dir query string false "Order direction (ASC/DESC, deprecated, use sort)"
 // @Param include_count query boolean false "Include total count"
+// @Param fields query string false "Comma-separated fields to return (id, name, description, slug, category_id, parent_id, path, depth, display_order, active, created_at, updated_at)"
+// @Param expand query string false "Comma-separated relations to load (category, items); category by default"
+// @Param cursor query string false "Keyset pagination in display order: empty for the first page, then meta.next_cursor; ignores offset and sort"
 // @Success 200 {array} entities.SubCategory
+// @Success 304 "Not modified since the ETag or date sent"
+// @Failure 400 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /api/v1/subcategories [get]
 func (h *SubCategoryHandler) GetAll(c *gin.Context) {
@@ -68,18 +90,44 @@ func (h *SubCategoryHandler) GetAll(c *gin.Context) {
 	filter := entities.SubCategoryFilter{
 		Limit:        utils.ParseInt(c.Query("limit"), 10),
 		Offset:       utils.ParseInt(c.Query("offset"), 0),
-		OrderBy:      c.DefaultQuery("order_by", "display_order"),
-		OrderDir:     c.DefaultQuery("order_dir", "ASC"),
 		Search:       c.Query("search"),
+		TopLevel:     c.Query("top_level") == "true",
 		IncludeCount: c.Query("include_count") == "true",
 	}
 
+	sort, err := querySort(c, entities.SubCategorySorts, "")
+	if err != nil {
+		response.ValidationError(c, "Invalid sort", err.Error())
+		return
+	}
+	filter.Sort = sort
+
+	selection, err := queryFieldSelection(c, entities.SubCategoryFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+	filter.Expand = selection.expand
+
+	cursor, err := queryCursor(c)
+	if err != nil {
+		response.BadRequest(c, "Invalid cursor", "Use the next_cursor of the previous page")
+		return
+	}
+	filter.Cursor = cursor
+
 	if categoryID := c.Query("category_id"); categoryID != "" {
 		if id, err := strconv.ParseUint(categoryID, 10, 32); err == nil {
 			filter.CategoryID = utils.UintPtr(uint(id))
 		}
 	}
 
+	if parentID := c.Query("parent_id"); parentID != "" {
+		if id, err := strconv.ParseUint(parentID, 10, 32); err == nil {
+			filter.ParentID = utils.UintPtr(uint(id))
+		}
+	}
+
 	if active := c.Query("active"); active != "" {
 		if active == "true" {
 			filter.Active = utils.BoolPtr(true)
@@ -88,6 +136,10 @@ func (h *SubCategoryHandler) GetAll(c *gin.Context) {
 		}
 	}
 
+	if notModified(c, h.versions) {
+		return
+	}
+
 	subcategories, pagination, err := h.service.GetAll(ctx, filter)
 	if err != nil {
 		h.logger.LogError(ctx, err, "Failed to get subcategories", nil)
@@ -95,10 +147,18 @@ func (h *SubCategoryHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	if filter.IncludeCount && pagination != nil {
-		response.SuccessWithPagination(c, subcategories, pagination)
+	data, err := selection.project(subcategories)
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get subcategories"))
+		return
+	}
+
+	if filter.Cursor != nil {
+		response.SuccessWithCursor(c, data, pagination)
+	} else if filter.IncludeCount && pagination != nil {
+		response.SuccessWithPagination(c, data, pagination)
 	} else {
-		response.Success(c, subcategories)
+		response.Success(c, data)
 	}
 }
 
@@ -110,7 +170,10 @@ func (h *SubCategoryHandler) GetAll(c *gin.Context) {
 // @Produce json
 // @Param id path int true "SubCategory ID"
 // @Param include_items query boolean false "Include items in response"
+// @Param fields query string false "Comma-separated fields to return (id, name, description, slug, category_id, parent_id, path, depth, display_order, active, created_at, updated_at)"
+// @Param expand query string false "Comma-separated relations to load (category, items); usual ones by default"
 // @Success 200 {object} entities.SubCategory
+// @Success 304 "Not modified since the ETag or date sent"
 // @Failure 400 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
@@ -124,12 +187,22 @@ func (h *SubCategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	selection, err := queryFieldSelection(c, entities.SubCategoryFields)
+	if err != nil {
+		response.ValidationError(c, "Invalid field selection", err.Error())
+		return
+	}
+
 	// Check if we should include items
-	includeItems := c.Query("include_items") == "true"
+	includeItems := c.Query("include_items") == "true" || selection.expand.Has("items")
+
+	if notModified(c, h.versions) {
+		return
+	}
 
 	var subcategory *entities.SubCategory
 	if includeItems {
-		subcategory, err = h.service.GetByID(ctx, uint(id))
+		subcategory, err = h.service.GetWithItems(ctx, uint(id))
 	} else {
 		subcategory, err = h.service.GetByID(ctx, uint(id))
 	}
@@ -147,7 +220,13 @@ func (h *SubCategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, subcategory)
+	data, err := selection.project(subcategory)
+	if err != nil {
+		response.Error(c, appErrors.WrapInternalError(err, "Failed to get subcategory"))
+		return
+	}
+
+	response.Success(c, data)
 }
 
 // CreateSubCategory godoc
@@ -186,10 +265,28 @@ func (h *SubCategoryHandler) Create(c *gin.Context) {
 		return
 	}
 
+	// Validate parent subcategory exists within the category
+	if req.ParentID != nil {
+		parent, err := h.service.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			h.logger.LogError(ctx, err, "Failed to validate parent subcategory", map[string]interface{}{
+				"parent_id": *req.ParentID,
+			})
+			response.Error(c, appErrors.WrapInternalError(err, "Failed to validate parent subcategory"))
+			return
+		}
+
+		if parent == nil || parent.CategoryID != req.CategoryID {
+			response.BadRequest(c, "Invalid parent ID", "Parent subcategory does not exist in this category")
+			return
+		}
+	}
+
 	subcategory := &entities.SubCategory{
 		Name:         req.Name,
 		Description:  req.Description,
 		CategoryID:   req.CategoryID,
+		ParentID:     req.ParentID,
 		DisplayOrder: req.DisplayOrder,
 		Active:       true,
 	}
@@ -485,3 +582,206 @@ func (h *SubCategoryHandler) UpdateDisplayOrder(c *gin.Context) {
 	response.Success(c, updatedSubCategory)
 }
 
+// ReorderSubCategories godoc
+// @Summary Reorder subcategories
+// @Description Replace the ordering of the subcategories directly below a category, or below parent_id when given, with the given ID list. The list must contain every such subcategory exactly once and is applied atomically with consecutive display order values.
+// @Tags SubCategories
+// @Accept json
+// @Produce json
+// @Param order body ReorderSubCategoriesRequest true "Parent category and ordered subcategory IDs"
+// @Success 200 {array} entities.SubCategory
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/subcategories/reorder [put]
+func (h *SubCategoryHandler) Reorder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req ReorderSubCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	subcategories, err := h.service.Reorder(ctx, req.CategoryID, req.ParentID, req.IDs)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to reorder subcategories", map[string]interface{}{
+			"category_id": req.CategoryID,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, subcategories)
+}
+
+// CloneSubCategory godoc
+// @Summary Clone a subcategory
+// @Description Duplicate a subcategory together with its items in one transaction, optionally into another category
+// @Tags SubCategories
+// @Accept json
+// @Produce json
+// @Param id path int true "SubCategory ID"
+// @Param options body CloneRequest false "Name suffix and target category ID"
+// @Success 201 {object} entities.SubCategory
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/subcategories/{id}/clone [post]
+func (h *SubCategoryHandler) Clone(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid subcategory ID", "ID must be a positive integer")
+		return
+	}
+
+	var req CloneRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	// Validate target category exists
+	if req.TargetParentID != nil {
+		if _, err := h.categoryService.GetByID(ctx, *req.TargetParentID); err != nil {
+			response.Error(c, err)
+			return
+		}
+	}
+
+	clone, err := h.service.Clone(ctx, uint(id), entities.CloneOptions{
+		NameSuffix:     req.NameSuffix,
+		TargetParentID: req.TargetParentID,
+	})
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to clone subcategory", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, clone)
+}
+
+// GetSubCategoryTree godoc
+// @Summary Get a subcategory subtree
+// @Description Get a subcategory with all subcategories nested below it in children
+// @Tags SubCategories
+// @Produce json
+// @Param id path int true "SubCategory ID"
+// @Success 200 {object} entities.SubCategory
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/subcategories/{id}/tree [get]
+func (h *SubCategoryHandler) GetSubtree(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid subcategory ID", "ID must be a positive integer")
+		return
+	}
+
+	subtree, err := h.service.GetSubtree(ctx, uint(id))
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to get subcategory subtree", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, subtree)
+}
+
+// GetSubCategoryBreadcrumbs godoc
+// @Summary Get subcategory breadcrumbs
+// @Description Get the trail from the category down to the subcategory
+// @Tags SubCategories
+// @Produce json
+// @Param id path int true "SubCategory ID"
+// @Success 200 {array} entities.Breadcrumb
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/subcategories/{id}/breadcrumbs [get]
+func (h *SubCategoryHandler) GetBreadcrumbs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid subcategory ID", "ID must be a positive integer")
+		return
+	}
+
+	breadcrumbs, err := h.service.GetBreadcrumbs(ctx, uint(id))
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to get subcategory breadcrumbs", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, breadcrumbs)
+}
+
+// MoveSubCategory godoc
+// @Summary Move a subcategory
+// @Description Move a subcategory, with everything nested below it, under another subcategory (parent_id) or to the top level of a category (category_id). It is placed after its new siblings.
+// @Tags SubCategories
+// @Accept json
+// @Produce json
+// @Param id path int true "SubCategory ID"
+// @Param target body MoveSubCategoryRequest true "New parent subcategory or category"
+// @Success 200 {object} entities.SubCategory
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /api/v1/subcategories/{id}/move [put]
+func (h *SubCategoryHandler) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid subcategory ID", "ID must be a positive integer")
+		return
+	}
+
+	var req MoveSubCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, "Invalid request data", err.Error())
+		return
+	}
+
+	// Validate category exists
+	if req.CategoryID != 0 {
+		category, err := h.categoryService.GetByID(ctx, req.CategoryID)
+		if err != nil {
+			h.logger.LogError(ctx, err, "Failed to validate category", map[string]interface{}{
+				"category_id": req.CategoryID,
+			})
+			response.Error(c, appErrors.WrapInternalError(err, "Failed to validate category"))
+			return
+		}
+
+		if category == nil {
+			response.BadRequest(c, "Invalid category ID", "Category does not exist")
+			return
+		}
+	}
+
+	subcategory, err := h.service.Move(ctx, uint(id), req.CategoryID, req.ParentID)
+	if err != nil {
+		h.logger.LogError(ctx, err, "Failed to move subcategory", map[string]interface{}{
+			"subcategory_id": id,
+		})
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, subcategory)
+}
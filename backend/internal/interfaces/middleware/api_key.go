@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/services"
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+	"restaurant-menu-api/pkg/response"
+)
+
+const (
+	// APIKeyHeader carries the API key of a request
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyContextKey = "api_key"
+)
+
+// menuScopeRoutes are the route prefixes a menu-scoped key may read.
+var menuScopeRoutes = []string{
+	"/api/v1/status",
+	"/api/v1/menu",
+	"/api/v1/restaurants/info",
+	"/api/v1/restaurants/hours",
+}
+
+// menuScopeWrites are the routes a menu-scoped key may post to: recording
+// which search result a guest opened.
+var menuScopeWrites = []string{
+	"/api/v1/menu/search/:searchId/clicks",
+}
+
+// APIKeyAuth identifies requests sent with an API key, refusing keys that are
+// unknown, revoked, used from an origin they do not allow or outside their
+// scope, and counts each request towards the key's usage. Requests without a
+// key go ahead as before.
+func APIKeyAuth(apiKeyService services.APIKeyService, log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := strings.TrimSpace(c.GetHeader(APIKeyHeader))
+		if secret == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		apiKey, err := apiKeyService.Authenticate(ctx, secret)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		if !apiKey.AllowsOrigin(c.GetHeader("Origin")) {
+			log.LogWarning(ctx, "API key used from a disallowed origin", map[string]interface{}{
+				"api_key_id": apiKey.ID,
+				"origin":     c.GetHeader("Origin"),
+			})
+			response.Error(c, appErrors.NewForbiddenError("API key is not allowed from this origin"))
+			c.Abort()
+			return
+		}
+
+		route := c.FullPath()
+		if !scopeAllows(apiKey.Scope, c.Request.Method, route) {
+			response.Error(c, appErrors.NewForbiddenError("API key scope does not allow this request"))
+			c.Abort()
+			return
+		}
+
+		c.Set(apiKeyContextKey, apiKey)
+
+		c.Next()
+
+		if route == "" {
+			route = "unmatched"
+		}
+		apiKeyService.RecordUsage(ctx, apiKey.ID, c.Request.Method, route)
+	}
+}
+
+// RequireAdminAPIKey refuses requests not sent with an admin-scoped key. It
+// runs after APIKeyAuth, which has already refused unknown and revoked keys.
+func RequireAdminAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := APIKeyFromContext(c)
+		if apiKey == nil {
+			response.Error(c, appErrors.NewUnauthorizedError("An admin API key is required"))
+			c.Abort()
+			return
+		}
+		if apiKey.Scope != entities.APIKeyScopeAdmin {
+			response.Error(c, appErrors.NewForbiddenError("API key scope does not allow this request"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// APIKeyFromContext returns the key the request was sent with, or nil.
+func APIKeyFromContext(c *gin.Context) *entities.APIKey {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil
+	}
+	apiKey, _ := value.(*entities.APIKey)
+	return apiKey
+}
+
+// scopeAllows reports whether a key with scope may make a request to route.
+// Admin keys may make any request; menu keys only read the public menu and
+// restaurant information, and record search clicks.
+func scopeAllows(scope entities.APIKeyScope, method, route string) bool {
+	if scope == entities.APIKeyScopeAdmin {
+		return true
+	}
+
+	if method == http.MethodPost {
+		for _, write := range menuScopeWrites {
+			if route == write {
+				return true
+			}
+		}
+		return false
+	}
+
+	if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+		return false
+	}
+	for _, prefix := range menuScopeRoutes {
+		if route == prefix || strings.HasPrefix(route, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
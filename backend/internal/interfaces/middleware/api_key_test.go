@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"restaurant-menu-api/internal/config"
+	"restaurant-menu-api/internal/domain/entities"
+	"restaurant-menu-api/internal/domain/services"
+	appErrors "restaurant-menu-api/pkg/errors"
+	"restaurant-menu-api/pkg/logger"
+)
+
+// fakeAPIKeyService knows a fixed set of keys and records the usage counted
+// for them. Methods the middleware should not need panic through the nil
+// embedded interface.
+type fakeAPIKeyService struct {
+	services.APIKeyService
+	keys map[string]*entities.APIKey
+
+	mu    sync.Mutex
+	usage map[uint][]string
+}
+
+func (s *fakeAPIKeyService) Authenticate(ctx context.Context, secret string) (*entities.APIKey, error) {
+	key, ok := s.keys[secret]
+	if !ok {
+		return nil, appErrors.NewUnauthorizedError("Invalid API key")
+	}
+	return key, nil
+}
+
+func (s *fakeAPIKeyService) RecordUsage(ctx context.Context, keyID uint, method, route string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage[keyID] = append(s.usage[keyID], method+" "+route)
+}
+
+func newFakeAPIKeyService() *fakeAPIKeyService {
+	return &fakeAPIKeyService{
+		keys: map[string]*entities.APIKey{
+			"rmk_menu":   {ID: 1, Scope: entities.APIKeyScopeMenu},
+			"rmk_admin":  {ID: 2, Scope: entities.APIKeyScopeAdmin},
+			"rmk_origin": {ID: 3, Scope: entities.APIKeyScopeMenu, AllowedOrigins: entities.StringList{"https://menu.example.com"}},
+			"rmk_burst":  {ID: 4, Scope: entities.APIKeyScopeMenu, RateLimit: 2},
+			"rmk_quota":  {ID: 5, Scope: entities.APIKeyScopeMenu, DailyQuota: 2},
+		},
+		usage: make(map[uint][]string),
+	}
+}
+
+// newTestRouter wires the API key and rate limit middleware the way the
+// server does, in front of a few routes that answer 200.
+func newTestRouter(t *testing.T, apiKeyService services.APIKeyService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	log := logger.New("error", "json")
+	log.SetOutput(io.Discard)
+
+	store := NewMemoryRateLimitStore(1000, time.Hour)
+	t.Cleanup(store.Close)
+	rateLimiter := NewRateLimiter(store, config.RateLimitConfig{
+		Default: config.RateLimitRule{Name: "default", Limit: 100, Period: time.Minute},
+	}, log)
+
+	router := gin.New()
+	router.Use(APIKeyAuth(apiKeyService, log))
+	router.Use(rateLimiter.Middleware())
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	v1 := router.Group("/api/v1")
+	v1.GET("/menu", ok)
+	v1.POST("/categories", ok)
+	v1.POST("/menu/search/:searchId/clicks", ok)
+	v1.GET("/api-keys", RequireAdminAPIKey(), ok)
+	v1.GET("/analytics/search/top-queries", RequireAdminAPIKey(), ok)
+
+	return router
+}
+
+func serve(router *gin.Engine, method, path, apiKey, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if apiKey != "" {
+		req.Header.Set(APIKeyHeader, apiKey)
+	}
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		apiKey string
+		origin string
+		want   int
+	}{
+		{"no key reads the menu", http.MethodGet, "/api/v1/menu", "", "", http.StatusOK},
+		{"unknown key", http.MethodGet, "/api/v1/menu", "rmk_unknown", "", http.StatusUnauthorized},
+		{"menu key reads the menu", http.MethodGet, "/api/v1/menu", "rmk_menu", "", http.StatusOK},
+		{"menu key writes", http.MethodPost, "/api/v1/categories", "rmk_menu", "", http.StatusForbidden},
+		{"admin key writes", http.MethodPost, "/api/v1/categories", "rmk_admin", "", http.StatusOK},
+		{"menu key records a search click", http.MethodPost, "/api/v1/menu/search/42/clicks", "rmk_menu", "", http.StatusOK},
+		{"allowed origin", http.MethodGet, "/api/v1/menu", "rmk_origin", "https://menu.example.com", http.StatusOK},
+		{"allowed origin in another case", http.MethodGet, "/api/v1/menu", "rmk_origin", "HTTPS://Menu.Example.com", http.StatusOK},
+		{"allowed origin with a trailing slash", http.MethodGet, "/api/v1/menu", "rmk_origin", "https://menu.example.com/", http.StatusOK},
+		{"allowed host on another port", http.MethodGet, "/api/v1/menu", "rmk_origin", "https://menu.example.com:8443", http.StatusForbidden},
+		{"malformed origin", http.MethodGet, "/api/v1/menu", "rmk_origin", "menu.example.com", http.StatusForbidden},
+		{"disallowed origin", http.MethodGet, "/api/v1/menu", "rmk_origin", "https://other.example.com", http.StatusForbidden},
+		{"missing origin", http.MethodGet, "/api/v1/menu", "rmk_origin", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, newFakeAPIKeyService())
+			if got := serve(router, tt.method, tt.path, tt.apiKey, tt.origin).Code; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthRecordsUsage(t *testing.T) {
+	service := newFakeAPIKeyService()
+	router := newTestRouter(t, service)
+
+	serve(router, http.MethodGet, "/api/v1/menu", "rmk_menu", "")
+	serve(router, http.MethodPost, "/api/v1/categories", "rmk_menu", "")
+	serve(router, http.MethodGet, "/api/v1/nowhere", "rmk_admin", "")
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if got := service.usage[1]; len(got) != 1 || got[0] != "GET /api/v1/menu" {
+		t.Errorf("menu key usage = %v, want only the menu read", got)
+	}
+	if got := service.usage[2]; len(got) != 1 || got[0] != "GET unmatched" {
+		t.Errorf("admin key usage = %v, want one unmatched request", got)
+	}
+}
+
+func TestRequireAdminAPIKey(t *testing.T) {
+	for _, path := range []string{"/api/v1/api-keys", "/api/v1/analytics/search/top-queries"} {
+		t.Run(path, func(t *testing.T) {
+			router := newTestRouter(t, newFakeAPIKeyService())
+
+			if got := serve(router, http.MethodGet, path, "", "").Code; got != http.StatusUnauthorized {
+				t.Errorf("without a key: status = %d, want %d", got, http.StatusUnauthorized)
+			}
+			if got := serve(router, http.MethodGet, path, "rmk_menu", "").Code; got != http.StatusForbidden {
+				t.Errorf("with a menu key: status = %d, want %d", got, http.StatusForbidden)
+			}
+			if got := serve(router, http.MethodGet, path, "rmk_admin", "").Code; got != http.StatusOK {
+				t.Errorf("with an admin key: status = %d, want %d", got, http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestRateLimiterAPIKeyBucket(t *testing.T) {
+	router := newTestRouter(t, newFakeAPIKeyService())
+
+	for i := 0; i < 2; i++ {
+		recorder := serve(router, http.MethodGet, "/api/v1/menu", "rmk_burst", "")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i, recorder.Code, http.StatusOK)
+		}
+		if got := recorder.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("RateLimit-Limit = %q, want the key's own limit of 2", got)
+		}
+	}
+
+	recorder := serve(router, http.MethodGet, "/api/v1/menu", "rmk_burst", "")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After is missing")
+	}
+
+	// Other clients keep their own buckets
+	if got := serve(router, http.MethodGet, "/api/v1/menu", "rmk_menu", "").Code; got != http.StatusOK {
+		t.Errorf("another key: status = %d, want %d", got, http.StatusOK)
+	}
+	if got := serve(router, http.MethodGet, "/api/v1/menu", "", "").Code; got != http.StatusOK {
+		t.Errorf("without a key: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestRateLimiterDailyQuota(t *testing.T) {
+	router := newTestRouter(t, newFakeAPIKeyService())
+
+	for i, remaining := range []string{"1", "0"} {
+		recorder := serve(router, http.MethodGet, "/api/v1/menu", "rmk_quota", "")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i, recorder.Code, http.StatusOK)
+		}
+		if got := recorder.Header().Get("X-Quota-Limit"); got != "2" {
+			t.Errorf("X-Quota-Limit = %q, want 2", got)
+		}
+		if got := recorder.Header().Get("X-Quota-Remaining"); got != remaining {
+			t.Errorf("request %d: X-Quota-Remaining = %q, want %s", i, got, remaining)
+		}
+	}
+
+	recorder := serve(router, http.MethodGet, "/api/v1/menu", "rmk_quota", "")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if got := recorder.Header().Get("X-Quota-Remaining"); got != "0" {
+		t.Errorf("X-Quota-Remaining = %q, want 0", got)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After is missing")
+	}
+}
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		scope  entities.APIKeyScope
+		method string
+		route  string
+		want   bool
+	}{
+		{entities.APIKeyScopeMenu, http.MethodGet, "/api/v1/menu", true},
+		{entities.APIKeyScopeMenu, http.MethodGet, "/api/v1/menu/:categorySlug", true},
+		{entities.APIKeyScopeMenu, http.MethodHead, "/api/v1/restaurants/info", true},
+		{entities.APIKeyScopeMenu, http.MethodPost, "/api/v1/menu/search/:searchId/clicks", true},
+		{entities.APIKeyScopeMenu, http.MethodPost, "/api/v1/menu", false},
+		{entities.APIKeyScopeMenu, http.MethodDelete, "/api/v1/menu/search/:searchId/clicks", false},
+		{entities.APIKeyScopeMenu, http.MethodGet, "/api/v1/menufoo", false},
+		{entities.APIKeyScopeMenu, http.MethodGet, "/api/v1/categories", false},
+		{entities.APIKeyScopeMenu, http.MethodGet, "/api/v1/api-keys", false},
+		{entities.APIKeyScopeAdmin, http.MethodDelete, "/api/v1/categories/:id", true},
+	}
+
+	for _, tt := range tests {
+		if got := scopeAllows(tt.scope, tt.method, tt.route); got != tt.want {
+			t.Errorf("scopeAllows(%s, %s, %s) = %v, want %v", tt.scope, tt.method, tt.route, got, tt.want)
+		}
+	}
+}
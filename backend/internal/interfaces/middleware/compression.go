@@ -0,0 +1,325 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	encodingGzip   = "gzip"
+	encodingBrotli = "br"
+
+	// brotliLevel trades some ratio for the speed dynamic responses need
+	brotliLevel = 5
+)
+
+// Content types that are already compressed and gain nothing from another pass
+var incompressibleTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-brotli",
+	"application/pdf",
+	"application/octet-stream",
+}
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	brotliWriters = sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+)
+
+// Compression encodes responses with brotli or gzip, whichever the client
+// prefers, once they reach minSize bytes. Responses that already have a
+// Content-Encoding or an incompressible content type pass through.
+//
+// The encoding is appended to the ETag of compressed responses, so each
+// variant has its own validator, and stripped from If-None-Match again before
+// handlers compare it with the ETag they compute.
+func Compression(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		// Handlers compare If-None-Match with the ETag of the identity variant
+		ifNoneMatch := c.GetHeader("If-None-Match")
+		taggedMatch := false
+		if ifNoneMatch != "" {
+			var stripped string
+			stripped, taggedMatch = stripETagEncoding(ifNoneMatch, encoding)
+			c.Request.Header.Set("If-None-Match", stripped)
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		c.Writer = writer
+
+		defer func() {
+			// A 304 carries the validator the client sent
+			if writer.Status() == http.StatusNotModified && taggedMatch {
+				tagETag(writer.Header(), encoding)
+			}
+			writer.close()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// compressWriter buffers the start of the body until it knows whether the
+// response is big enough to compress, then either streams it through the
+// encoder or writes it as it is.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buffer  bytes.Buffer
+	encoder io.WriteCloser
+	decided bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow holds the header back until the encoding is decided; gin
+// writes it after the handlers if no body followed.
+func (w *compressWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Written reports a body as written while it is still buffered, so gin
+// doesn't write the header ahead of the encoding decision.
+func (w *compressWriter) Written() bool {
+	return w.buffer.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(w.buffer.Len() >= w.minSize)
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.Hijack()
+}
+
+// decide settles whether the response is compressed and writes out what has
+// been buffered.
+func (w *compressWriter) decide(bigEnough bool) error {
+	w.decided = true
+
+	if bigEnough && w.compressible() {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		tagETag(header, w.encoding)
+
+		w.encoder = w.newEncoder()
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	data := w.buffer.Bytes()
+	w.buffer = bytes.Buffer{}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// close writes out a body that never reached the size threshold or finishes
+// the encoded stream.
+func (w *compressWriter) close() {
+	if !w.decided {
+		if w.buffer.Len() == 0 {
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(w.buffer.Len()))
+		_ = w.decide(false)
+	}
+
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.releaseEncoder()
+	}
+}
+
+func (w *compressWriter) compressible() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *compressWriter) newEncoder() io.WriteCloser {
+	if w.encoding == encodingBrotli {
+		encoder := brotliWriters.Get().(*brotli.Writer)
+		encoder.Reset(w.ResponseWriter)
+		return encoder
+	}
+
+	encoder := gzipWriters.Get().(*gzip.Writer)
+	encoder.Reset(w.ResponseWriter)
+	return encoder
+}
+
+func (w *compressWriter) releaseEncoder() {
+	switch encoder := w.encoder.(type) {
+	case *brotli.Writer:
+		encoder.Reset(io.Discard)
+		brotliWriters.Put(encoder)
+	case *gzip.Writer:
+		encoder.Reset(io.Discard)
+		gzipWriters.Put(encoder)
+	}
+	w.encoder = nil
+}
+
+// negotiateEncoding picks brotli or gzip from an Accept-Encoding header,
+// preferring the higher quality and brotli on a tie. It returns "" when the
+// client accepts neither.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64, 2)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, quality := parseEncoding(part)
+		switch name {
+		case encodingBrotli, encodingGzip:
+			qualities[name] = quality
+		case "x-gzip":
+			qualities[encodingGzip] = quality
+		case "*":
+			wildcard = quality
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// parseEncoding splits one Accept-Encoding entry into its lowercase coding and
+// quality, 1 unless given.
+func parseEncoding(part string) (string, float64) {
+	name, params, _ := strings.Cut(part, ";")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	quality := 1.0
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return name, 0
+		}
+		quality = q
+	}
+	return name, quality
+}
+
+// tagETag appends the encoding to the response's ETag, W/"v" becoming
+// W/"v-gzip".
+func tagETag(header http.Header, encoding string) {
+	etag := header.Get("ETag")
+	if !strings.HasSuffix(etag, `"`) || strings.HasSuffix(etag, "-"+encoding+`"`) {
+		return
+	}
+	header.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`)
+}
+
+// stripETagEncoding removes the encoding tagETag adds from every entity tag
+// in an If-None-Match list and reports whether any had it.
+func stripETagEncoding(ifNoneMatch, encoding string) (string, bool) {
+	suffix := "-" + encoding + `"`
+	tagged := false
+
+	tags := strings.Split(ifNoneMatch, ",")
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if strings.HasSuffix(tag, suffix) {
+			tag = strings.TrimSuffix(tag, suffix) + `"`
+			tagged = true
+		}
+		tags[i] = tag
+	}
+	return strings.Join(tags, ", "), tagged
+}
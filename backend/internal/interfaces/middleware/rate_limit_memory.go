@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitShards spreads buckets over independently locked maps, so
+	// concurrent requests rarely wait on each other
+	rateLimitShards = 32
+	// evictionSample is how many buckets a full shard looks at to pick one
+	// to drop for a new client
+	evictionSample = 8
+)
+
+// MemoryRateLimitStore keeps token buckets and quota counters in process
+// memory, for when Redis is not available; each server then limits clients
+// on its own.
+//
+// A bucket that has refilled is no different from a missing one, so a
+// background sweep drops those. Beyond maxClients buckets, a new client
+// replaces the bucket closest to refilling among a few sampled, which keeps
+// memory bounded at the cost of forgetting a little of some client's use.
+type MemoryRateLimitStore struct {
+	shards      [rateLimitShards]rateLimitShard
+	maxPerShard int
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+type rateLimitShard struct {
+	mu       sync.Mutex
+	buckets  map[string]*memoryBucket
+	counters map[string]*memoryCounter
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	// fullAt is when the bucket will have refilled
+	fullAt time.Time
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// NewMemoryRateLimitStore keeps buckets for up to maxClients clients and
+// drops refilled ones every sweepInterval until Close is called.
+func NewMemoryRateLimitStore(maxClients int, sweepInterval time.Duration) *MemoryRateLimitStore {
+	store := &MemoryRateLimitStore{
+		maxPerShard: (maxClients + rateLimitShards - 1) / rateLimitShards,
+		stop:        make(chan struct{}),
+	}
+	for i := range store.shards {
+		store.shards[i].buckets = make(map[string]*memoryBucket)
+		store.shards[i].counters = make(map[string]*memoryCounter)
+	}
+
+	go store.sweepEvery(sweepInterval)
+	return store
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, burst int, refillEvery time.Duration) (*RateLimitDecision, error) {
+	now := time.Now()
+	capacity := float64(burst)
+
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	bucket, ok := shard.buckets[key]
+	if !ok {
+		if len(shard.buckets) >= s.maxPerShard {
+			shard.evictOne()
+		}
+		bucket = &memoryBucket{tokens: capacity, updated: now}
+		shard.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updated)
+	if elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)/float64(refillEvery))
+		bucket.updated = now
+	}
+
+	decision := &RateLimitDecision{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration(math.Ceil((1 - bucket.tokens) * float64(refillEvery)))
+	}
+
+	decision.Remaining = int(bucket.tokens)
+	decision.ResetAfter = time.Duration(math.Ceil((capacity - bucket.tokens) * float64(refillEvery)))
+	bucket.fullAt = now.Add(decision.ResetAfter)
+
+	return decision, nil
+}
+
+func (s *MemoryRateLimitStore) Increment(ctx context.Context, key string, expiresAt time.Time) (int64, error) {
+	now := time.Now()
+
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	counter, ok := shard.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		if !ok && len(shard.counters) >= s.maxPerShard {
+			shard.evictCounter()
+		}
+		counter = &memoryCounter{}
+		shard.counters[key] = counter
+	}
+
+	counter.count++
+	counter.expiresAt = expiresAt
+	return counter.count, nil
+}
+
+// Close stops the background sweep.
+func (s *MemoryRateLimitStore) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *MemoryRateLimitStore) shard(key string) *rateLimitShard {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return &s.shards[hash.Sum32()%rateLimitShards]
+}
+
+func (s *MemoryRateLimitStore) sweepEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			for i := range s.shards {
+				s.shards[i].sweep(now)
+			}
+		}
+	}
+}
+
+// sweep drops the buckets that have refilled and the counters that have
+// expired by now.
+func (sh *rateLimitShard) sweep(now time.Time) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	for key, bucket := range sh.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(sh.buckets, key)
+		}
+	}
+	for key, counter := range sh.counters {
+		if !now.Before(counter.expiresAt) {
+			delete(sh.counters, key)
+		}
+	}
+}
+
+// evictOne drops the bucket closest to refilling among a few sampled. Map
+// iteration order is random, so the sample is too. The caller holds the lock.
+func (sh *rateLimitShard) evictOne() {
+	var victim string
+	var victimFullAt time.Time
+	sampled := 0
+	for key, bucket := range sh.buckets {
+		if sampled == 0 || bucket.fullAt.Before(victimFullAt) {
+			victim, victimFullAt = key, bucket.fullAt
+		}
+		sampled++
+		if sampled == evictionSample {
+			break
+		}
+	}
+	delete(sh.buckets, victim)
+}
+
+// evictCounter drops the counter expiring first among a few sampled. The
+// caller holds the lock.
+func (sh *rateLimitShard) evictCounter() {
+	var victim string
+	var victimExpiresAt time.Time
+	sampled := 0
+	for key, counter := range sh.counters {
+		if sampled == 0 || counter.expiresAt.Before(victimExpiresAt) {
+			victim, victimExpiresAt = key, counter.expiresAt
+		}
+		sampled++
+		if sampled == evictionSample {
+			break
+		}
+	}
+	delete(sh.counters, victim)
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// shardSizes returns how many buckets and counters each shard holds.
+func shardSizes(store *MemoryRateLimitStore) (buckets, counters []int) {
+	for i := range store.shards {
+		shard := &store.shards[i]
+		shard.mu.Lock()
+		buckets = append(buckets, len(shard.buckets))
+		counters = append(counters, len(shard.counters))
+		shard.mu.Unlock()
+	}
+	return buckets, counters
+}
+
+func TestMemoryRateLimitStoreTakeConcurrent(t *testing.T) {
+	store := NewMemoryRateLimitStore(1000, time.Hour)
+	defer store.Close()
+	ctx := context.Background()
+
+	const (
+		clients  = 50
+		burst    = 10
+		requests = 40
+	)
+
+	// Buckets refill far slower than the test runs, so each client gets
+	// exactly burst requests through however the goroutines interleave
+	allowed := make([]atomic.Int64, clients)
+	var wg sync.WaitGroup
+	for c := 0; c < clients; c++ {
+		for r := 0; r < requests; r++ {
+			wg.Add(1)
+			go func(c int) {
+				defer wg.Done()
+				decision, err := store.Take(ctx, fmt.Sprintf("client:%d", c), burst, time.Hour)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if decision.Allowed {
+					allowed[c].Add(1)
+				} else if decision.RetryAfter <= 0 {
+					t.Errorf("refused request without Retry-After")
+				}
+			}(c)
+		}
+	}
+	wg.Wait()
+
+	for c := range allowed {
+		if got := allowed[c].Load(); got != burst {
+			t.Errorf("client %d: %d requests allowed, want %d", c, got, burst)
+		}
+	}
+}
+
+func TestMemoryRateLimitStoreRefill(t *testing.T) {
+	store := NewMemoryRateLimitStore(100, time.Hour)
+	defer store.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if decision, _ := store.Take(ctx, "client", 2, 20*time.Millisecond); !decision.Allowed {
+			t.Fatalf("request %d refused within the burst", i)
+		}
+	}
+	if decision, _ := store.Take(ctx, "client", 2, 20*time.Millisecond); decision.Allowed {
+		t.Fatal("request allowed beyond the burst")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if decision, _ := store.Take(ctx, "client", 2, 20*time.Millisecond); !decision.Allowed {
+		t.Error("request refused after a token refilled")
+	}
+}
+
+func TestMemoryRateLimitStoreIncrementConcurrent(t *testing.T) {
+	store := NewMemoryRateLimitStore(1000, time.Hour)
+	defer store.Close()
+	ctx := context.Background()
+
+	const (
+		counters   = 20
+		increments = 100
+	)
+	expiresAt := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	for c := 0; c < counters; c++ {
+		for i := 0; i < increments; i++ {
+			wg.Add(1)
+			go func(c int) {
+				defer wg.Done()
+				if _, err := store.Increment(ctx, fmt.Sprintf("quota:%d", c), expiresAt); err != nil {
+					t.Error(err)
+				}
+			}(c)
+		}
+	}
+	wg.Wait()
+
+	for c := 0; c < counters; c++ {
+		count, _ := store.Increment(ctx, fmt.Sprintf("quota:%d", c), expiresAt)
+		if count != increments+1 {
+			t.Errorf("counter %d = %d, want %d", c, count, increments+1)
+		}
+	}
+}
+
+func TestMemoryRateLimitStoreIncrementExpiry(t *testing.T) {
+	store := NewMemoryRateLimitStore(100, time.Hour)
+	defer store.Close()
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(20 * time.Millisecond)
+	store.Increment(ctx, "quota", expiresAt)
+	store.Increment(ctx, "quota", expiresAt)
+
+	time.Sleep(30 * time.Millisecond)
+	if count, _ := store.Increment(ctx, "quota", time.Now().Add(time.Hour)); count != 1 {
+		t.Errorf("count after expiry = %d, want 1", count)
+	}
+}
+
+func TestMemoryRateLimitStoreEviction(t *testing.T) {
+	const maxClients = rateLimitShards * 4
+	store := NewMemoryRateLimitStore(maxClients, time.Hour)
+	defer store.Close()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("client:%d:%d", g, i)
+				if _, err := store.Take(ctx, key, 5, time.Hour); err != nil {
+					t.Error(err)
+				}
+				if _, err := store.Increment(ctx, key, time.Now().Add(time.Hour)); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	buckets, counters := shardSizes(store)
+	total := 0
+	for i := range buckets {
+		if buckets[i] > store.maxPerShard || counters[i] > store.maxPerShard {
+			t.Errorf("shard %d holds %d buckets and %d counters, limit %d", i, buckets[i], counters[i], store.maxPerShard)
+		}
+		total += buckets[i]
+	}
+	if total > maxClients {
+		t.Errorf("%d buckets kept, limit %d", total, maxClients)
+	}
+}
+
+func TestMemoryRateLimitStoreSweep(t *testing.T) {
+	store := NewMemoryRateLimitStore(100, 10*time.Millisecond)
+	defer store.Close()
+	ctx := context.Background()
+
+	store.Take(ctx, "refilling", 1, 5*time.Millisecond)
+	store.Take(ctx, "draining", 1, time.Hour)
+	store.Increment(ctx, "expiring", time.Now().Add(5*time.Millisecond))
+	store.Increment(ctx, "lasting", time.Now().Add(time.Hour))
+
+	time.Sleep(50 * time.Millisecond)
+
+	buckets, counters := shardSizes(store)
+	totalBuckets, totalCounters := 0, 0
+	for i := range buckets {
+		totalBuckets += buckets[i]
+		totalCounters += counters[i]
+	}
+	if totalBuckets != 1 || totalCounters != 1 {
+		t.Errorf("after the sweep %d buckets and %d counters are kept, want 1 each", totalBuckets, totalCounters)
+	}
+}
+
+func TestMemoryRateLimitStoreClose(t *testing.T) {
+	store := NewMemoryRateLimitStore(100, time.Millisecond)
+	store.Close()
+	// Closing twice is harmless
+	store.Close()
+}
@@ -3,199 +3,194 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"restaurant-menu-api/internal/config"
+	"restaurant-menu-api/internal/domain/entities"
 	"restaurant-menu-api/internal/infrastructure/redis"
+	appErrors "restaurant-menu-api/pkg/errors"
 	"restaurant-menu-api/pkg/logger"
 	"restaurant-menu-api/pkg/response"
 )
 
-type RateLimiter struct {
-	redisClient *redis.Client
-	logger      *logger.Logger
-	config      RateLimiterConfig
+// RateLimitStore keeps the token buckets the rate limiter draws from.
+type RateLimitStore interface {
+	// Take takes a token from the bucket under key, which holds up to burst
+	// tokens and regains one every refillEvery.
+	Take(ctx context.Context, key string, burst int, refillEvery time.Duration) (*RateLimitDecision, error)
+	// Increment counts a request under key until expiresAt and returns the
+	// count so far.
+	Increment(ctx context.Context, key string, expiresAt time.Time) (int64, error)
 }
 
-type RateLimiterConfig struct {
-	RequestsPerMinute int
-	WindowSize        time.Duration
-	KeyPrefix         string
+// RateLimitDecision is whether a request may go ahead and the state of its
+// bucket afterwards.
+type RateLimitDecision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
 }
 
-func NewRateLimiter(redisClient *redis.Client, logger *logger.Logger, config RateLimiterConfig) *RateLimiter {
-	if config.RequestsPerMinute == 0 {
-		config.RequestsPerMinute = 100 // Default: 100 requests per minute
-	}
-	if config.WindowSize == 0 {
-		config.WindowSize = time.Minute // Default: 1 minute window
-	}
-	if config.KeyPrefix == "" {
-		config.KeyPrefix = "rate_limit"
-	}
+type redisRateLimitStore struct {
+	client *redis.Client
+}
 
-	return &RateLimiter{
-		redisClient: redisClient,
-		logger:      logger,
-		config:      config,
-	}
+// NewRedisRateLimitStore keeps buckets in Redis, shared by every server.
+func NewRedisRateLimitStore(client *redis.Client) RateLimitStore {
+	return &redisRateLimitStore{client: client}
 }
 
-func (rl *RateLimiter) Middleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx := c.Request.Context()
+func (s *redisRateLimitStore) Take(ctx context.Context, key string, burst int, refillEvery time.Duration) (*RateLimitDecision, error) {
+	result, err := s.client.TakeToken(ctx, key, burst, refillEvery)
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitDecision{
+		Allowed:    result.Allowed,
+		Remaining:  result.Remaining,
+		RetryAfter: result.RetryAfter,
+		ResetAfter: result.ResetAfter,
+	}, nil
+}
 
-		// Get client identifier (IP address + User-Agent for more uniqueness)
-		clientID := rl.getClientID(c)
-		
-		// Check rate limit
-		allowed, remaining, resetTime, err := rl.checkRateLimit(ctx, clientID)
-		if err != nil {
-			rl.logger.LogError(ctx, err, "Rate limiter error", map[string]interface{}{
-				"client_id": clientID,
-			})
-			// On error, allow the request but log the issue
-			c.Next()
-			return
-		}
+func (s *redisRateLimitStore) Increment(ctx context.Context, key string, expiresAt time.Time) (int64, error) {
+	return s.client.IncrementWithExpiry(ctx, key, time.Until(expiresAt))
+}
 
-		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(rl.config.RequestsPerMinute))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
+// RateLimiter limits each client, told apart by API key or else by IP
+// address, to the token bucket of the first configured rule matching the
+// request, or the default rule. A key with its own rate limit draws from one
+// bucket of that size instead, and a key with a daily quota is refused once
+// it has used it up. It reports the bucket in the RateLimit-* headers and
+// answers 429 with Retry-After once it is empty. Requests go ahead when the
+// store fails.
+type RateLimiter struct {
+	store       RateLimitStore
+	rules       []*rateLimitRule
+	defaultRule *rateLimitRule
+	logger      *logger.Logger
+}
 
-		if !allowed {
-			rl.logger.LogWarning(ctx, "Rate limit exceeded", map[string]interface{}{
-				"client_id":  clientID,
-				"path":       c.Request.URL.Path,
-				"method":     c.Request.Method,
-				"user_agent": c.Request.UserAgent(),
-			})
+type rateLimitRule struct {
+	name        string
+	methods     map[string]bool
+	segments    []string
+	anyRest     bool
+	burst       int
+	refillEvery time.Duration
+	policy      string
+}
 
-			response.Error(c, fmt.Errorf("rate limit exceeded"))
-			c.Abort()
-			return
-		}
+func NewRateLimiter(store RateLimitStore, cfg config.RateLimitConfig, logger *logger.Logger) *RateLimiter {
+	rules := make([]*rateLimitRule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		rules[i] = newRateLimitRule(rule)
+	}
 
-		c.Next()
+	return &RateLimiter{
+		store:       store,
+		rules:       rules,
+		defaultRule: newRateLimitRule(cfg.Default),
+		logger:      logger,
 	}
 }
 
-func (rl *RateLimiter) checkRateLimit(ctx context.Context, clientID string) (allowed bool, remaining int, resetTime time.Time, err error) {
-	key := fmt.Sprintf("%s:%s", rl.config.KeyPrefix, clientID)
-	
-	// Use sliding window approach
-	now := time.Now()
-	windowStart := now.Truncate(rl.config.WindowSize)
-	resetTime = windowStart.Add(rl.config.WindowSize)
-
-	// Get current count
-	count, err := rl.redisClient.IncrementWithExpiry(ctx, key, rl.config.WindowSize)
-	if err != nil {
-		return false, 0, resetTime, err
+func newRateLimitRule(cfg config.RateLimitRule) *rateLimitRule {
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = cfg.Limit
 	}
 
-	remaining = rl.config.RequestsPerMinute - int(count)
-	if remaining < 0 {
-		remaining = 0
+	rule := &rateLimitRule{
+		name:        cfg.Name,
+		burst:       burst,
+		refillEvery: cfg.Period / time.Duration(cfg.Limit),
+		policy:      fmt.Sprintf("%d;w=%d;burst=%d", cfg.Limit, int(math.Ceil(cfg.Period.Seconds())), burst),
 	}
 
-	allowed = count <= int64(rl.config.RequestsPerMinute)
-
-	return allowed, remaining, resetTime, nil
-}
-
-func (rl *RateLimiter) getClientID(c *gin.Context) string {
-	// Try to get real IP from headers (for load balancers/proxies)
-	ip := c.GetHeader("X-Forwarded-For")
-	if ip == "" {
-		ip = c.GetHeader("X-Real-IP")
-	}
-	if ip == "" {
-		ip = c.ClientIP()
+	if len(cfg.Methods) > 0 {
+		rule.methods = make(map[string]bool, len(cfg.Methods))
+		for _, method := range cfg.Methods {
+			rule.methods[strings.ToUpper(method)] = true
+		}
 	}
 
-	// Include user agent for additional uniqueness (truncated to avoid very long keys)
-	userAgent := c.Request.UserAgent()
-	if len(userAgent) > 50 {
-		userAgent = userAgent[:50]
+	rule.segments = pathSegments(cfg.Path)
+	if n := len(rule.segments); n > 0 && rule.segments[n-1] == "**" {
+		rule.segments = rule.segments[:n-1]
+		rule.anyRest = true
 	}
 
-	return fmt.Sprintf("%s:%s", ip, userAgent)
+	return rule
 }
 
-// Advanced rate limiter with different limits for different endpoints
-type AdvancedRateLimiter struct {
-	redisClient *redis.Client
-	logger      *logger.Logger
-	rules       map[string]RateLimiterConfig
-	defaultRule RateLimiterConfig
-}
-
-func NewAdvancedRateLimiter(redisClient *redis.Client, logger *logger.Logger) *AdvancedRateLimiter {
-	return &AdvancedRateLimiter{
-		redisClient: redisClient,
-		logger:      logger,
-		rules: map[string]RateLimiterConfig{
-			"/v1/upload":     {RequestsPerMinute: 10, WindowSize: time.Minute, KeyPrefix: "rate_limit_upload"},
-			"/v1/search":     {RequestsPerMinute: 50, WindowSize: time.Minute, KeyPrefix: "rate_limit_search"},
-			"/v1/menu":       {RequestsPerMinute: 200, WindowSize: time.Minute, KeyPrefix: "rate_limit_menu"},
-			"POST:/v1/":      {RequestsPerMinute: 30, WindowSize: time.Minute, KeyPrefix: "rate_limit_post"},
-			"PUT:/v1/":       {RequestsPerMinute: 30, WindowSize: time.Minute, KeyPrefix: "rate_limit_put"},
-			"DELETE:/v1/":    {RequestsPerMinute: 20, WindowSize: time.Minute, KeyPrefix: "rate_limit_delete"},
-		},
-		defaultRule: RateLimiterConfig{
-			RequestsPerMinute: 100,
-			WindowSize:        time.Minute,
-			KeyPrefix:         "rate_limit_default",
-		},
+// apiKeyRateLimitRule is the single bucket of a key with its own rate limit,
+// requestsPerMinute deep.
+func apiKeyRateLimitRule(requestsPerMinute int) *rateLimitRule {
+	return &rateLimitRule{
+		name:        "key",
+		burst:       requestsPerMinute,
+		refillEvery: time.Minute / time.Duration(requestsPerMinute),
+		policy:      fmt.Sprintf("%d;w=60", requestsPerMinute),
 	}
 }
 
-func (arl *AdvancedRateLimiter) Middleware() gin.HandlerFunc {
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
-		// Get client identifier
-		clientID := arl.getClientID(c)
-		
-		// Determine which rule to apply
-		rule := arl.getRuleForRequest(c)
-		
-		// Create rate limiter for this rule
-		rl := NewRateLimiter(arl.redisClient, arl.logger, rule)
-		
-		// Check rate limit
-		allowed, remaining, resetTime, err := rl.checkRateLimit(ctx, clientID)
+		rule := rl.ruleFor(c.Request.Method, c.Request.URL.Path)
+		clientIP := c.ClientIP()
+		client := clientIP
+
+		apiKey := APIKeyFromContext(c)
+		if apiKey != nil {
+			client = "key:" + strconv.FormatUint(uint64(apiKey.ID), 10)
+			if apiKey.RateLimit > 0 {
+				rule = apiKeyRateLimitRule(apiKey.RateLimit)
+			}
+		}
+
+		decision, err := rl.store.Take(ctx, "rate_limit:"+rule.name+":"+client, rule.burst, rule.refillEvery)
 		if err != nil {
-			arl.logger.LogError(ctx, err, "Advanced rate limiter error", map[string]interface{}{
-				"client_id": clientID,
-				"rule":      rule.KeyPrefix,
+			rl.logger.LogError(ctx, err, "Rate limiter error", map[string]interface{}{
+				"client_ip": clientIP,
+				"rule":      rule.name,
 			})
 			// On error, allow the request but log the issue
 			c.Next()
 			return
 		}
 
-		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(rule.RequestsPerMinute))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
-		c.Header("X-RateLimit-Policy", rule.KeyPrefix)
-
-		if !allowed {
-			arl.logger.LogWarning(ctx, "Rate limit exceeded", map[string]interface{}{
-				"client_id":  clientID,
-				"path":       c.Request.URL.Path,
-				"method":     c.Request.Method,
-				"user_agent": c.Request.UserAgent(),
-				"rule":       rule.KeyPrefix,
-				"limit":      rule.RequestsPerMinute,
+		c.Header("RateLimit-Limit", strconv.Itoa(rule.burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.ResetAfter)))
+		c.Header("RateLimit-Policy", rule.policy)
+
+		if !decision.Allowed {
+			retryAfter := ceilSeconds(decision.RetryAfter)
+
+			rl.logger.LogWarning(ctx, "Rate limit exceeded", map[string]interface{}{
+				"client_ip":   clientIP,
+				"path":        c.Request.URL.Path,
+				"method":      c.Request.Method,
+				"rule":        rule.name,
+				"retry_after": retryAfter,
 			})
 
-			response.Error(c, fmt.Errorf("rate limit exceeded for this endpoint"))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			response.Error(c, appErrors.NewRateLimitError("Rate limit exceeded", fmt.Sprintf("Try again in %ds", retryAfter)))
+			c.Abort()
+			return
+		}
+
+		if apiKey != nil && apiKey.DailyQuota > 0 && !rl.withinQuota(c, apiKey) {
 			c.Abort()
 			return
 		}
@@ -204,113 +199,83 @@ func (arl *AdvancedRateLimiter) Middleware() gin.HandlerFunc {
 	}
 }
 
-func (arl *AdvancedRateLimiter) getRuleForRequest(c *gin.Context) RateLimiterConfig {
-	path := c.Request.URL.Path
-	method := c.Request.Method
+// withinQuota counts the request against the key's quota for the current UTC
+// day and reports it in the X-Quota-* headers, answering 429 and returning
+// false once the quota is used up.
+func (rl *RateLimiter) withinQuota(c *gin.Context, apiKey *entities.APIKey) bool {
+	ctx := c.Request.Context()
 
-	// Check for method-specific rules first
-	methodPath := fmt.Sprintf("%s:%s", method, path)
-	if rule, exists := arl.rules[methodPath]; exists {
-		return rule
-	}
+	now := time.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+	resetAt := day.Add(24 * time.Hour)
 
-	// Check for method-prefix rules
-	for prefix, rule := range arl.rules {
-		if method+":" == prefix[:len(method)+1] && 
-		   len(prefix) > len(method)+1 && 
-		   path[:len(prefix)-len(method)-1] == prefix[len(method)+1:] {
-			return rule
-		}
-	}
-
-	// Check for path-specific rules
-	if rule, exists := arl.rules[path]; exists {
-		return rule
+	used, err := rl.store.Increment(ctx, fmt.Sprintf("api_key_quota:%d:%s", apiKey.ID, day.Format("2006-01-02")), resetAt)
+	if err != nil {
+		rl.logger.LogError(ctx, err, "Rate limiter error", map[string]interface{}{
+			"api_key_id": apiKey.ID,
+		})
+		// On error, allow the request but log the issue
+		return true
 	}
 
-	// Check for path prefix rules
-	for prefix, rule := range arl.rules {
-		if len(prefix) > 0 && prefix[len(prefix)-1:] != ":" && 
-		   len(path) >= len(prefix) && 
-		   path[:len(prefix)] == prefix {
-			return rule
-		}
+	remaining := int64(apiKey.DailyQuota) - used
+	if remaining < 0 {
+		remaining = 0
 	}
+	c.Header("X-Quota-Limit", strconv.Itoa(apiKey.DailyQuota))
+	c.Header("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
 
-	// Return default rule
-	return arl.defaultRule
-}
-
-func (arl *AdvancedRateLimiter) getClientID(c *gin.Context) string {
-	// Try to get real IP from headers (for load balancers/proxies)
-	ip := c.GetHeader("X-Forwarded-For")
-	if ip == "" {
-		ip = c.GetHeader("X-Real-IP")
-	}
-	if ip == "" {
-		ip = c.ClientIP()
+	if used <= int64(apiKey.DailyQuota) {
+		return true
 	}
 
-	// Include user agent for additional uniqueness (truncated to avoid very long keys)
-	userAgent := c.Request.UserAgent()
-	if len(userAgent) > 50 {
-		userAgent = userAgent[:50]
-	}
+	retryAfter := ceilSeconds(resetAt.Sub(now))
 
-	return fmt.Sprintf("%s:%s", ip, userAgent)
-}
+	rl.logger.LogWarning(ctx, "Daily quota exceeded", map[string]interface{}{
+		"api_key_id":  apiKey.ID,
+		"path":        c.Request.URL.Path,
+		"method":      c.Request.Method,
+		"daily_quota": apiKey.DailyQuota,
+	})
 
-// IP-based simple rate limiter (fallback when Redis is not available)
-type SimpleRateLimiter struct {
-	requests map[string][]time.Time
-	limit    int
-	window   time.Duration
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	response.Error(c, appErrors.NewRateLimitError("Daily quota exceeded", fmt.Sprintf("The quota of %d requests resets at %s", apiKey.DailyQuota, resetAt.Format(time.RFC3339))))
+	return false
 }
 
-func NewSimpleRateLimiter(limit int, window time.Duration) *SimpleRateLimiter {
-	return &SimpleRateLimiter{
-		requests: make(map[string][]time.Time),
-		limit:    limit,
-		window:   window,
+// ruleFor returns the first rule matching the request, or the default rule.
+func (rl *RateLimiter) ruleFor(method, path string) *rateLimitRule {
+	segments := pathSegments(path)
+	for _, rule := range rl.rules {
+		if rule.matches(method, segments) {
+			return rule
+		}
 	}
+	return rl.defaultRule
 }
 
-func (srl *SimpleRateLimiter) Middleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		now := time.Now()
-
-		// Clean old entries
-		srl.cleanup(ip, now)
+func (r *rateLimitRule) matches(method string, segments []string) bool {
+	if r.methods != nil && !r.methods[method] {
+		return false
+	}
 
-		// Check current requests
-		if len(srl.requests[ip]) >= srl.limit {
-			response.Error(c, fmt.Errorf("rate limit exceeded"))
-			c.Abort()
-			return
+	if len(segments) < len(r.segments) || (!r.anyRest && len(segments) != len(r.segments)) {
+		return false
+	}
+	for i, pattern := range r.segments {
+		if pattern != "*" && !strings.HasPrefix(pattern, ":") && pattern != segments[i] {
+			return false
 		}
-
-		// Add current request
-		srl.requests[ip] = append(srl.requests[ip], now)
-
-		c.Next()
 	}
+	return true
 }
 
-func (srl *SimpleRateLimiter) cleanup(ip string, now time.Time) {
-	cutoff := now.Add(-srl.window)
-	requests := srl.requests[ip]
-	
-	var validRequests []time.Time
-	for _, req := range requests {
-		if req.After(cutoff) {
-			validRequests = append(validRequests, req)
-		}
-	}
-	
-	if len(validRequests) == 0 {
-		delete(srl.requests, ip)
-	} else {
-		srl.requests[ip] = validRequests
-	}
-}
\ No newline at end of file
+// pathSegments splits a path at its slashes, ignoring empty segments.
+func pathSegments(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+}
+
+// ceilSeconds rounds d up to whole seconds, as the rate limit headers count.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,4 @@
+-- Rollback: item placements
+
+DROP TRIGGER IF EXISTS update_item_placements_updated_at ON item_placements;
+DROP TABLE IF EXISTS item_placements;
@@ -0,0 +1,22 @@
+-- Allow items to be listed in multiple subcategories
+
+CREATE TABLE item_placements (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    sub_category_id INTEGER NOT NULL REFERENCES sub_categories(id) ON DELETE CASCADE,
+    display_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_item_placements_item_sub_category ON item_placements(item_id, sub_category_id);
+CREATE INDEX idx_item_placements_sub_category_id ON item_placements(sub_category_id);
+CREATE INDEX idx_item_placements_display_order ON item_placements(display_order);
+
+-- Every existing item is placed in its current subcategory
+INSERT INTO item_placements (item_id, sub_category_id, display_order)
+SELECT id, sub_category_id, display_order
+FROM items
+WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_item_placements_updated_at BEFORE UPDATE ON item_placements FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
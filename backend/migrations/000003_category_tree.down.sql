@@ -0,0 +1,9 @@
+-- Rollback: category tree
+
+DROP INDEX IF EXISTS idx_sub_categories_path;
+DROP INDEX IF EXISTS idx_sub_categories_parent_id;
+
+ALTER TABLE sub_categories
+    DROP COLUMN IF EXISTS depth,
+    DROP COLUMN IF EXISTS path,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,12 @@
+-- Allow subcategories to be nested to any depth below a category
+
+ALTER TABLE sub_categories
+    ADD COLUMN parent_id INTEGER REFERENCES sub_categories(id) ON DELETE CASCADE,
+    ADD COLUMN path VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN depth INTEGER DEFAULT 0;
+
+-- Existing subcategories sit directly below their category
+UPDATE sub_categories SET path = '/' || id || '/', depth = 0;
+
+CREATE INDEX idx_sub_categories_parent_id ON sub_categories(parent_id);
+CREATE INDEX idx_sub_categories_path ON sub_categories(path varchar_pattern_ops);
@@ -0,0 +1,9 @@
+-- Rollback: item slugs and slug history
+
+DROP TRIGGER IF EXISTS update_slug_redirects_updated_at ON slug_redirects;
+DROP TABLE IF EXISTS slug_redirects;
+
+DROP INDEX IF EXISTS idx_sub_categories_category_slug;
+DROP INDEX IF EXISTS idx_items_slug;
+
+ALTER TABLE items DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,97 @@
+-- Item slugs and slug history for public menu URLs
+
+ALTER TABLE items ADD COLUMN slug VARCHAR(160);
+
+-- Derive item slugs from names the way the application does, keeping the
+-- first row with a name on the plain slug and giving later ones the lowest
+-- free "-n" suffix. Checking each candidate against every slug assigned so far
+-- keeps a suffixed slug from taking another name's plain one, such as the
+-- second "Soup" taking the "soup-12" of "Soup 12".
+-- Only ASCII letters are kept here, so names in other scripts get item-<id>,
+-- category-<id> or sub-category-<id>; run the maintenance command
+-- regenerate-slugs afterwards to transliterate them as new rows are. Those
+-- rows go first so that they keep exactly that slug.
+CREATE INDEX idx_items_slug_backfill ON items(slug);
+
+DO $$
+DECLARE
+    item RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INTEGER;
+BEGIN
+    FOR item IN
+        SELECT id, name_base FROM (
+            SELECT id, LEFT(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g')), 150) AS name_base
+            FROM items
+        ) AS bases
+        ORDER BY name_base = '' DESC, id
+    LOOP
+        base := item.name_base;
+        IF base = '' THEN
+            base := 'item-' || item.id;
+        END IF;
+
+        candidate := base;
+        n := 2;
+        WHILE EXISTS (SELECT 1 FROM items WHERE slug = candidate) LOOP
+            candidate := base || '-' || n;
+            n := n + 1;
+        END LOOP;
+
+        UPDATE items SET slug = candidate WHERE id = item.id;
+    END LOOP;
+END $$;
+
+DROP INDEX idx_items_slug_backfill;
+
+CREATE UNIQUE INDEX idx_items_slug ON items(slug);
+
+-- Names without ASCII letters used to produce empty slugs; categories named
+-- like "Category 12" may already use the fallback, so it is suffixed the same
+-- way. Subcategory slugs only need to be unique within their category.
+DO $$
+DECLARE
+    entry RECORD;
+    candidate TEXT;
+    n INTEGER;
+BEGIN
+    FOR entry IN SELECT id FROM categories WHERE slug IS NULL OR slug = '' ORDER BY id LOOP
+        candidate := 'category-' || entry.id;
+        n := 2;
+        WHILE EXISTS (SELECT 1 FROM categories WHERE slug = candidate) LOOP
+            candidate := 'category-' || entry.id || '-' || n;
+            n := n + 1;
+        END LOOP;
+
+        UPDATE categories SET slug = candidate WHERE id = entry.id;
+    END LOOP;
+
+    FOR entry IN SELECT id, category_id FROM sub_categories WHERE slug IS NULL OR slug = '' ORDER BY id LOOP
+        candidate := 'sub-category-' || entry.id;
+        n := 2;
+        WHILE EXISTS (SELECT 1 FROM sub_categories WHERE category_id = entry.category_id AND slug = candidate) LOOP
+            candidate := 'sub-category-' || entry.id || '-' || n;
+            n := n + 1;
+        END LOOP;
+
+        UPDATE sub_categories SET slug = candidate WHERE id = entry.id;
+    END LOOP;
+END $$;
+
+CREATE INDEX idx_sub_categories_category_slug ON sub_categories(category_id, slug);
+
+CREATE TABLE slug_redirects (
+    id SERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    scope_id INTEGER NOT NULL DEFAULT 0,
+    slug VARCHAR(160) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_slug_redirects_lookup ON slug_redirects(entity_type, scope_id, slug);
+CREATE INDEX idx_slug_redirects_entity_id ON slug_redirects(entity_id);
+
+CREATE TRIGGER update_slug_redirects_updated_at BEFORE UPDATE ON slug_redirects FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
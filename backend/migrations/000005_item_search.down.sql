@@ -0,0 +1,14 @@
+-- Rollback: item full-text search
+
+DROP INDEX IF EXISTS idx_items_search_vector;
+
+DROP TRIGGER IF EXISTS refresh_category_item_search_vectors ON categories;
+DROP TRIGGER IF EXISTS refresh_sub_category_item_search_vectors ON sub_categories;
+DROP TRIGGER IF EXISTS update_items_search_vector ON items;
+
+DROP FUNCTION IF EXISTS refresh_category_item_search_vectors();
+DROP FUNCTION IF EXISTS refresh_sub_category_item_search_vectors();
+DROP FUNCTION IF EXISTS update_item_search_vector();
+DROP FUNCTION IF EXISTS item_search_vector(TEXT, TEXT, INTEGER);
+
+ALTER TABLE items DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,51 @@
+-- Full-text search over items, weighted by where the words appear
+
+ALTER TABLE items ADD COLUMN search_vector tsvector;
+
+-- Item name (A) and description (B), then its subcategory (C) and category (D) names
+CREATE OR REPLACE FUNCTION item_search_vector(item_name TEXT, item_description TEXT, item_sub_category_id INTEGER)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english', COALESCE(item_name, '')), 'A')
+        || setweight(to_tsvector('english', COALESCE(item_description, '')), 'B')
+        || setweight(to_tsvector('english', COALESCE(sub_categories.name, '')), 'C')
+        || setweight(to_tsvector('english', COALESCE(categories.name, '')), 'D')
+    FROM (SELECT 1) AS one
+    LEFT JOIN sub_categories ON sub_categories.id = item_sub_category_id
+    LEFT JOIN categories ON categories.id = sub_categories.category_id
+$$ LANGUAGE SQL STABLE;
+
+CREATE OR REPLACE FUNCTION update_item_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := item_search_vector(NEW.name, NEW.description, NEW.sub_category_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_items_search_vector BEFORE INSERT OR UPDATE OF name, description, sub_category_id ON items FOR EACH ROW EXECUTE PROCEDURE update_item_search_vector();
+
+-- Renaming a subcategory or category changes the vectors of its items
+CREATE OR REPLACE FUNCTION refresh_sub_category_item_search_vectors()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE items SET search_vector = item_search_vector(items.name, items.description, items.sub_category_id)
+    WHERE items.sub_category_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION refresh_category_item_search_vectors()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE items SET search_vector = item_search_vector(items.name, items.description, items.sub_category_id)
+    WHERE items.sub_category_id IN (SELECT id FROM sub_categories WHERE category_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refresh_sub_category_item_search_vectors AFTER UPDATE OF name ON sub_categories FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name) EXECUTE PROCEDURE refresh_sub_category_item_search_vectors();
+CREATE TRIGGER refresh_category_item_search_vectors AFTER UPDATE OF name ON categories FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name) EXECUTE PROCEDURE refresh_category_item_search_vectors();
+
+UPDATE items SET search_vector = item_search_vector(name, description, sub_category_id);
+
+CREATE INDEX idx_items_search_vector ON items USING GIN (search_vector);
@@ -0,0 +1,6 @@
+-- Rollback: typo-tolerant item search
+
+DROP INDEX IF EXISTS idx_items_description_trgm;
+DROP INDEX IF EXISTS idx_items_name_trgm;
+
+-- The pg_trgm extension is left installed; other database objects may rely on it
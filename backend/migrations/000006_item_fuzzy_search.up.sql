@@ -0,0 +1,6 @@
+-- Typo-tolerant item search using trigram similarity
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_items_name_trgm ON items USING GIN (LOWER(name) gin_trgm_ops);
+CREATE INDEX idx_items_description_trgm ON items USING GIN (LOWER(description) gin_trgm_ops);
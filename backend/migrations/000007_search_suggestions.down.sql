@@ -0,0 +1,7 @@
+-- Rollback: search-as-you-type suggestions
+
+DROP INDEX IF EXISTS idx_categories_name_prefix;
+DROP INDEX IF EXISTS idx_items_name_prefix;
+
+DROP TRIGGER IF EXISTS update_search_queries_updated_at ON search_queries;
+DROP TABLE IF EXISTS search_queries;
@@ -0,0 +1,21 @@
+-- Search-as-you-type suggestions
+
+-- Queries guests searched for that found something, for popular suggestions
+CREATE TABLE search_queries (
+    id SERIAL PRIMARY KEY,
+    query VARCHAR(200) NOT NULL,
+    search_count INTEGER NOT NULL DEFAULT 0,
+    last_result_count INTEGER NOT NULL DEFAULT 0,
+    last_searched_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_search_queries_query ON search_queries(query);
+CREATE INDEX idx_search_queries_prefix ON search_queries(query varchar_pattern_ops);
+
+CREATE TRIGGER update_search_queries_updated_at BEFORE UPDATE ON search_queries FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+
+-- Prefix lookups on names
+CREATE INDEX idx_items_name_prefix ON items(LOWER(name) varchar_pattern_ops);
+CREATE INDEX idx_categories_name_prefix ON categories(LOWER(name) varchar_pattern_ops);
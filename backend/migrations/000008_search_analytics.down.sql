@@ -0,0 +1,4 @@
+-- Rollback: search analytics
+
+DROP TABLE IF EXISTS search_clicks;
+DROP TABLE IF EXISTS search_events;
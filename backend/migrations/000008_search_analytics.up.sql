@@ -0,0 +1,25 @@
+-- Search analytics: one row per search and per result clicked. No client
+-- details are stored.
+
+CREATE TABLE search_events (
+    id SERIAL PRIMARY KEY,
+    query VARCHAR(200) NOT NULL,
+    result_count INTEGER NOT NULL DEFAULT 0,
+    fuzzy BOOLEAN NOT NULL DEFAULT false,
+    filters JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_search_events_created_at ON search_events(created_at);
+CREATE INDEX idx_search_events_query ON search_events(query);
+
+CREATE TABLE search_clicks (
+    id SERIAL PRIMARY KEY,
+    search_event_id INTEGER NOT NULL REFERENCES search_events(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_search_clicks_search_event_id ON search_clicks(search_event_id);
+CREATE INDEX idx_search_clicks_item_id ON search_clicks(item_id);
@@ -0,0 +1,7 @@
+-- Rollback: search synonyms and stop words
+
+DROP TRIGGER IF EXISTS update_search_stop_words_updated_at ON search_stop_words;
+DROP TRIGGER IF EXISTS update_search_synonyms_updated_at ON search_synonyms;
+
+DROP TABLE IF EXISTS search_stop_words;
+DROP TABLE IF EXISTS search_synonyms;
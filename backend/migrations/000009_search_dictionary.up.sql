@@ -0,0 +1,24 @@
+-- Admin-managed search synonyms and stop words, per locale
+
+CREATE TABLE search_synonyms (
+    id SERIAL PRIMARY KEY,
+    locale VARCHAR(10) NOT NULL DEFAULT 'en',
+    terms JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_search_synonyms_locale ON search_synonyms(locale);
+
+CREATE TABLE search_stop_words (
+    id SERIAL PRIMARY KEY,
+    locale VARCHAR(10) NOT NULL DEFAULT 'en',
+    word VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_search_stop_words_locale_word ON search_stop_words(locale, word);
+
+CREATE TRIGGER update_search_synonyms_updated_at BEFORE UPDATE ON search_synonyms FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_search_stop_words_updated_at BEFORE UPDATE ON search_stop_words FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Rollback: keyset pagination
+
+DROP INDEX IF EXISTS idx_items_display_order_id;
+DROP INDEX IF EXISTS idx_sub_categories_display_order_id;
+DROP INDEX IF EXISTS idx_categories_display_order_id;
@@ -0,0 +1,5 @@
+-- Keyset pagination walks list endpoints in (display_order, id) order
+
+CREATE INDEX idx_categories_display_order_id ON categories(display_order, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_sub_categories_display_order_id ON sub_categories(display_order, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_items_display_order_id ON items(display_order, id) WHERE deleted_at IS NULL;
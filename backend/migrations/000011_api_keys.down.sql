@@ -0,0 +1,6 @@
+-- Rollback: API keys and their usage
+
+DROP TRIGGER IF EXISTS update_api_keys_updated_at ON api_keys;
+
+DROP TABLE IF EXISTS api_key_usages;
+DROP TABLE IF EXISTS api_keys;
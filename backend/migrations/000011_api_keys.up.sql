@@ -0,0 +1,32 @@
+-- API keys for partners and signage screens, with their daily usage per endpoint
+
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scope VARCHAR(20) NOT NULL DEFAULT 'menu',
+    allowed_origins JSONB NOT NULL DEFAULT '[]',
+    rate_limit INTEGER NOT NULL DEFAULT 0,
+    daily_quota INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_api_keys_scope CHECK (scope IN ('menu', 'admin'))
+);
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys(key_hash);
+
+CREATE TABLE api_key_usages (
+    api_key_id INTEGER NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, day, method, route)
+);
+
+CREATE INDEX idx_api_key_usages_day ON api_key_usages(day);
+
+CREATE TRIGGER update_api_keys_updated_at BEFORE UPDATE ON api_keys FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
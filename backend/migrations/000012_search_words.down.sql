@@ -0,0 +1,11 @@
+-- Rollback: dictionary of the words used in menu names
+
+DROP TRIGGER IF EXISTS update_categories_search_words ON categories;
+DROP TRIGGER IF EXISTS update_sub_categories_search_words ON sub_categories;
+DROP TRIGGER IF EXISTS update_items_search_words ON items;
+
+DROP FUNCTION IF EXISTS update_search_words();
+DROP FUNCTION IF EXISTS count_search_words(TEXT, INTEGER);
+DROP FUNCTION IF EXISTS name_words(TEXT);
+
+DROP TABLE IF EXISTS search_words;
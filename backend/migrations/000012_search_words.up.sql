@@ -0,0 +1,67 @@
+-- Dictionary of the words used in menu names, for "did you mean" suggestions
+
+CREATE TABLE search_words (
+    word TEXT PRIMARY KEY,
+    occurrences INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_search_words_word_trgm ON search_words USING GIN (word gin_trgm_ops);
+
+-- The distinct words of a name worth suggesting
+CREATE OR REPLACE FUNCTION name_words(name TEXT) RETURNS SETOF TEXT AS $$
+    SELECT DISTINCT word FROM regexp_split_to_table(LOWER(COALESCE(name, '')), '[^[:alnum:]]+') AS word
+    WHERE LENGTH(word) > 2
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Adds delta to the occurrences of each word of name, forgetting words no
+-- name uses any more
+CREATE OR REPLACE FUNCTION count_search_words(name TEXT, delta INTEGER) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO search_words (word, occurrences)
+    SELECT word, delta FROM name_words(name) AS word
+    ON CONFLICT (word) DO UPDATE SET occurrences = search_words.occurrences + EXCLUDED.occurrences;
+
+    DELETE FROM search_words WHERE occurrences <= 0 AND word IN (SELECT name_words(name));
+END;
+$$ LANGUAGE plpgsql;
+
+-- Soft-deleted rows don't count, so deleting and restoring one moves its words
+-- out of and back into the dictionary
+CREATE OR REPLACE FUNCTION update_search_words() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.name IS NOT DISTINCT FROM NEW.name AND (OLD.deleted_at IS NULL) = (NEW.deleted_at IS NULL) THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF OLD.deleted_at IS NULL THEN
+            PERFORM count_search_words(OLD.name, -1);
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.deleted_at IS NULL THEN
+            PERFORM count_search_words(NEW.name, 1);
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_items_search_words AFTER INSERT OR DELETE OR UPDATE OF name, deleted_at ON items
+FOR EACH ROW EXECUTE PROCEDURE update_search_words();
+CREATE TRIGGER update_sub_categories_search_words AFTER INSERT OR DELETE OR UPDATE OF name, deleted_at ON sub_categories
+FOR EACH ROW EXECUTE PROCEDURE update_search_words();
+CREATE TRIGGER update_categories_search_words AFTER INSERT OR DELETE OR UPDATE OF name, deleted_at ON categories
+FOR EACH ROW EXECUTE PROCEDURE update_search_words();
+
+INSERT INTO search_words (word, occurrences)
+SELECT word, COUNT(*) FROM (
+    SELECT name_words(name) AS word FROM items WHERE deleted_at IS NULL
+    UNION ALL SELECT name_words(name) FROM sub_categories WHERE deleted_at IS NULL
+    UNION ALL SELECT name_words(name) FROM categories WHERE deleted_at IS NULL
+) AS words
+GROUP BY word;
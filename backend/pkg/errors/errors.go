@@ -17,6 +17,7 @@ const (
 	InternalError      AppErrorType = "INTERNAL_ERROR"
 	BadRequestError    AppErrorType = "BAD_REQUEST_ERROR"
 	ServiceUnavailable AppErrorType = "SERVICE_UNAVAILABLE_ERROR"
+	RateLimitError     AppErrorType = "RATE_LIMIT_ERROR"
 )
 
 type AppError struct {
@@ -61,6 +62,8 @@ func NewAppError(errorType AppErrorType, message, details string, err error) *Ap
 		appErr.StatusCode = http.StatusBadRequest
 	case ServiceUnavailable:
 		appErr.StatusCode = http.StatusServiceUnavailable
+	case RateLimitError:
+		appErr.StatusCode = http.StatusTooManyRequests
 	default:
 		appErr.StatusCode = http.StatusInternalServerError
 	}
@@ -100,6 +103,10 @@ func NewServiceUnavailableError(message string) *AppError {
 	return NewAppError(ServiceUnavailable, message, "", nil)
 }
 
+func NewRateLimitError(message, details string) *AppError {
+	return NewAppError(RateLimitError, message, details, nil)
+}
+
 func IsAppError(err error) (*AppError, bool) {
 	var appErr *AppError
 	if errors.As(err, &appErr) {
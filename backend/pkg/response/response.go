@@ -25,6 +25,7 @@ type APIError struct {
 type Meta struct {
 	Pagination *entities.Pagination `json:"pagination,omitempty"`
 	Total      int64                `json:"total,omitempty"`
+	NextCursor string               `json:"next_cursor,omitempty"`
 	RequestID  string               `json:"request_id,omitempty"`
 	Timestamp  string               `json:"timestamp,omitempty"`
 }
@@ -48,6 +49,20 @@ func SuccessWithPagination(c *gin.Context, data interface{}, pagination *entitie
 	})
 }
 
+// SuccessWithCursor responds with a cursor page, passing the cursor of the
+// next page and the total when it was counted in the meta.
+func SuccessWithCursor(c *gin.Context, data interface{}, pagination *entities.Pagination) {
+	meta := getMeta(c)
+	meta.Total = pagination.Total
+	meta.NextCursor = pagination.NextCursor
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 func SuccessWithMeta(c *gin.Context, data interface{}, meta *Meta) {
 	if meta == nil {
 		meta = getMeta(c)
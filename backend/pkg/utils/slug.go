@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// transliterations maps letters that do not decompose into an ASCII letter
+// plus combining marks. Arabic short vowels are combining marks and drop out.
+var transliterations = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+
+	// Arabic and Persian
+	'ء': "", 'ا': "a", 'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h", 'خ': "kh",
+	'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "d",
+	'ط': "t", 'ظ': "z", 'ع': "a", 'غ': "gh", 'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l",
+	'م': "m", 'ن': "n", 'ه': "h", 'و': "w", 'ي': "y", 'ى': "a", 'ة': "a", 'ٱ': "a",
+	'پ': "p", 'چ': "ch", 'ژ': "zh", 'ک': "k", 'گ': "g", 'ی': "y",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Transliterate lowercases s and spells it in ASCII where it knows how.
+// Accents are stripped, Arabic and Cyrillic letters are romanized and
+// anything else becomes a space.
+func Transliterate(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining accents and vowel marks
+		case r >= '٠' && r <= '٩':
+			b.WriteRune('0' + r - '٠')
+		case r >= '۰' && r <= '۹':
+			b.WriteRune('0' + r - '۰')
+		default:
+			if latin, ok := transliterations[r]; ok {
+				b.WriteString(latin)
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// GenerateSlug creates a URL-friendly slug from a string
+func GenerateSlug(s string) string {
+	slug := slugSeparators.ReplaceAllString(Transliterate(s), "-")
+	return strings.Trim(slug, "-")
+}
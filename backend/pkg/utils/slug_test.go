@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Soup", "soup"},
+		{"Crème Brûlée", "creme brulee"},
+		{"Straße", "strasse"},
+		{"Smørrebrød", "smorrebrod"},
+		{"شاورما", "shawrma"},
+		{"كُنافة", "knafa"},
+		{"Борщ", "borshch"},
+		{"٣ ۴", "3 4"},
+		{"寿司", "  "},
+	}
+
+	for _, tt := range tests {
+		if got := Transliterate(tt.in); got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSlug(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Soup", "soup"},
+		{"Soup 12", "soup-12"},
+		{"  Fish & Chips!  ", "fish-chips"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Chicken Shawarma (شاورما دجاج)", "chicken-shawarma-shawrma-djaj"},
+		{"Борщ", "borshch"},
+		{"寿司", ""},
+		{"---", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := GenerateSlug(tt.in); got != tt.want {
+			t.Errorf("GenerateSlug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,26 +1,9 @@
 package utils
 
 import (
-	"regexp"
 	"strconv"
-	"strings"
 )
 
-// GenerateSlug creates a URL-friendly slug from a string
-func GenerateSlug(s string) string {
-	// Convert to lowercase
-	slug := strings.ToLower(s)
-	
-	// Replace spaces and special characters with hyphens
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	slug = reg.ReplaceAllString(slug, "-")
-	
-	// Remove leading and trailing hyphens
-	slug = strings.Trim(slug, "-")
-	
-	return slug
-}
-
 // BoolPtr returns a pointer to a bool value
 func BoolPtr(b bool) *bool {
 	return &b